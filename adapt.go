@@ -0,0 +1,105 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"github.com/pkg/errors"
+	"reflect"
+)
+
+/**
+Registration of the adapter from one bean type to another, resolved after scan
+*/
+type adaptation struct {
+	from    interface{}
+	to      interface{}
+	adapter interface{}
+}
+
+/**
+Wraps every bean of the current context implementing 'from' by the adapter function, so the results satisfy injections of 'to'.
+Types are given by reflect.Type or by nil pointer like (*OldService)(nil), adapter must be the function 'func(A) B'.
+Helps to migrate beans to the new interface incrementally.
+
+Example:
+	glue.New(
+		&legacyStorage{},
+		glue.Adapt((*OldStorage)(nil), (*Storage)(nil), func(old OldStorage) Storage {
+			return &storageAdapter{old}
+		}),
+	)
+*/
+func Adapt(from, to, adapter interface{}) interface{} {
+	return &adaptation{from: from, to: to, adapter: adapter}
+}
+
+/**
+Returns class by reflect.Type or by nil pointer, where pointer to interface means the interface itself
+*/
+func classOf(v interface{}) reflect.Type {
+	if typ, ok := v.(reflect.Type); ok {
+		return typ
+	}
+	typ := reflect.TypeOf(v)
+	if typ != nil && typ.Kind() == reflect.Ptr && typ.Elem().Kind() == reflect.Interface {
+		return typ.Elem()
+	}
+	return typ
+}
+
+/**
+Validates adaptation and returns classes with adapter function
+*/
+func (t *adaptation) resolve() (from, to reflect.Type, fn reflect.Value, err error) {
+	from, to = classOf(t.from), classOf(t.to)
+	if from == nil || to == nil {
+		return nil, nil, fn, errors.New("adapter types must be not nil")
+	}
+	if to.Kind() != reflect.Ptr && to.Kind() != reflect.Interface {
+		return nil, nil, fn, errors.Errorf("adapter can produce ptr or interface, but type is '%v'", to)
+	}
+	fn = reflect.ValueOf(t.adapter)
+	if !fn.IsValid() || fn.Kind() == reflect.Func && fn.IsNil() {
+		return nil, nil, fn, errors.Errorf("adapter must be not nil function 'func(%v) %v'", from, to)
+	}
+	ft := fn.Type()
+	if ft.Kind() != reflect.Func || ft.NumIn() != 1 || ft.NumOut() != 1 || !from.AssignableTo(ft.In(0)) || !ft.Out(0).AssignableTo(to) {
+		return nil, nil, fn, errors.Errorf("adapter must be 'func(%v) %v', but was '%v'", from, to, ft)
+	}
+	return from, to, fn, nil
+}
+
+/**
+Factory bean producing adapted object from the source bean
+*/
+type adapterFactory struct {
+	source *bean
+	to     reflect.Type
+	fn     reflect.Value
+}
+
+func (t *adapterFactory) Object() (interface{}, error) {
+	if t.source.obj == nil {
+		return nil, errors.Errorf("source bean '%s' of the adapter to '%v' is not constructed", t.source.name, t.to)
+	}
+	out := t.fn.Call([]reflect.Value{reflect.ValueOf(t.source.obj)})[0]
+	if !out.IsValid() || (out.Kind() == reflect.Ptr || out.Kind() == reflect.Interface) && out.IsNil() {
+		return nil, errors.Errorf("adapter of source bean '%s' to '%v' returned nil", t.source.name, t.to)
+	}
+	return out.Interface(), nil
+}
+
+func (t *adapterFactory) ObjectType() reflect.Type {
+	return t.to
+}
+
+func (t *adapterFactory) ObjectName() string {
+	return ""
+}
+
+func (t *adapterFactory) Singleton() bool {
+	return true
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type OldStorage interface {
+	Fetch(key string) string
+}
+
+type KeyStorage interface {
+	Lookup(key string) (string, bool)
+}
+
+type legacyStorage struct {
+	constructed bool
+}
+
+func (t *legacyStorage) PostConstruct() error {
+	t.constructed = true
+	return nil
+}
+
+func (t *legacyStorage) Fetch(key string) string {
+	return "legacy:" + key
+}
+
+type storageAdapter struct {
+	old OldStorage
+}
+
+func (t *storageAdapter) Lookup(key string) (string, bool) {
+	return t.old.Fetch(key), true
+}
+
+type keyStorageHolder struct {
+	Storage KeyStorage `inject`
+}
+
+func TestAdapt(t *testing.T) {
+
+	legacy := &legacyStorage{}
+	holder := &keyStorageHolder{}
+
+	ctx, err := glue.New(
+		legacy,
+		glue.Adapt((*OldStorage)(nil), (*KeyStorage)(nil), func(old OldStorage) KeyStorage {
+			require.True(t, old.(*legacyStorage).constructed)
+			return &storageAdapter{old}
+		}),
+		holder,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.NotNil(t, holder.Storage)
+	value, ok := holder.Storage.Lookup("a")
+	require.True(t, ok)
+	require.Equal(t, "legacy:a", value)
+}
+
+func TestAdaptInvalid(t *testing.T) {
+
+	_, err := glue.New(
+		&legacyStorage{},
+		glue.Adapt((*OldStorage)(nil), (*KeyStorage)(nil), func(old OldStorage) string {
+			return ""
+		}),
+	)
+	require.Error(t, err)
+
+	_, err = glue.New(
+		&legacyStorage{},
+		glue.Adapt((*OldStorage)(nil), (*KeyStorage)(nil), nil),
+	)
+	require.Error(t, err)
+
+	_, err = glue.New(
+		&legacyStorage{},
+		glue.Adapt((*OldStorage)(nil), (*KeyStorage)(nil), (func(OldStorage) KeyStorage)(nil)),
+	)
+	require.Error(t, err)
+}
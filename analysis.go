@@ -0,0 +1,190 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"reflect"
+	"sort"
+)
+
+/**
+Kind of the suspicious pattern found by static analysis of beans on scan
+*/
+type WarningCode string
+
+const (
+	/**
+	Optional injection in to the field of the type that can not be nil, so missing implementation can not be detected
+	*/
+	WarnOptionalNotNillable WarningCode = "optional-not-nillable"
+
+	/**
+	Lazy injection in to the bean with PostConstruct, the dependency could be not constructed yet when PostConstruct uses it
+	*/
+	WarnLazyPostConstruct WarningCode = "lazy-post-construct"
+
+	/**
+	Injection level beyond the depth of the context hierarchy, it works as the union of all contexts
+	*/
+	WarnLevelBeyondDepth WarningCode = "level-beyond-depth"
+
+	/**
+	Field with 'value' tag is unexported, the property can not be injected in to it
+	*/
+	WarnValueUnexported WarningCode = "value-unexported"
+)
+
+/**
+Suspicious pattern found in the bean on scan
+*/
+type Warning struct {
+
+	/**
+	Kind of the pattern
+	*/
+	Code WarningCode
+
+	/**
+	Name of the bean
+	*/
+	Bean string
+
+	/**
+	Class of the bean
+	*/
+	Class reflect.Type
+
+	/**
+	Field of the bean
+	*/
+	Field string
+
+	/**
+	Human readable description
+	*/
+	Message string
+}
+
+func (t Warning) String() string {
+	return fmt.Sprintf("%s: field '%s' of bean '%s' with type '%v', %s", t.Code, t.Field, t.Bean, t.Class, t.Message)
+}
+
+/**
+Option of the context that collects warnings of static analysis of beans on scan instead of writing them to the glue.Warnings logger.
+The option is inherited by contexts created by Extend.
+
+Example:
+	var found []glue.Warning
+	ctx, err := glue.New(
+		glue.OnWarning(func(w glue.Warning) {
+			found = append(found, w)
+		}),
+		&service{},
+	)
+*/
+type OnWarning func(w Warning)
+
+func (t OnWarning) applyOption(ctx *context) {
+	ctx.onWarning = t
+}
+
+/**
+Option of the context that fails creation of the context if static analysis of beans found warnings, warnings are reported as usual before.
+Useful in tests and CI to keep wiring clean. The option is inherited by contexts created by Extend.
+
+Example:
+	ctx, err := glue.New(
+		glue.Strict{},
+		&service{},
+	)
+*/
+type Strict struct {
+}
+
+func (Strict) applyOption(ctx *context) {
+	ctx.strict = true
+}
+
+/**
+Reports warnings of static analysis of beans of the current context in order of bean names and fields,
+returns error with warnings for the strict context
+*/
+func (t *context) analyzeBeans() error {
+	var list []Warning
+	depth := 0
+	for c := t; c != nil; c = c.parent {
+		depth++
+	}
+	t.eachBean(func(b Bean) bool {
+		if impl, ok := b.(*bean); ok && impl.beenFactory == nil && impl.obj != nil {
+			list = append(list, impl.analyze(depth)...)
+		}
+		return true
+	})
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].Bean != list[j].Bean {
+			return list[i].Bean < list[j].Bean
+		}
+		return list[i].Field < list[j].Field
+	})
+	for _, w := range list {
+		if t.onWarning != nil {
+			t.onWarning(w)
+		} else {
+			warnf("%v\n", w)
+		}
+	}
+	if t.strict && len(list) > 0 {
+		return errors.Errorf("strict context has %d warnings of static analysis, %v", len(list), list)
+	}
+	return nil
+}
+
+/**
+Finds suspicious patterns in injection and property fields of the bean
+*/
+func (t *bean) analyze(depth int) []Warning {
+	var list []Warning
+	warn := func(code WarningCode, field string, format string, args ...interface{}) {
+		list = append(list, Warning{
+			Code:    code,
+			Bean:    t.name,
+			Class:   t.beanDef.classPtr,
+			Field:   field,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+	_, hasConstructor := t.obj.(InitializingBean)
+	for _, def := range t.beanDef.fields {
+		field := def.class.Field(def.fieldNum)
+		if def.optional && !nillable(field.Type) {
+			warn(WarnOptionalNotNillable, def.fieldName, "optional field of type '%v' can not be nil", field.Type)
+		}
+		if def.lazy && hasConstructor {
+			warn(WarnLazyPostConstruct, def.fieldName, "lazy dependency could be not constructed yet in PostConstruct")
+		}
+		if def.level > depth {
+			warn(WarnLevelBeyondDepth, def.fieldName, "level %d is beyond depth %d of the context hierarchy", def.level, depth)
+		}
+	}
+	for _, def := range t.beanDef.properties {
+		if def.class.Field(def.fieldNum).PkgPath != "" {
+			warn(WarnValueUnexported, def.fieldName, "property '%s' can not be injected in to unexported field", def.propertyName)
+		}
+	}
+	return list
+}
+
+func nillable(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Func, reflect.Slice, reflect.Map, reflect.Chan:
+		return true
+	default:
+		return false
+	}
+}
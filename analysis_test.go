@@ -0,0 +1,71 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type analysisStorage struct {
+}
+
+type analysisService struct {
+	Storage *analysisStorage `inject:"lazy"`
+	Retries int              `inject:"optional"`
+	Parent  *analysisStorage `inject:"level=3"`
+	name    string           `value:"service.name,default=svc"`
+}
+
+func (t *analysisService) PostConstruct() error {
+	return nil
+}
+
+type analysisClean struct {
+	Storage *analysisStorage `inject:"optional,level=2"`
+	Name    string           `value:"service.name,default=svc"`
+}
+
+func TestOnWarning(t *testing.T) {
+
+	var found []glue.Warning
+	ctx, err := glue.New(
+		glue.OnWarning(func(w glue.Warning) {
+			found = append(found, w)
+		}),
+		&analysisStorage{},
+		&analysisService{},
+	)
+	require.Error(t, err)
+	require.Nil(t, ctx)
+
+	require.Equal(t, 4, len(found), found)
+	codes := make(map[string]glue.WarningCode)
+	for _, w := range found {
+		require.Equal(t, "*glue_test.analysisService", w.Bean)
+		codes[w.Field] = w.Code
+	}
+	require.Equal(t, glue.WarnLazyPostConstruct, codes["Storage"])
+	require.Equal(t, glue.WarnOptionalNotNillable, codes["Retries"])
+	require.Equal(t, glue.WarnLevelBeyondDepth, codes["Parent"])
+	require.Equal(t, glue.WarnValueUnexported, codes["name"])
+
+	found = nil
+	parent, err := glue.New(
+		glue.OnWarning(func(w glue.Warning) {
+			found = append(found, w)
+		}),
+		&analysisStorage{},
+	)
+	require.NoError(t, err)
+	defer parent.Close()
+
+	child, err := parent.Extend(&analysisClean{})
+	require.NoError(t, err)
+	defer child.Close()
+	require.Empty(t, found)
+}
@@ -6,7 +6,9 @@
 package glue
 
 import (
+	stdcontext "context"
 	"io"
+	"log"
 	"net/http"
 	"os"
 	"reflect"
@@ -73,7 +75,7 @@ type Bean interface {
 	FactoryBean() (Bean, bool)
 
 	/**
-	Re-initialize bean by calling Destroy method if bean implements DisposableBean interface
+	Re-initialize bean by calling Destroy method if bean implements DisposableBean interface (or DestroyWithReason with ReloadReason)
 	and then calls PostConstruct method if bean implements InitializingBean interface
 
 	Reload can not be used for beans created by FactoryBean, since the instances are already injected
@@ -85,12 +87,93 @@ type Bean interface {
 	*/
 	Lifecycle() BeanLifecycle
 
+	/**
+	Registers callback invoked on every lifecycle transition of the bean in the goroutine that made the transition.
+	Callback must not block, for example it could notify monitoring when connection pool bean enters BeanDestroying.
+	*/
+	OnLifecycle(cb func(from, to BeanLifecycle))
+
+	/**
+	Returns properties injected in to the fields of the bean with 'value' tag and the sources of their values.
+	Bindings are collected on construction of the bean, empty for not constructed beans.
+	*/
+	Properties() []PropertyBinding
+
+	/**
+	Returns beans of the context and its children with fields injected by this bean, lazy injections included.
+	For the factory bean returns beans injected by objects produced by it, so when the backing resource of the factory fails
+	it shows which services are impacted. Beans of closed contexts are removed.
+	*/
+	Consumers() []Bean
+
 	/**
 	Returns information about the bean
 	*/
 	String() string
 }
 
+/**
+Binding of the bean field with 'value' tag to the property
+*/
+type PropertyBinding struct {
+
+	/**
+	Full property key including prefix, map fields have key 'prefix.*'
+	*/
+	Key string `json:"key"`
+
+	/**
+	Field name, nested fields are separated by dot
+	*/
+	Field string `json:"field"`
+
+	/**
+	Default value from the 'value' tag
+	*/
+	Default string `json:"default,omitempty"`
+
+	/**
+	Source of the current value: path of PropertySource, 'map', 'runtime', type of PropertyResolver or 'default'
+	*/
+	Source string `json:"source"`
+
+	/**
+	Property is sensitive, value must not be displayed
+	*/
+	Sensitive bool `json:"sensitive,omitempty"`
+}
+
+/**
+Step of the property resolution returned by Properties.Explain
+*/
+type ResolutionStep struct {
+
+	/**
+	Description of the resolver, String() if implemented or the type
+	*/
+	Resolver string `json:"resolver"`
+
+	/**
+	Priority of the resolver
+	*/
+	Priority int `json:"priority"`
+
+	/**
+	Resolver has the property
+	*/
+	Matched bool `json:"matched"`
+
+	/**
+	Value of the resolver, redacted for sensitive properties
+	*/
+	Value string `json:"value,omitempty"`
+
+	/**
+	Value of the resolver is returned by Get, other matched resolvers are shadowed by it
+	*/
+	Winner bool `json:"winner,omitempty"`
+}
+
 var ContextClass = reflect.TypeOf((*Context)(nil)).Elem()
 
 type Context interface {
@@ -100,7 +183,9 @@ type Context interface {
 	Parent() (Context, bool)
 
 	/**
-	New new context with additional beans based on current one
+	New new context with additional beans based on current one.
+	Safe to call concurrently with other Extend, Bean, Lookup and Inject calls on this context,
+	lookups from children are cached in the registry of the context under its lock.
 	*/
 	Extend(scan ...interface{}) (Context, error)
 
@@ -110,12 +195,68 @@ type Context interface {
 	Children() []ChildContext
 
 	/**
-	Destroy all beans that implement interface DisposableBean.
+	Returns descriptions of beans of the current context with docs of DocumentedBean, dependencies and property bindings.
+	Use glue.WriteMarkdown to generate the report.
+	*/
+	Describe() []BeanDescription
+
+	/**
+	Returns the satisfaction matrix of interfaces: beans implementing each of them in the context hierarchy
+	and whether the interface is bound, i.e. requested by an injection or lookup. Beans of unbound interfaces are not found by Lookup by name,
+	the report suggests the placeholder to add. Does not construct lazy beans and does not bind interfaces.
+
+	Example:
+		report := ctx.Satisfaction(UserServiceClass, StorageClass)
+		if len(report.Unbound()) > 0 || len(report.Unimplemented()) > 0 {
+			log.Printf("wiring issues:\n%v", report)
+		}
+	*/
+	Satisfaction(ifaces ...reflect.Type) SatisfactionReport
+
+	/**
+	Returns factory beans producing objects of the type (or implementing the interface), singletons and non-singletons,
+	from the current context first and then from parents. Objects created by direct Object calls are not managed by the context,
+	this is the way to create additional prototypes explicitly without keeping references to factory structs.
+	*/
+	Factories(objType reflect.Type) []FactoryBean
+
+	/**
+	Returns statistics of beans of the current context.
+	Approximate retained memory per bean is estimated only if glue.MemoryAnalyzer option is in the scan list.
+	*/
+	Stats() ContextStats
+
+	/**
+	Saves the state of initialized glue.StatefulBean beans of the current context to files in the directory, one file per bean named by the bean.
+	Each file is replaced atomically, so the previous checkpoint of the bean stays intact on failure.
+	*/
+	Checkpoint(dir string) error
+
+	/**
+	Loads the state of initialized glue.StatefulBean beans of the current context from files saved by Checkpoint,
+	beans without the file in the directory are skipped. Usually called right after creation of the context.
+	*/
+	Restore(dir string) error
+
+	/**
+	Returns role of the child context that created this context, inherited by Extend, empty for the root context
+	*/
+	Role() string
+
+	/**
+	Destroy all beans that implement interface DisposableBean or DisposableWithReasonBean.
 	*/
 	Close() error
 
 	/**
-	Get list of all registered instances on creation of context with scope 'core'
+	Closes the context like Close, but stops waiting for child contexts, leader election, consumers and beans when ctx is done:
+	beans not reached yet are skipped and the call in progress is abandoned.
+	Returns CloseCanceledError listing beans not destroyed if ctx is done before the close completes.
+	*/
+	CloseWithContext(ctx stdcontext.Context) error
+
+	/**
+	Get list of all registered instances on creation of context with scope 'core', sorted by type name
 	*/
 	Core() []reflect.Type
 
@@ -154,6 +295,26 @@ type Context interface {
 	*/
 	Lookup(name string, level int) []Bean
 
+	/**
+	Lookup registered beans in context by name pattern, glob by default or regular expression with 'regexp:' prefix.
+	Beans are ordered by names, invalid pattern returns empty list.
+
+	Example:
+		beans := ctx.LookupPattern("storage.*", glue.DefaultLevel)
+		beans := ctx.LookupPattern("regexp:^storage\\.(users|orders)$", glue.DefaultLevel)
+
+	Same as Lookup it finds in parent context only beans that were used in injection inside ctx context.
+	*/
+	LookupPattern(pattern string, level int) []Bean
+
+	/**
+	Iterates all beans of the context including beans produced by factories, stops when fn returns false.
+	Level 0 and 1 iterate the current context, level 2 adds the parent and so on, level -1 iterates all contexts.
+	Beans are ordered by context starting from the current one, then by type name and registration order.
+	Lazy beans are not constructed by the iteration.
+	*/
+	EachBean(level int, fn func(Bean) bool)
+
 	/**
 	Inject fields in to the obj on runtime that is not part of core context.
 	Does not add a new bean in to the core context, so this method is only for one-time use with scope 'runtime'.
@@ -167,9 +328,34 @@ type Context interface {
 		rp := new(requestProcessor)
 		ctx.Inject(rp)
 		required.NotNil(t, rp.UserService)
+
+	Properties of 'value' tags are bound the same way as for beans in context including nested structures and maps.
+	Use glue.WithPrefix wrapper to read properties under the prefix.
 	*/
 	Inject(interface{}) error
 
+	/**
+	Injects fields of multiple runtime objects in one pass, candidates are looked up once per type of the object,
+	so it is cheaper than separate Inject calls when hydrating batches of request or message handlers.
+	Objects could be wrapped by glue.WithPrefix, returns the first error with the position of the object.
+
+	Example:
+		handlers := []interface{}{&orderHandler{}, &orderHandler{}, &paymentHandler{}}
+		err := ctx.InjectAll(handlers...)
+	*/
+	InjectAll(objs ...interface{}) error
+
+	/**
+	Returns view of the context that logs to the given logger instead of the global glue.Verbose one.
+	Inject and InjectAll of the view trace candidates and result of each field,
+	Extend of the view logs creation of the child context and the child keeps the logger.
+	Useful to trace a single problematic runtime injection without verbose for the whole process.
+
+	Example:
+		err := ctx.WithVerbose(log.Default()).Inject(handler)
+	*/
+	WithVerbose(log *log.Logger) Context
+
 	/**
 	Returns resource and true if found
 	Path should come with ResourceSource name prefix.
@@ -182,6 +368,64 @@ type Context interface {
 	 */
 	Properties() Properties
 
+	/**
+	Forces resolution and construction of beans of listed types including not yet produced factory beans and warms lookup caches.
+	Call it before traffic is admitted, so the first request would not include factory construction.
+	Uses default level of lookup.
+	 */
+	Prime(types ...reflect.Type) error
+
+	/**
+	Replaces the single bean of the current context implementing the interface by the new implementation.
+	The new implementation is injected and constructed first, then fields of beans of this context referencing the old bean are repointed and the old bean is destroyed.
+	Fields of beans of contexts created by Extend (child contexts included) are repointed as well, pointer and interface fields are updated atomically.
+	The new implementation of other concrete type is refused if any field or slice element holds the old bean, since interface value could not be updated atomically,
+	only map fields accepting the new type are replaced as a whole.
+	Beans injected by Inject method in to objects not tracked by the context are refused as well.
+	 */
+	Swap(iface reflect.Type, newImpl interface{}) error
+
+	/**
+	Re-evaluates properties of initialized beans of the current context and re-initializes beans whose injected properties changed since creation
+	together with beans depending on them: Destroy (or DestroyWithReason with RefreshReason) is called for dependents first,
+	then properties are injected again and PostConstruct is called in dependency order.
+	Beans produced by FactoryBean and beans of parent contexts are not refreshed, running consumers can not be refreshed.
+	If PostConstruct of the bean fails, the rest of beans are re-initialized anyway and errors are returned together,
+	the failed bean is not destroyed on close and is re-initialized by the next Refresh.
+	 */
+	Refresh() error
+
+	/**
+	Reads property sources of the current context again and loads their values, then calls Refresh and notifies RefreshableBean beans with changed keys.
+	Values of keys removed from the sources and values set in runtime are kept, unless the sources set them again.
+	 */
+	ReloadProperties() error
+
+	/**
+	Moves the initialized bean of the current context to the parent context, so subsequent children reuse it.
+	Supports expensive shared resources created on demand by the first child that needs them.
+	The bean must not depend on beans of the current context, the parent destroys it on close.
+	 */
+	Promote(b Bean) error
+
+	/**
+	Writes snapshot of bean definitions of this context to be used by glue.NewFromSnapshot on the next start.
+	 */
+	Snapshot(w io.Writer) error
+
+	/**
+	Releases objects produced by singleton factory beans of this context that are referenced only by glue.WeakRef handles.
+	Released objects are destroyed with ReleaseWeakReason and produced again on the next WeakRef.Get call.
+	Returns number of released beans.
+	 */
+	ReleaseWeak() int
+
+	/**
+	Returns every injection decision made on creation of the context including chosen and rejected candidates.
+	Use AuditLog.WriteJSON to export it.
+	 */
+	AuditLog() AuditLog
+
 	/**
 	Returns information about context
 	*/
@@ -303,6 +547,84 @@ type DisposableBean interface {
 	Destroy() error
 }
 
+/**
+Reason of destroying the bean passed to DisposableWithReasonBean
+*/
+type CloseReason int32
+
+const (
+	/**
+	Context closed by the application
+	*/
+	ShutdownReason CloseReason = iota
+
+	/**
+	Context failed on PostConstruct and closes already initialized beans
+	*/
+	StartupFailureReason
+
+	/**
+	Parent context was closed
+	*/
+	ParentCloseReason
+
+	/**
+	Bean was replaced by Context.Swap
+	*/
+	SwapReason
+
+	/**
+	Bean is re-initialized by Bean.Reload
+	*/
+	ReloadReason
+
+	/**
+	Bean is re-initialized by Context.Refresh since its properties or dependencies changed
+	*/
+	RefreshReason
+
+	/**
+	Object referenced only by weak handles is released by Context.ReleaseWeak
+	*/
+	ReleaseWeakReason
+)
+
+func (t CloseReason) String() string {
+	switch t {
+	case ShutdownReason:
+		return "Shutdown"
+	case StartupFailureReason:
+		return "StartupFailure"
+	case ParentCloseReason:
+		return "ParentClose"
+	case SwapReason:
+		return "Swap"
+	case ReloadReason:
+		return "Reload"
+	case RefreshReason:
+		return "Refresh"
+	case ReleaseWeakReason:
+		return "ReleaseWeak"
+	default:
+		return "Unknown"
+	}
+}
+
+/**
+This interface uses to select objects that need to know why they are destroyed, for example to flush on shutdown and abort on startup failure.
+If the bean implements both interfaces, only DestroyWithReason is called.
+*/
+var DisposableWithReasonBeanClass = reflect.TypeOf((*DisposableWithReasonBean)(nil)).Elem()
+
+type DisposableWithReasonBean interface {
+
+	/**
+	During close context would be called for each bean in the core with the reason of closing.
+	*/
+
+	DestroyWithReason(reason CloseReason) error
+}
+
 /**
 This interface used to collect all beans with similar type in map, where the name is the key
 */
@@ -329,6 +651,42 @@ type OrderedBean interface {
 	BeanOrder() int
 }
 
+/**
+Priority bands of bean order, use offsets from them to assemble middleware-like chains predictably.
+Injection field with tag `inject:"ordered"` requires all candidates to implement OrderedBean interface.
+*/
+const (
+	OrderFirst   = -1 << 20
+	OrderDefault = 0
+	OrderLast    = 1 << 20
+)
+
+/**
+This interface used to place the bean in collections and on construction after the beans of listed types
+*/
+var OrderedAfterClass = reflect.TypeOf((*OrderedAfter)(nil)).Elem()
+
+type OrderedAfter interface {
+
+	/**
+	Returns pointer or interface types of beans that must go before the current bean
+	*/
+	BeanAfter() []reflect.Type
+}
+
+/**
+This interface used to place the bean in collections and on construction before the beans of listed types
+*/
+var OrderedBeforeClass = reflect.TypeOf((*OrderedBefore)(nil)).Elem()
+
+type OrderedBefore interface {
+
+	/**
+	Returns pointer or interface types of beans that must go after the current bean
+	*/
+	BeanBefore() []reflect.Type
+}
+
 /**
 	Resource source is using to add bind resources in to the context
  */
@@ -395,6 +753,21 @@ type PropertyResolver interface {
 
 }
 
+/**
+	Property Interceptor wraps the resolver chain of Properties like a middleware.
+	Could be used to implement caching, auditing of property access or masking of secret values.
+ */
+var PropertyInterceptorClass = reflect.TypeOf((*PropertyInterceptor)(nil)).Elem()
+
+type PropertyInterceptor interface {
+
+	/**
+	Intercepts property resolution, call next to continue the chain
+	 */
+	Around(key string, next func(string) (string, bool)) (string, bool)
+
+}
+
 /**
 Use this bean to parse properties from file and place in context.
 Merge properties from multiple PropertySource files in to one Properties bean.
@@ -415,8 +788,25 @@ type Properties interface {
 	Register additional property resolver. It would be sorted by priority.
 	 */
 	Register(PropertyResolver)
+
+	/**
+	Removes the registered property resolver, returns true if it was registered.
+	 */
+	Unregister(PropertyResolver) bool
 	PropertyResolvers() []PropertyResolver
 
+	/**
+	Lists resolvers consulted for the key in priority order, whether they matched and which one won.
+	Interceptors are not applied, so the result shows the raw resolver chain.
+	 */
+	Explain(key string) []ResolutionStep
+
+	/**
+	Register property interceptor. The first registered interceptor is the outermost one.
+	 */
+	Intercept(PropertyInterceptor)
+	PropertyInterceptors() []PropertyInterceptor
+
 	/**
 	Loads properties from map
 	 */
@@ -447,13 +837,25 @@ type Properties interface {
 	 */
 	Extend(parent Properties)
 
+	/**
+	Returns live view of properties scoped to the prefix with keys relative to it.
+	The dot is appended to the prefix if missing, so Sub("db") and Sub("db.") are the same.
+	 */
+	Sub(prefix string) Properties
+
+	/**
+	Applies the overlay of properties with the highest priority until pop is called, later overlays shadow earlier ones.
+	The base store is not modified, so tests and canary toggles could change configuration temporarily.
+	 */
+	Push(overlay map[string]string) (pop func())
+
 	/**
 	Gets length of the properties
 	 */
 	Len() int
 
 	/**
-	Gets all keys associated with properties
+	Gets all keys associated with properties in sorted order
 	 */
 	Keys() []string
 
@@ -483,6 +885,16 @@ type Properties interface {
 	GetDuration(key string, def time.Duration) time.Duration
 	GetFileMode(key string, def os.FileMode) os.FileMode
 
+	/**
+	Marks properties as sensitive, their values would be redacted in Dump, verbose logs and error messages
+	 */
+	MarkSensitive(keys ...string)
+
+	/**
+	Checks if property is sensitive in current or parent properties
+	 */
+	IsSensitive(key string) bool
+
 	// properties conversion error handler
 	GetErrorHandler() func(string, error)
 	SetErrorHandler(onError func(string, error))
@@ -0,0 +1,167 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+/**
+Injection record describes the single injection decision made during creation of the context.
+*/
+type InjectionRecord struct {
+
+	/**
+	Name of the bean where injection happened
+	*/
+	Bean string `json:"bean"`
+
+	/**
+	Class of the bean where injection happened
+	*/
+	Class string `json:"class"`
+
+	/**
+	Field name of the injection
+	*/
+	Field string `json:"field"`
+
+	/**
+	Type of the field, for collections the element type
+	*/
+	FieldType string `json:"fieldType"`
+
+	/**
+	Qualifier requested by 'bean=' attribute
+	*/
+	Qualifier string `json:"qualifier,omitempty"`
+
+	/**
+	Lookup level applied to candidates
+	*/
+	Level int `json:"level"`
+
+	/**
+	Optional injection
+	*/
+	Optional bool `json:"optional,omitempty"`
+
+	/**
+	Injection was disabled by the boolean property in 'when' attribute
+	*/
+	Disabled bool `json:"disabled,omitempty"`
+
+	/**
+	Names of the beans selected for injection
+	*/
+	Chosen []string `json:"chosen,omitempty"`
+
+	/**
+	Candidates that were visible, but rejected
+	*/
+	Rejected []RejectedCandidate `json:"rejected,omitempty"`
+
+	/**
+	Injection error if happened
+	*/
+	Error string `json:"error,omitempty"`
+}
+
+type RejectedCandidate struct {
+
+	/**
+	Name of the rejected bean
+	*/
+	Bean string `json:"bean"`
+
+	/**
+	Human readable reason of the rejection
+	*/
+	Reason string `json:"reason"`
+}
+
+/**
+Audit log of all injection decisions made during creation of the context in the order they happened.
+*/
+type AuditLog []*InjectionRecord
+
+/**
+Writes audit log as JSON array to the output stream
+*/
+func (t AuditLog) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(t)
+}
+
+func newInjectionRecord(b *bean, def *injectionDef) *InjectionRecord {
+	return &InjectionRecord{
+		Bean:      b.name,
+		Class:     b.beanDef.classPtr.String(),
+		Field:     def.fieldName,
+		FieldType: def.fieldType.String(),
+		Qualifier: def.qualifier,
+		Level:     def.level,
+		Optional:  def.optional,
+	}
+}
+
+func (t *InjectionRecord) choose(list []*bean) {
+	for _, b := range list {
+		t.Chosen = append(t.Chosen, b.name)
+	}
+}
+
+func (t *InjectionRecord) reject(b *bean, format string, args ...interface{}) {
+	t.Rejected = append(t.Rejected, RejectedCandidate{
+		Bean:   b.name,
+		Reason: fmt.Sprintf(format, args...),
+	})
+}
+
+func (t *InjectionRecord) fail(err error) error {
+	if err != nil {
+		t.Error = err.Error()
+	}
+	return err
+}
+
+/**
+Records candidates that were dropped by the lookup level
+*/
+func (t *InjectionRecord) rejectLevel(deep []beanlist, selected []*bean) {
+	visible := make(map[*bean]bool)
+	for _, b := range selected {
+		visible[b] = true
+	}
+	for _, entry := range deep {
+		for _, b := range entry.list {
+			if !visible[b] {
+				t.reject(b, "found on context level %d, but injection level is %d", entry.level, t.Level)
+			}
+		}
+	}
+}
+
+/**
+Records candidates that were dropped by the qualifier
+*/
+func (t *InjectionRecord) rejectQualifier(list, filtered []*bean) {
+	if len(list) == len(filtered) {
+		return
+	}
+	visible := make(map[*bean]bool)
+	for _, b := range filtered {
+		visible[b] = true
+	}
+	for _, b := range list {
+		if !visible[b] {
+			t.reject(b, "bean name '%s' does not match qualifier '%s'", b.name, t.Qualifier)
+		}
+	}
+}
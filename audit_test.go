@@ -0,0 +1,86 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type auditHolder struct {
+	FirstService FirstService `inject:"bean=*glue_test.firstServiceImpl"`
+	BeanA        *beanA       `inject:"optional"`
+}
+
+func TestAuditLog(t *testing.T) {
+
+	ctx, err := glue.New(
+		&firstServiceImpl{testing: t},
+		&firstService2Impl{testing: t},
+		&auditHolder{},
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	log := ctx.AuditLog()
+	require.Equal(t, 2, len(log))
+
+	var service, optional *glue.InjectionRecord
+	for _, record := range log {
+		switch record.Field {
+		case "FirstService":
+			service = record
+		case "BeanA":
+			optional = record
+		}
+	}
+
+	require.NotNil(t, service)
+	require.Equal(t, "*glue_test.auditHolder", service.Bean)
+	require.Equal(t, []string{"*glue_test.firstServiceImpl"}, service.Chosen)
+	require.Equal(t, 1, len(service.Rejected))
+	require.Equal(t, "*glue_test.firstService2Impl", service.Rejected[0].Bean)
+	require.Contains(t, service.Rejected[0].Reason, "qualifier")
+
+	require.NotNil(t, optional)
+	require.True(t, optional.Optional)
+	require.Empty(t, optional.Chosen)
+	require.Empty(t, optional.Error)
+
+	var buf bytes.Buffer
+	require.NoError(t, log.WriteJSON(&buf))
+
+	var exported []map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &exported))
+	require.Equal(t, 2, len(exported))
+}
+
+func TestAuditLogParentLevel(t *testing.T) {
+
+	parent, err := glue.New(
+		&firstServiceImpl{testing: t},
+	)
+	require.NoError(t, err)
+	defer parent.Close()
+
+	child, err := parent.Extend(
+		&firstService2Impl{testing: t},
+		&struct {
+			FirstService FirstService `inject:"level=1"`
+		}{},
+	)
+	require.NoError(t, err)
+	defer child.Close()
+
+	log := child.AuditLog()
+	require.Equal(t, 1, len(log))
+	require.Equal(t, []string{"*glue_test.firstService2Impl"}, log[0].Chosen)
+	require.Equal(t, 1, len(log[0].Rejected))
+	require.Contains(t, log[0].Rejected[0].Reason, "level")
+}
@@ -12,10 +12,56 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"unsafe"
 )
 
-const DefaultLevel = 0
+const (
+	/**
+	Look in the current context, if not found then look in the parent context and so on
+	*/
+	DefaultLevel = 0
+	/**
+	Look only in the current context
+	*/
+	LocalLevel = 1
+	/**
+	Look in the current context in union with the parent context
+	*/
+	ParentsLevel = 2
+	/**
+	Look in union of all contexts
+	*/
+	AllLevel = -1
+)
+
+/**
+Symbolic names of levels in 'inject' tag
+*/
+var levelNames = map[string]int{
+	"default": DefaultLevel,
+	"local":   LocalLevel,
+	"parents": ParentsLevel,
+	"all":     AllLevel,
+}
+
+/**
+Parse level of 'inject' tag given by symbolic name or by number
+*/
+func parseLevel(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if level, ok := levelNames[s]; ok {
+		return level, nil
+	}
+	level, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, errors.Errorf("invalid level '%s', expected number or one of default, local, parents, all", s)
+	}
+	if level < AllLevel {
+		return 0, errors.Errorf("invalid level %d, expected -1 or greater", level)
+	}
+	return level, nil
+}
 
 type beanDef struct {
 	/**
@@ -59,6 +105,47 @@ type bean struct {
 	ordered bool
 	order   int
 
+	/**
+	Bean is preferred among multiple candidates for the single field
+	*/
+	primary bool
+
+	/**
+	Relative order of the bean, types of beans that must go before and after the current one
+	*/
+	after  []reflect.Type
+	before []reflect.Type
+
+	/**
+	Sequence number of registration, keeps independent beans in the order of scan
+	*/
+	seq uint64
+
+	/**
+	Name of the group of beans set by glue.Group
+	*/
+	group string
+
+	/**
+	Prefix of properties injected by 'value' tags
+	*/
+	propertyPrefix string
+
+	/**
+	Bean constructs on first lookup or injection need
+	*/
+	lazyInit bool
+
+	/**
+	Bean produced by factory was injected by strong reference in to other bean
+	*/
+	injected bool
+
+	/**
+	Bean was injected in to object by runtime injection, those fields can not be repointed by Swap
+	*/
+	runtimeInjected int32
+
 	/**
 	Factory of the bean if exist
 	*/
@@ -103,6 +190,35 @@ type bean struct {
 	Constructor mutex for the bean
 	*/
 	ctorMu sync.Mutex
+
+	/**
+	Callbacks on lifecycle transitions of the bean
+	*/
+	listeners   []func(from, to BeanLifecycle)
+	listenersMu sync.Mutex
+
+	/**
+	Bindings of 'value' fields collected on construction
+	*/
+	bindings atomic.Value // value is []PropertyBinding
+
+	/**
+	Beans injected with the current one or with objects produced by it if it is a factory, and beans injected in to the current one
+	*/
+	consumers   []*bean
+	consumersMu sync.Mutex
+	consumes    []*bean
+
+	/**
+	Bean completed PostConstruct (or has no one), consumer bean was started, accessed atomically
+	*/
+	postConstructed int32
+	started         int32
+
+	/**
+	Bean is tracked in disposables of the context
+	*/
+	disposable bool
 }
 
 type beanlist struct {
@@ -158,28 +274,65 @@ func (t *bean) Reload() error {
 	t.ctorMu.Lock()
 	defer t.ctorMu.Unlock()
 
-	t.lifecycle = BeanDestroying
-	if dis, ok := t.obj.(DisposableBean); ok {
-		if err := dis.Destroy(); err != nil {
-			return err
-		}
+	t.setLifecycle(BeanDestroying)
+	if err := destroyObject(t.obj, ReloadReason); err != nil {
+		return err
 	}
-	t.lifecycle = BeanConstructing
+	t.setLifecycle(BeanConstructing)
 	if t.beenFactory != nil {
 		return errors.Errorf("bean '%s' was created by factory bean '%v and can not be reloaded", t.name, t.beenFactory.factoryClassPtr)
 	} else {
 		if init, ok := t.obj.(InitializingBean); ok {
+			atomic.StoreInt32(&t.postConstructed, 0)
 			if err := init.PostConstruct(); err != nil {
 				return err
 			}
 		}
 	}
-	t.lifecycle = BeanInitialized
+	atomic.StoreInt32(&t.postConstructed, 1)
+	t.setLifecycle(BeanInitialized)
 	return nil
 }
 
+/**
+Returns properties visible to the bean through the property prefix
+*/
+func (t *bean) scopedProperties(properties Properties) Properties {
+	if t.propertyPrefix != "" {
+		return properties.Sub(t.propertyPrefix)
+	}
+	return properties
+}
+
 func (t *bean) Lifecycle() BeanLifecycle {
-	return t.lifecycle
+	return BeanLifecycle(atomic.LoadInt32((*int32)(&t.lifecycle)))
+}
+
+func (t *bean) OnLifecycle(cb func(from, to BeanLifecycle)) {
+	t.listenersMu.Lock()
+	defer t.listenersMu.Unlock()
+	t.listeners = append(t.listeners, cb)
+}
+
+func (t *bean) Properties() []PropertyBinding {
+	list, _ := t.bindings.Load().([]PropertyBinding)
+	return append([]PropertyBinding(nil), list...)
+}
+
+/**
+Change lifecycle of the bean and notify listeners about transition
+*/
+func (t *bean) setLifecycle(to BeanLifecycle) {
+	from := BeanLifecycle(atomic.SwapInt32((*int32)(&t.lifecycle), int32(to)))
+	if from == to {
+		return
+	}
+	t.listenersMu.Lock()
+	listeners := t.listeners
+	t.listenersMu.Unlock()
+	for _, cb := range listeners {
+		cb(from, to)
+	}
 }
 
 /**
@@ -217,6 +370,26 @@ type factory struct {
 	Created bean instances by this factory
 	*/
 	instances []*bean
+
+	/**
+	Name of instances produced after the first one by non-singleton factory
+	*/
+	elemName string
+
+	/**
+	Guards instances on concurrent runtime injections
+	*/
+	mu sync.Mutex
+
+	/**
+	Circuit breaker of runtime calls for RetryFactoryBean
+	*/
+	circuit factoryCircuit
+
+	/**
+	Pool of objects if factory implements PoolingFactoryBean
+	*/
+	pool *objectPool
 }
 
 func (t *factory) String() string {
@@ -224,8 +397,11 @@ func (t *factory) String() string {
 }
 
 func (t *factory) ctor() (*bean, bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	var b *bean
-	var singleton bool
+	var singleton, appended bool
 
 	if len(t.instances) == 0 {
 		return nil, false, errors.Errorf("internal: element bean collection is empty for factory '%v'", t.factoryClassPtr)
@@ -244,21 +420,25 @@ func (t *factory) ctor() (*bean, bool, error) {
 		} else {
 			// append next element, since it is not a singleton
 			b = &bean{
-				name:        t.instances[0].beanDef.classPtr.String(),
+				name:        t.elemName,
 				beenFactory: t.instances[0].beenFactory,
 				beanDef:     t.instances[0].beanDef,
 			}
 			t.instances = append(t.instances, b)
+			appended = true
 		}
 	}
 
 	obj, err := t.factoryBean.Object()
 	if err != nil {
+		if appended {
+			t.instances = t.instances[:len(t.instances)-1]
+		}
 		return nil, false, errors.Errorf("factory bean '%v' failed to create bean '%v', %v", t.factoryClassPtr, t.factoryBean.ObjectType(), err)
 	}
 
 	b.obj = obj
-	b.lifecycle = BeanInitialized
+	b.setLifecycle(BeanInitialized)
 	if namedBean, ok := obj.(NamedBean); ok {
 		b.name = namedBean.BeanName()
 	}
@@ -285,6 +465,13 @@ type factoryDependency struct {
 Investigate bean by using reflection
 */
 func investigate(obj interface{}, classPtr reflect.Type) (*bean, error) {
+	return investigateWith(obj, classPtr, nil)
+}
+
+/**
+Investigate the object, injection fields and properties are taken from the restored definition if it is not nil
+*/
+func investigateWith(obj interface{}, classPtr reflect.Type, restored *beanDef) (*bean, error) {
 	var fields []*injectionDef
 	var properties []*propInjectionDef
 	var anonymousFields []reflect.Type
@@ -322,44 +509,17 @@ func investigate(obj interface{}, classPtr reflect.Type) (*bean, error) {
 			}
 		}
 
+		if restored != nil {
+			continue
+		}
+
 		if valueTag, hasValueTag := field.Tag.Lookup("value"); hasValueTag {
 			if field.Anonymous {
 				return nil, errors.Errorf("injection to anonymous field '%s' in '%v' is not allowed", field.Name, classPtr)
 			}
-			var propertyName string
-			var defaultValue string
-			var layout string
-			pairs := strings.Split(valueTag, ",")
-			for i, pair := range pairs {
-				p := strings.TrimSpace(pair)
-				if i == 0 {
-					// property name
-					propertyName = p
-					continue
-				}
-				kv := strings.SplitN(p, "=", 2)
-				switch strings.TrimSpace(kv[0]) {
-				case "default":
-					if len(kv) > 1 {
-						defaultValue = strings.TrimSpace(kv[1])
-					}
-				case "layout":
-					if len(kv) > 1 {
-						layout = strings.TrimSpace(kv[1])
-					}
-				}
-			}
-			if propertyName == "" {
-				return nil, errors.Errorf("empty property name in field '%s' with type '%v' on position %d in %v with 'value' tag", field.Name, field.Type, j, classPtr)
-			}
-			def := &propInjectionDef{
-				class:     class,
-				fieldNum:  j,
-				fieldName: field.Name,
-				fieldType: field.Type,
-				propertyName: propertyName,
-				defaultValue: defaultValue,
-				layout: layout,
+			def, err := investigateProperty(class, j, field, valueTag, make(map[reflect.Type]bool))
+			if err != nil {
+				return nil, err
 			}
 			properties = append(properties, def)
 			continue
@@ -373,6 +533,14 @@ func investigate(obj interface{}, classPtr reflect.Type) (*bean, error) {
 			var qualifier string
 			var optional bool
 			var lazy bool
+			var ordered bool
+			var topo bool
+			var when string
+			var also []reflect.Type
+			var excludeSelf bool
+			var exact bool
+			var onDuplicate string
+			var keyCase, keyFunc string
 			level := DefaultLevel
 			if hasInjectTag {
 				pairs := strings.Split(injectTag, ",")
@@ -388,22 +556,82 @@ func investigate(obj interface{}, classPtr reflect.Type) (*bean, error) {
 						optional = true
 					case "lazy":
 						lazy = true
+					case "ordered":
+						ordered = true
+					case "topo":
+						topo = true
+					case "excludeSelf":
+						excludeSelf = true
+					case "exact":
+						exact = true
+					case "keyCase":
+						if len(kv) > 1 {
+							keyCase = strings.TrimSpace(kv[1])
+						}
+					case "keyFunc":
+						if len(kv) > 1 {
+							keyFunc = strings.TrimSpace(kv[1])
+						}
+					case "onDuplicate":
+						if len(kv) > 1 {
+							onDuplicate = strings.TrimSpace(kv[1])
+						}
+						switch onDuplicate {
+						case duplicateFirst, duplicateLast, duplicateError:
+						default:
+							return nil, errors.Errorf("invalid 'onDuplicate' attribute '%s' in field '%s' on position %d in %v with 'inject' tag, expected first, last or error", onDuplicate, field.Name, j, classPtr)
+						}
+					case "also":
+						if len(kv) > 1 {
+							name := strings.TrimSpace(kv[1])
+							iface, ok := lookupInterface(name)
+							if !ok {
+								return nil, errors.Errorf("unknown interface '%s' in 'also' attribute of field '%s' on position %d in %v with 'inject' tag, register it by glue.RegisterInterface", name, field.Name, j, classPtr)
+							}
+							also = append(also, iface)
+						}
+					case "when":
+						if len(kv) > 1 {
+							when = strings.TrimSpace(kv[1])
+						}
 					case "level":
 						if len(kv) > 1 {
-							level, _ = strconv.Atoi(kv[1])
+							var err error
+							if level, err = parseLevel(kv[1]); err != nil {
+								return nil, errors.Errorf("%v in field '%s' with type '%v' on position %d in %v with 'inject' tag", err, field.Name, field.Type, j, classPtr)
+							}
 						}
 					}
 				}
 			}
+			if field.Type.Implements(weakReferenceClass) {
+				fields = append(fields, &injectionDef{
+					class:     class,
+					fieldNum:  j,
+					fieldName: field.Name,
+					fieldType: field.Type,
+					optional:  optional,
+					qualifier: qualifier,
+					when:      when,
+					level:     level,
+					weakType:  newWeakReference(field.Type).weakType(),
+				})
+				continue
+			}
 			kind := field.Type.Kind()
 			fieldType := field.Type
 			var fieldSlice, fieldMap bool
-			switch kind {
-			case reflect.Slice:
+			if exact && (kind != reflect.Slice && kind != reflect.Map || field.Type.Name() == "") {
+				return nil, errors.Errorf("'exact' attribute is allowed only for named slice or map field '%s' on position %d in %v with 'inject' tag", field.Name, j, classPtr)
+			}
+			switch {
+			case exact:
+				// named slice or map type is injected by exact type, for example produced by FactoryBean
+			case kind == reflect.Slice:
 				fieldSlice = true
 				fieldType = field.Type.Elem()
 				kind = fieldType.Kind()
-			case reflect.Map:
+			case kind == reflect.Map:
 				fieldMap = true
 				if field.Type.Key().Kind() != reflect.String {
 					return nil, errors.Errorf("map must have string key to be injected for field type '%v' on position %d in %v with 'inject' tag", field.Type, j, classPtr)
@@ -411,24 +639,39 @@ func investigate(obj interface{}, classPtr reflect.Type) (*bean, error) {
 				fieldType = field.Type.Elem()
 				kind = fieldType.Kind()
 			}
-			if kind != reflect.Ptr && kind != reflect.Interface && kind != reflect.Func {
-				return nil, errors.Errorf("not a pointer, interface or function field type '%v' on position %d in %v with 'inject' tag", field.Type, j, classPtr)
+			if topo && !fieldSlice {
+				return nil, errors.Errorf("'topo' attribute is allowed only for slice field '%s' on position %d in %v with 'inject' tag", field.Name, j, classPtr)
+			}
+			if (onDuplicate != "" || keyCase != "" || keyFunc != "") && !fieldMap {
+				return nil, errors.Errorf("'onDuplicate', 'keyCase' and 'keyFunc' attributes are allowed only for map field '%s' on position %d in %v with 'inject' tag", field.Name, j, classPtr)
 			}
 			def := &injectionDef{
-				class:     class,
-				fieldNum:  j,
-				fieldName: field.Name,
-				fieldType: fieldType,
-				lazy:      lazy,
-				slice:     fieldSlice,
-				table:     fieldMap,
-				optional:  optional,
-				qualifier: qualifier,
-				level:     level,
+				class:       class,
+				fieldNum:    j,
+				fieldName:   field.Name,
+				fieldType:   fieldType,
+				lazy:        lazy,
+				ordered:     ordered,
+				topo:        topo,
+				slice:       fieldSlice,
+				table:       fieldMap,
+				optional:    optional,
+				qualifier:   qualifier,
+				when:        when,
+				also:        also,
+				level:       level,
+				excludeSelf: excludeSelf,
+				onDuplicate: onDuplicate,
+			}
+			if err := def.resolveMapKey(keyCase, keyFunc); err != nil {
+				return nil, errors.Errorf("%v in field '%s' on position %d in %v with 'inject' tag", err, field.Name, j, classPtr)
 			}
 			fields = append(fields, def)
 		}
 	}
+	if restored != nil {
+		fields, properties = restored.fields, restored.properties
+	}
 	name := classPtr.String()
 	var qualifier string
 	if namedBean, ok := obj.(NamedBean); ok {
@@ -441,11 +684,20 @@ func investigate(obj interface{}, classPtr reflect.Type) (*bean, error) {
 		ordered = true
 		order = orderedBean.BeanOrder()
 	}
+	var after, before []reflect.Type
+	if orderedAfter, ok := obj.(OrderedAfter); ok {
+		after = orderedAfter.BeanAfter()
+	}
+	if orderedBefore, ok := obj.(OrderedBefore); ok {
+		before = orderedBefore.BeanBefore()
+	}
 	return &bean{
 		name:     name,
 		qualifier: qualifier,
 		ordered:  ordered,
 		order:    order,
+		after:    after,
+		before:   before,
 		obj:      obj,
 		valuePtr: valuePtr,
 		beanDef: &beanDef{
@@ -458,6 +710,102 @@ func investigate(obj interface{}, classPtr reflect.Type) (*bean, error) {
 	}, nil
 }
 
+/**
+Check if bean is the instance of the pointer type or implements the interface type
+*/
+func (t *bean) matches(typ reflect.Type) bool {
+	if typ.Kind() == reflect.Interface {
+		return t.beanDef.implements(typ)
+	}
+	return t.beanDef.classPtr == typ
+}
+
+/**
+Check if bean implements all interfaces, returns the first missing interface otherwise
+*/
+func (t *bean) implementsAll(ifaces []reflect.Type) (reflect.Type, bool) {
+	for _, iface := range ifaces {
+		if !t.beanDef.classPtr.Implements(iface) {
+			return iface, false
+		}
+	}
+	return nil, true
+}
+
+/**
+Check if bean has order, for not yet produced factory beans check the object type
+*/
+func (t *bean) isOrdered() bool {
+	if t.ordered {
+		return true
+	}
+	return t.beenFactory != nil && t.beanDef.classPtr.Implements(OrderedBeanClass)
+}
+
+/**
+Investigate field with 'value' tag, nested structures are investigated recursively with relative property names
+*/
+func investigateProperty(class reflect.Type, j int, field reflect.StructField, valueTag string, visited map[reflect.Type]bool) (*propInjectionDef, error) {
+	var propertyName string
+	var defaultValue string
+	var layout string
+	var sensitive bool
+	pairs := strings.Split(valueTag, ",")
+	for i, pair := range pairs {
+		p := strings.TrimSpace(pair)
+		if i == 0 {
+			// property name
+			propertyName = p
+			continue
+		}
+		kv := strings.SplitN(p, "=", 2)
+		switch strings.TrimSpace(kv[0]) {
+		case "default":
+			if len(kv) > 1 {
+				defaultValue = strings.TrimSpace(kv[1])
+			}
+		case "layout":
+			if len(kv) > 1 {
+				layout = strings.TrimSpace(kv[1])
+			}
+		case "sensitive":
+			sensitive = true
+		}
+	}
+	if propertyName == "" {
+		return nil, errors.Errorf("empty property name in field '%s' with type '%v' on position %d in %v with 'value' tag", field.Name, field.Type, j, class)
+	}
+	def := &propInjectionDef{
+		class:     class,
+		fieldNum:  j,
+		fieldName: field.Name,
+		fieldType: field.Type,
+		propertyName: propertyName,
+		defaultValue: defaultValue,
+		layout: layout,
+		sensitive: sensitive,
+	}
+	if nestedClass, ok := nestedPropertyClass(field.Type); ok {
+		if visited[nestedClass] {
+			return nil, errors.Errorf("recursive property structure '%v' in field '%s' of %v with 'value' tag", nestedClass, field.Name, class)
+		}
+		visited[nestedClass] = true
+		def.nested = []*propInjectionDef{}
+		for i := 0; i < nestedClass.NumField(); i++ {
+			nestedField := nestedClass.Field(i)
+			if tag, ok := nestedField.Tag.Lookup("value"); ok {
+				nestedDef, err := investigateProperty(nestedClass, i, nestedField, tag, visited)
+				if err != nil {
+					return nil, err
+				}
+				def.nested = append(def.nested, nestedDef)
+			}
+		}
+		delete(visited, nestedClass)
+	}
+	return def, nil
+}
+
 func isSomeoneImplements(iface reflect.Type, list []reflect.Type) bool {
 	for _, el := range list {
 		if el.Implements(iface) {
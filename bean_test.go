@@ -203,3 +203,39 @@ func TestNotFoundSpecificBeanByInterface(t *testing.T) {
 	require.True(t, strings.Contains(err.Error(), "can not find candidates"))
 
 }
+
+var poolBeanClass = reflect.TypeOf((*poolBean)(nil))
+
+type poolBean struct {
+}
+
+func (t *poolBean) PostConstruct() error {
+	return nil
+}
+
+func (t *poolBean) Destroy() error {
+	return nil
+}
+
+func TestBeanOnLifecycle(t *testing.T) {
+
+	ctx, err := glue.New(
+		glue.Lazy(&poolBean{}),
+	)
+	require.NoError(t, err)
+
+	b := ctx.Bean(poolBeanClass, glue.DefaultLevel)
+	require.Equal(t, 1, len(b))
+
+	var transitions []glue.BeanLifecycle
+	b[0].OnLifecycle(func(from, to glue.BeanLifecycle) {
+		transitions = append(transitions, to)
+	})
+
+	require.NoError(t, b[0].Reload())
+	require.Equal(t, []glue.BeanLifecycle{glue.BeanDestroying, glue.BeanConstructing, glue.BeanInitialized}, transitions)
+
+	transitions = nil
+	require.NoError(t, ctx.Close())
+	require.Equal(t, []glue.BeanLifecycle{glue.BeanDestroying, glue.BeanDestroyed}, transitions)
+}
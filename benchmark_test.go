@@ -0,0 +1,101 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"github.com/codeallergy/glue"
+	"testing"
+)
+
+func newBenchmarkContext(b *testing.B) glue.Context {
+	prev := glue.Verbose(nil)
+	b.Cleanup(func() {
+		glue.Verbose(prev)
+	})
+	ctx, err := glue.New(
+		&coreBean{},
+		&implComponent{value: "a", order: 1},
+		&implComponent{value: "b", order: 2},
+		&implElement{value: "c", order: 3},
+	)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return ctx
+}
+
+func BenchmarkBeanByPointer(b *testing.B) {
+	ctx := newBenchmarkContext(b)
+	defer ctx.Close()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ctx.Bean(coreBeanClass, glue.DefaultLevel)
+	}
+}
+
+func BenchmarkBeanByInterface(b *testing.B) {
+	ctx := newBenchmarkContext(b)
+	defer ctx.Close()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ctx.Bean(ComponentClass, glue.DefaultLevel)
+	}
+}
+
+func BenchmarkBeanParallel(b *testing.B) {
+	ctx := newBenchmarkContext(b)
+	defer ctx.Close()
+	child, err := ctx.Extend()
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer child.Close()
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			child.Bean(ComponentClass, glue.AllLevel)
+		}
+	})
+}
+
+func BenchmarkInject(b *testing.B) {
+	ctx := newBenchmarkContext(b)
+	defer ctx.Close()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		holder := &struct {
+			Core       *coreBean   `inject`
+			Components []Component `inject`
+		}{}
+		if err := ctx.Inject(holder); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkInjectAll(b *testing.B) {
+	ctx := newBenchmarkContext(b)
+	defer ctx.Close()
+	type handler struct {
+		Core       *coreBean   `inject`
+		Components []Component `inject`
+	}
+	batch := make([]interface{}, 100)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i += len(batch) {
+		for j := range batch {
+			batch[j] = &handler{}
+		}
+		if err := ctx.InjectAll(batch...); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
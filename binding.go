@@ -0,0 +1,116 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+/**
+Sources of property values reported in PropertyBinding
+*/
+const (
+	/**
+	Property was not found, the default value from 'value' tag is used
+	*/
+	DefaultPropertySource = "default"
+
+	/**
+	Property was set in Properties directly, not by PropertySource
+	*/
+	RuntimePropertySource = "runtime"
+
+	/**
+	Property came from PropertySource with Map
+	*/
+	MapPropertySource = "map"
+
+	/**
+	Property came from the catalogue registered by glue.Defaults
+	*/
+	DefaultsPropertySource = "defaults"
+)
+
+/**
+Remember the property source of keys changed by loading of the source
+*/
+func (t *context) recordOrigins(before map[string]string, origin string) {
+	for key, value := range t.properties.Map() {
+		if prev, ok := before[key]; !ok || prev != value {
+			t.propertyOrigins.Store(key, origin)
+		}
+	}
+}
+
+/**
+Returns where the value of the property comes from: path of PropertySource, 'map', 'runtime', 'defaults', type of PropertyResolver or 'default'
+*/
+func (t *context) propertyOrigin(key string) string {
+	for _, r := range t.properties.PropertyResolvers() {
+		if _, ok := r.GetProperty(key); !ok {
+			continue
+		}
+		for ctx := t; ctx != nil; ctx = ctx.parent {
+			if interface{}(r) == interface{}(ctx.properties) {
+				if origin, ok := ctx.propertyOrigins.Load(key); ok {
+					return origin.(string)
+				}
+				return RuntimePropertySource
+			}
+		}
+		if _, ok := r.(*defaultsResolver); ok {
+			return DefaultsPropertySource
+		}
+		return fmt.Sprintf("%T", r)
+	}
+	return DefaultPropertySource
+}
+
+/**
+Collect bindings of 'value' fields of the bean to property keys with sources of current values
+*/
+func (t *context) propertyBindings(b *bean) []PropertyBinding {
+	prefix := b.propertyPrefix
+	if prefix != "" && !strings.HasSuffix(prefix, ".") {
+		prefix += "."
+	}
+	var list []PropertyBinding
+	for _, def := range b.beanDef.properties {
+		list = t.appendBinding(list, def, prefix, "")
+	}
+	return list
+}
+
+func (t *context) appendBinding(list []PropertyBinding, def *propInjectionDef, prefix, field string) []PropertyBinding {
+	key := prefix + def.propertyName
+	field += def.fieldName
+	if def.nested != nil {
+		for _, nested := range def.nested {
+			list = t.appendBinding(list, nested, key+".", field+".")
+		}
+		return list
+	}
+	binding := PropertyBinding{
+		Key:       key,
+		Field:     field,
+		Default:   def.defaultValue,
+		Sensitive: def.sensitive || t.properties.IsSensitive(key),
+		Source:    DefaultPropertySource,
+	}
+	if isMap(def.fieldType) {
+		binding.Key = key + ".*"
+		keys := collectPropertyKeys(t.properties, key+".")
+		sort.Strings(keys)
+		if len(keys) > 0 {
+			binding.Source = t.propertyOrigin(key + "." + keys[0])
+		}
+	} else {
+		binding.Source = t.propertyOrigin(key)
+	}
+	return append(list, binding)
+}
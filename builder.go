@@ -0,0 +1,115 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"log"
+)
+
+/**
+Fluent builder of the context, alternative to options placed in the scan list of New and Extend.
+
+Example:
+	ctx, err := glue.NewBuilder().
+		Parent(parent).
+		Verbose(log.Default()).
+		Properties(map[string]string{"db.host": "localhost"}).
+		CloseTimeouts(glue.CloseTimeouts{Dispose: 5 * time.Second}).
+		Strict().
+		Scan(&storage{}, &service{}).
+		Build()
+*/
+type Builder struct {
+	parent    Context
+	options   []interface{}
+	overrides map[string]string
+	scan      []interface{}
+}
+
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+/**
+Creates the context by Extend of the parent, New by default
+*/
+func (t *Builder) Parent(parent Context) *Builder {
+	t.parent = parent
+	return t
+}
+
+/**
+Verbose logger of the context, see WithVerbose
+*/
+func (t *Builder) Verbose(log *log.Logger) *Builder {
+	t.options = append(t.options, verboseOption{log: log})
+	return t
+}
+
+/**
+Properties overriding values of all property sources and resolvers of the context, calls are merged
+*/
+func (t *Builder) Properties(overrides map[string]string) *Builder {
+	if t.overrides == nil {
+		t.overrides = make(map[string]string)
+	}
+	for key, value := range overrides {
+		t.overrides[key] = value
+	}
+	return t
+}
+
+/**
+Timeouts of close phases, see CloseTimeouts option
+*/
+func (t *Builder) CloseTimeouts(timeouts CloseTimeouts) *Builder {
+	t.options = append(t.options, timeouts)
+	return t
+}
+
+/**
+Fails creation on warnings of static analysis, see Strict option
+*/
+func (t *Builder) Strict() *Builder {
+	t.options = append(t.options, Strict{})
+	return t
+}
+
+/**
+Any other options of the context like glue.Parallel or glue.LeakDetector
+*/
+func (t *Builder) Option(options ...interface{}) *Builder {
+	t.options = append(t.options, options...)
+	return t
+}
+
+/**
+Beans, property sources, groups and child contexts of the context, calls are appended
+*/
+func (t *Builder) Scan(scan ...interface{}) *Builder {
+	t.scan = append(t.scan, scan...)
+	return t
+}
+
+/**
+Creates the context with options first, then property overrides and scanned objects
+*/
+func (t *Builder) Build() (Context, error) {
+	var scan []interface{}
+	scan = append(scan, t.options...)
+	if len(t.overrides) > 0 {
+		overrides := &overlayResolver{store: make(map[string]string, len(t.overrides)), priority: overridesResolverPriority}
+		for key, value := range t.overrides {
+			overrides.store[key] = value
+		}
+		scan = append(scan, overrides)
+	}
+	scan = append(scan, t.scan...)
+	if t.parent != nil {
+		return t.parent.Extend(scan...)
+	}
+	return New(scan...)
+}
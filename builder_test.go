@@ -0,0 +1,78 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"bytes"
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"log"
+	"testing"
+	"time"
+)
+
+type builderService struct {
+	Host    string `value:"db.host"`
+	Port    int    `value:"db.port"`
+	Timeout string `value:"db.timeout,default=1s"`
+}
+
+type builderLazyService struct {
+	Storage *analysisStorage `inject:"lazy"`
+}
+
+func (t *builderLazyService) PostConstruct() error {
+	return nil
+}
+
+func TestBuilder(t *testing.T) {
+
+	parent, err := glue.New(
+		glue.PropertySource{Map: map[string]interface{}{"db.host": "parent", "db.port": 5432}},
+	)
+	require.NoError(t, err)
+	defer parent.Close()
+
+	var out bytes.Buffer
+	service := &builderService{}
+	ctx, err := glue.NewBuilder().
+		Parent(parent).
+		Verbose(log.New(&out, "", 0)).
+		Properties(map[string]string{"db.host": "override"}).
+		Properties(map[string]string{"db.timeout": "5s"}).
+		CloseTimeouts(glue.CloseTimeouts{Dispose: time.Second}).
+		Strict().
+		Scan(glue.PropertySource{Map: map[string]interface{}{"db.host": "child"}}).
+		Scan(service).
+		Build()
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.Equal(t, "override", service.Host)
+	require.Equal(t, 5432, service.Port)
+	require.Equal(t, "5s", service.Timeout)
+	require.Equal(t, "override", ctx.Properties().GetString("db.host", ""))
+	require.NotEmpty(t, out.String())
+
+	p, ok := ctx.Parent()
+	require.True(t, ok)
+	require.True(t, p == parent)
+
+	ctx, err = glue.NewBuilder().
+		Option(glue.OnWarning(func(w glue.Warning) {})).
+		Scan(&analysisStorage{}, &builderLazyService{}).
+		Build()
+	require.NoError(t, err)
+	ctx.Close()
+
+	_, err = glue.NewBuilder().
+		Option(glue.OnWarning(func(w glue.Warning) {})).
+		Strict().
+		Scan(&analysisStorage{}, &builderLazyService{}).
+		Build()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "strict context has 1 warnings of static analysis")
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+/**
+Build constants injected by the linker, for example
+	go build -ldflags "-X github.com/codeallergy/glue.BuildVersion=1.2.3 -X github.com/codeallergy/glue.BuildCommit=$(git rev-parse HEAD)"
+*/
+var (
+	BuildVersion string
+	BuildCommit  string
+	BuildDate    string
+)
+
+/**
+Property resolver exposing build constants as 'build.version', 'build.commit', 'build.date' and 'build.go.version' properties.
+Constants not injected by the linker are taken from the build information of the binary (module version and VCS stamp).
+Fields of the resolver override package variables, so applications that inject own variables pass them here.
+Priority of the resolver is lower than default one, so properties files override it.
+
+Example:
+	glue.New(
+		&glue.BuildInfoResolver{},
+		&server{},
+	)
+
+	type server struct {
+		Version string `value:"build.version,default=dev"`
+	}
+*/
+type BuildInfoResolver struct {
+	Version string
+	Commit  string
+	Date    string
+}
+
+func (t *BuildInfoResolver) Priority() int {
+	return defaultPropertyResolverPriority - 10
+}
+
+func (t *BuildInfoResolver) GetProperty(key string) (string, bool) {
+	if !strings.HasPrefix(key, "build.") {
+		return "", false
+	}
+	var value string
+	switch key {
+	case "build.version":
+		value = firstNonEmpty(t.Version, BuildVersion, buildSetting("version"))
+	case "build.commit":
+		value = firstNonEmpty(t.Commit, BuildCommit, buildSetting("vcs.revision"))
+	case "build.date":
+		value = firstNonEmpty(t.Date, BuildDate, buildSetting("vcs.time"))
+	case "build.go.version":
+		value = runtime.Version()
+	}
+	return value, value != ""
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+/**
+Returns the setting from the build information of the binary, 'version' is the version of the main module
+*/
+func buildSetting(key string) string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	if key == "version" {
+		if v := info.Main.Version; v != "(devel)" {
+			return v
+		}
+		return ""
+	}
+	for _, s := range info.Settings {
+		if s.Key == key {
+			return s.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,39 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"runtime"
+	"testing"
+)
+
+type buildInfoServer struct {
+	Version   string `value:"build.version,default=dev"`
+	Commit    string `value:"build.commit,default=unknown"`
+	Date      string `value:"build.date,default="`
+	GoVersion string `value:"build.go.version"`
+}
+
+func TestBuildInfoResolver(t *testing.T) {
+
+	version := glue.BuildVersion
+	glue.BuildVersion = "1.2.3"
+	defer func() { glue.BuildVersion = version }()
+
+	server := &buildInfoServer{}
+	ctx, err := glue.New(
+		&glue.BuildInfoResolver{Commit: "abc123"},
+		server,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.Equal(t, "1.2.3", server.Version)
+	require.Equal(t, "abc123", server.Commit)
+	require.Equal(t, runtime.Version(), server.GoVersion)
+}
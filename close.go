@@ -0,0 +1,458 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	stdcontext "context"
+	"fmt"
+	"github.com/pkg/errors"
+	"reflect"
+	"sync/atomic"
+	"time"
+)
+
+/**
+Phases of closing the context, each phase walks beans in reverse initialization order
+*/
+const (
+	/**
+	Beans stop accepting new work, StoppableBean
+	*/
+	StopPhase = "stop"
+
+	/**
+	Beans finish work in progress, DrainableBean
+	*/
+	DrainPhase = "drain"
+
+	/**
+	Beans free resources, DisposableBean and DisposableWithReasonBean
+	*/
+	DisposePhase = "dispose"
+)
+
+/**
+This interface uses to select objects that need to stop accepting new work (close listeners, stop consumers) before drain and dispose phases of close.
+*/
+var StoppableBeanClass = reflect.TypeOf((*StoppableBean)(nil)).Elem()
+
+type StoppableBean interface {
+
+	/**
+	During close context would be called for each bean before Drain and Destroy.
+	*/
+
+	Stop() error
+}
+
+/**
+This interface uses to select objects that need to finish work in progress before dispose phase of close.
+*/
+var DrainableBeanClass = reflect.TypeOf((*DrainableBean)(nil)).Elem()
+
+type DrainableBean interface {
+
+	/**
+	During close context would be called for each bean after Stop and before Destroy, ctx is done when the drain timeout expires.
+	*/
+
+	Drain(ctx stdcontext.Context) error
+}
+
+/**
+Option of the context with timeouts of close phases, applied to each bean in the phase. Zero timeout waits the bean without limit.
+Beans that exceed the timeout are reported by CloseTimeoutError and close continues without waiting for them.
+Beans implementing DisposableBeanWithTimeout override the Dispose timeout.
+
+Example:
+	glue.New(
+		glue.CloseTimeouts{Stop: time.Second, Drain: 30 * time.Second, Dispose: 5 * time.Second},
+		&server{},
+	)
+*/
+type CloseTimeouts struct {
+	Stop    time.Duration
+	Drain   time.Duration
+	Dispose time.Duration
+}
+
+func (t CloseTimeouts) applyOption(ctx *context) {
+	ctx.closeTimeouts = t
+}
+
+/**
+Error returned by Close with beans that exceeded the timeout of the phase
+*/
+type CloseTimeoutError struct {
+	Phase   string
+	Timeout time.Duration
+	Beans   []string
+}
+
+func (t *CloseTimeoutError) Error() string {
+	return fmt.Sprintf("close phase '%s' timeout %v exceeded by beans %v", t.Phase, t.Timeout, t.Beans)
+}
+
+/**
+Error returned by CloseWithContext when the context is done before all beans got closed, lists beans not destroyed.
+Other errors of close are kept in Errors, errors.Is(err, context.DeadlineExceeded) reports the expired deadline.
+*/
+type CloseCanceledError struct {
+
+	/**
+	Error of the context passed to CloseWithContext
+	*/
+	Err error
+
+	/**
+	The first phase of close interrupted by the context
+	*/
+	Phase string
+
+	/**
+	Beans with Destroy not called or not completed
+	*/
+	Beans []string
+
+	/**
+	Other errors of close
+	*/
+	Errors []error
+}
+
+func (t *CloseCanceledError) Error() string {
+	msg := fmt.Sprintf("close phase '%s' canceled, %v, beans not destroyed %v", t.Phase, t.Err, t.Beans)
+	if len(t.Errors) > 0 {
+		msg += fmt.Sprintf(", errors %v", t.Errors)
+	}
+	return msg
+}
+
+func (t *CloseCanceledError) Unwrap() error {
+	return t.Err
+}
+
+/**
+Records the first phase of close interrupted by the context
+*/
+func (t *CloseCanceledError) interrupt(ctx stdcontext.Context, phase string) {
+	if t.Err == nil {
+		t.Err = ctx.Err()
+		t.Phase = phase
+	}
+}
+
+/**
+Adds beans abandoned by close of the child context
+*/
+func (t *CloseCanceledError) merge(child *CloseCanceledError) {
+	if t.Err == nil {
+		t.Err = child.Err
+		t.Phase = child.Phase
+	}
+	t.Beans = append(t.Beans, child.Beans...)
+}
+
+/**
+This interface used by disposable beans that need own limit of time for Destroy (or DestroyWithReason) instead of the Dispose timeout of CloseTimeouts.
+Zero timeout waits the bean without limit.
+*/
+var DisposableBeanWithTimeoutClass = reflect.TypeOf((*DisposableBeanWithTimeout)(nil)).Elem()
+
+type DisposableBeanWithTimeout interface {
+
+	/**
+	Returns the timeout of Destroy of the bean
+	*/
+	DestroyTimeout() time.Duration
+}
+
+/**
+Outcome of the call of the bean in the phase of close
+*/
+type closeOutcome int
+
+const (
+	closeDone closeOutcome = iota
+	closeExceeded
+	closeInterrupted
+)
+
+type closeResult struct {
+	err     error
+	outcome closeOutcome
+	timeout time.Duration
+}
+
+/**
+Runs the phase of close for disposables of the context in reverse initialization order.
+Beans are skipped once the parent context is done, the call of the bean in progress is abandoned, such beans are recorded in canceled.
+With Parallel option independent beans are destroyed concurrently, the bean is destroyed only after all beans depending on it.
+*/
+func (t *context) closePhase(parent stdcontext.Context, phase string, timeout time.Duration, call func(b *bean, ctx stdcontext.Context) error, canceled *CloseCanceledError) []error {
+	var results []closeResult
+	if phase == DisposePhase && t.parallel != nil {
+		results = t.closeConcurrently(parent, phase, timeout, call, t.parallel.Workers)
+	} else {
+		results = make([]closeResult, len(t.disposables))
+		for j := len(t.disposables) - 1; j >= 0; j-- {
+			results[j] = t.closeBean(parent, phase, timeout, t.disposables[j], call)
+		}
+	}
+
+	var listErr []error
+	var timeouts []time.Duration
+	exceeded := make(map[time.Duration][]string)
+	var interrupted []*bean
+	for j := len(t.disposables) - 1; j >= 0; j-- {
+		b, r := t.disposables[j], results[j]
+		switch r.outcome {
+		case closeDone:
+			if r.err != nil {
+				listErr = append(listErr, r.err)
+			}
+		case closeExceeded:
+			if _, ok := exceeded[r.timeout]; !ok {
+				timeouts = append(timeouts, r.timeout)
+			}
+			exceeded[r.timeout] = append(exceeded[r.timeout], b.name)
+		case closeInterrupted:
+			interrupted = append(interrupted, b)
+		}
+	}
+	for _, timeout := range timeouts {
+		listErr = append(listErr, &CloseTimeoutError{Phase: phase, Timeout: timeout, Beans: exceeded[timeout]})
+	}
+	if len(interrupted) > 0 {
+		canceled.interrupt(parent, phase)
+		if phase == DisposePhase {
+			for _, b := range interrupted {
+				switch b.obj.(type) {
+				case DisposableBean, DisposableWithReasonBean:
+					canceled.Beans = append(canceled.Beans, b.name)
+				}
+			}
+		}
+	}
+	return listErr
+}
+
+/**
+Calls the bean in the phase of close within the timeout of the phase or own timeout of Destroy
+*/
+func (t *context) closeBean(parent stdcontext.Context, phase string, timeout time.Duration, b *bean, call func(b *bean, ctx stdcontext.Context) error) closeResult {
+	if phase == DisposePhase {
+		if d, ok := b.obj.(DisposableBeanWithTimeout); ok {
+			timeout = d.DestroyTimeout()
+		}
+	}
+	if parent.Err() != nil {
+		return closeResult{outcome: closeInterrupted}
+	}
+	if timeout <= 0 && parent.Done() == nil {
+		return closeResult{err: call(b, parent)}
+	}
+	ctx, cancel := parent, stdcontext.CancelFunc(func() {})
+	if timeout > 0 {
+		ctx, cancel = stdcontext.WithTimeout(parent, timeout)
+	}
+	defer cancel()
+	ch := make(chan error, 1)
+	go func() {
+		ch <- call(b, ctx)
+	}()
+	select {
+	case err := <-ch:
+		return closeResult{err: err}
+	case <-ctx.Done():
+		if parent.Err() != nil {
+			return closeResult{outcome: closeInterrupted}
+		}
+		if t.logger() != nil {
+			t.logger().Printf("Close phase '%s' timeout %v exceeded by bean '%s' with type '%v'\n", phase, timeout, b.name, b.beanDef.classPtr)
+		}
+		return closeResult{outcome: closeExceeded, timeout: timeout}
+	}
+}
+
+/**
+Calls disposables by workers, the bean is scheduled after all beans depending on it directly or through other beans got closed.
+Results are indexed as disposables.
+*/
+func (t *context) closeConcurrently(parent stdcontext.Context, phase string, timeout time.Duration, call func(b *bean, ctx stdcontext.Context) error, workers int) []closeResult {
+	list := t.disposables
+	n := len(list)
+	results := make([]closeResult, n)
+	if n == 0 {
+		return results
+	}
+
+	/**
+	The bean waits for disposables that depend on it
+	*/
+	index := make(map[*bean]int, n)
+	for i, b := range list {
+		index[b] = i
+	}
+	pending := make([]int, n)
+	next := make([][]int, n)
+	for i, b := range list {
+		visited := make(map[*bean]bool)
+		var walk func(b *bean)
+		walk = func(b *bean) {
+			for _, dep := range disposalDependencies(b) {
+				if visited[dep] {
+					continue
+				}
+				visited[dep] = true
+				if j, ok := index[dep]; ok && j != i {
+					pending[j]++
+					next[i] = append(next[i], j)
+				}
+				walk(dep)
+			}
+		}
+		walk(b)
+	}
+
+	if !acyclicDisposal(pending, next) {
+		for j := n - 1; j >= 0; j-- {
+			results[j] = t.closeBean(parent, phase, timeout, list[j], call)
+		}
+		return results
+	}
+
+	type indexedResult struct {
+		i int
+		r closeResult
+	}
+	ready := make(chan int, n)
+	done := make(chan indexedResult, n)
+	defer close(ready)
+
+	if workers > n {
+		workers = n
+	}
+	for w := 0; w < workers; w++ {
+		go func() {
+			for i := range ready {
+				done <- indexedResult{i: i, r: t.closeBean(parent, phase, timeout, list[i], call)}
+			}
+		}()
+	}
+
+	for i := n - 1; i >= 0; i-- {
+		if pending[i] == 0 {
+			ready <- i
+		}
+	}
+	for closed := 0; closed < n; closed++ {
+		d := <-done
+		results[d.i] = d.r
+		for _, j := range next[d.i] {
+			pending[j]--
+			if pending[j] == 0 {
+				ready <- j
+			}
+		}
+	}
+	return results
+}
+
+/**
+Checks that every disposable could be closed after beans depending on it
+*/
+func acyclicDisposal(pending []int, next [][]int) bool {
+	left := append([]int(nil), pending...)
+	var queue []int
+	for i, p := range left {
+		if p == 0 {
+			queue = append(queue, i)
+		}
+	}
+	sorted := 0
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		sorted++
+		for _, j := range next[i] {
+			left[j]--
+			if left[j] == 0 {
+				queue = append(queue, j)
+			}
+		}
+	}
+	return sorted == len(pending)
+}
+
+/**
+Returns beans the bean depends on, including the factory bean of the produced bean
+*/
+func disposalDependencies(b *bean) []*bean {
+	var list []*bean
+	for _, factoryDep := range b.factoryDependencies {
+		list = append(list, factoryDep.factory.bean)
+	}
+	list = append(list, b.dependencies...)
+	if b.beenFactory != nil {
+		list = append(list, b.beenFactory.bean)
+	}
+	return list
+}
+
+/**
+Reports the phase of close skipped for the bean whose initialization or start did not complete
+*/
+func skipOnClose(b *bean, phase, cause string) {
+	warnf("Skip %s of bean '%s' with type '%v', %s\n", phase, b.name, b.beanDef.classPtr, cause)
+}
+
+/**
+Consumer bean that was never started by the context does not need to stop or drain
+*/
+func notStartedConsumer(b *bean) bool {
+	_, ok := b.obj.(Consumer)
+	return ok && atomic.LoadInt32(&b.started) == 0
+}
+
+func (t *context) stopBean(b *bean, ctx stdcontext.Context) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("stop bean '%s' with type '%v' recovered with error: %v", b.name, b.beanDef.classPtr, r)
+		}
+	}()
+	if notStartedConsumer(b) {
+		if _, ok := b.obj.(StoppableBean); ok && b.Lifecycle() == BeanInitialized {
+			skipOnClose(b, "stop", "consumer was not started")
+		}
+		return nil
+	}
+	if s, ok := b.obj.(StoppableBean); ok && b.Lifecycle() == BeanInitialized {
+		if t.logger() != nil {
+			t.logger().Printf("Stop bean '%s' with type '%v'\n", b.name, b.beanDef.classPtr)
+		}
+		return s.Stop()
+	}
+	return nil
+}
+
+func (t *context) drainBean(b *bean, ctx stdcontext.Context) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("drain bean '%s' with type '%v' recovered with error: %v", b.name, b.beanDef.classPtr, r)
+		}
+	}()
+	if notStartedConsumer(b) {
+		return nil
+	}
+	if d, ok := b.obj.(DrainableBean); ok && b.Lifecycle() == BeanInitialized {
+		if t.logger() != nil {
+			t.logger().Printf("Drain bean '%s' with type '%v'\n", b.name, b.beanDef.classPtr)
+		}
+		return d.Drain(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+/**
+Developer tool that inspects the context of the application built with glue.
+
+Usage:
+	glue describe [-format json|markdown|dot] <binary> [args...]
+
+The binary is started with --glue-describe flag, it must have glue.DescribeOnFlag option in the scan list of the context.
+*/
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/codeallergy/glue"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "glue: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintf(w, "Usage:\n\tglue describe [-format json|markdown|dot] <binary> [args...]\n")
+}
+
+func run(args []string, out io.Writer) error {
+	if len(args) == 0 || args[0] != "describe" {
+		usage(os.Stderr)
+		return fmt.Errorf("unknown command %v", args)
+	}
+	fs := flag.NewFlagSet("describe", flag.ContinueOnError)
+	format := fs.String("format", "markdown", "output format: json, markdown or dot")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		usage(os.Stderr)
+		return fmt.Errorf("binary is not specified")
+	}
+	d, err := describe(fs.Arg(0), fs.Args()[1:])
+	if err != nil {
+		return err
+	}
+	switch *format {
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(d)
+	case "markdown":
+		return writeMarkdown(out, d)
+	case "dot":
+		return writeDot(out, d)
+	default:
+		return fmt.Errorf("unknown format '%s', expected json, markdown or dot", *format)
+	}
+}
+
+/**
+Starts the binary with the describe flag and decodes the description from its output
+*/
+func describe(binary string, args []string) (*glue.ApplicationDescription, error) {
+	var stdout bytes.Buffer
+	cmd := exec.Command(binary, append(args, glue.DescribeFlag)...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("run '%s' error, %v", binary, err)
+	}
+	d := new(glue.ApplicationDescription)
+	if err := json.Unmarshal(stdout.Bytes(), d); err != nil {
+		return nil, fmt.Errorf("'%s' has no glue.DescribeOnFlag option in the context, %v", binary, err)
+	}
+	return d, nil
+}
+
+func writeMarkdown(w io.Writer, d *glue.ApplicationDescription) error {
+	if err := glue.WriteMarkdown(w, d.Beans); err != nil {
+		return err
+	}
+	out := bufio.NewWriter(w)
+	if len(d.Properties) > 0 {
+		fmt.Fprintf(out, "\n# Properties\n\n| Property | Default | Sensitive |\n|---|---|---|\n")
+		for _, p := range d.Properties {
+			fmt.Fprintf(out, "| `%s` | %s | %v |\n", p.Key, strings.ReplaceAll(p.Default, "|", "\\|"), p.Sensitive)
+		}
+	}
+	if len(d.Resources) > 0 {
+		fmt.Fprintf(out, "\n# Resources\n\n")
+		for _, r := range d.Resources {
+			fmt.Fprintf(out, "* `%s`\n", r)
+		}
+	}
+	return out.Flush()
+}
+
+func writeDot(w io.Writer, d *glue.ApplicationDescription) error {
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, "digraph glue {\n")
+	for _, b := range d.Beans {
+		label := b.Name
+		if b.Type != b.Name {
+			label += "\n" + b.Type
+		}
+		fmt.Fprintf(out, "\t%q [label=%q];\n", b.Name, label)
+		for _, dep := range b.Dependencies {
+			fmt.Fprintf(out, "\t%q -> %q;\n", b.Name, dep)
+		}
+	}
+	fmt.Fprintf(out, "}\n")
+	return out.Flush()
+}
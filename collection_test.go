@@ -464,3 +464,203 @@ func TestMapSpecificByInterface(t *testing.T) {
 	require.Equal(t, 1, len(holder.Elements()))
 
 }
+
+type middlewareX struct {
+	name  string
+	order int
+}
+
+func (t *middlewareX) BeanOrder() int {
+	return t.order
+}
+
+type strictHolderX struct {
+	Chain []*middlewareX `inject:"ordered"`
+}
+
+func TestOrderedInjectionWithBands(t *testing.T) {
+
+	holder := &strictHolderX{}
+	ctx, err := glue.New(
+		&middlewareX{name: "last", order: glue.OrderLast},
+		&middlewareX{name: "default", order: glue.OrderDefault},
+		&middlewareX{name: "first", order: glue.OrderFirst},
+		&middlewareX{name: "second", order: glue.OrderFirst + 1},
+		holder,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.Equal(t, 4, len(holder.Chain))
+	require.Equal(t, "first", holder.Chain[0].name)
+	require.Equal(t, "second", holder.Chain[1].name)
+	require.Equal(t, "default", holder.Chain[2].name)
+	require.Equal(t, "last", holder.Chain[3].name)
+}
+
+type strictElementHolderX struct {
+	Array []*elementX `inject:"ordered"`
+}
+
+func TestOrderedInjectionRequiresOrderedBeans(t *testing.T) {
+
+	_, err := glue.New(
+		&elementX{name: "a"},
+		&strictElementHolderX{},
+	)
+	require.Error(t, err)
+	require.True(t, strings.Contains(err.Error(), "does not implement glue.OrderedBean"))
+}
+
+type orderedElementHolder struct {
+	Array []Element `inject:"also=glue.OrderedBean"`
+}
+
+func TestArrayIntersectionByInterface(t *testing.T) {
+
+	holder := &orderedElementHolder{}
+
+	ctx, err := glue.New(
+		&elementImpl{name: "a"},
+		&orderedElementImpl{name: "b"},
+		&orderedElementImpl{name: "c"},
+		holder,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.Equal(t, 2, len(holder.Array))
+	require.Equal(t, "b", holder.Array[0].BeanName())
+	require.Equal(t, "c", holder.Array[1].BeanName())
+
+	_, err = glue.New(
+		&struct {
+			Array []Element `inject:"also=app.Unknown"`
+		}{},
+	)
+	require.Error(t, err)
+	require.True(t, strings.Contains(err.Error(), "glue.RegisterInterface"))
+}
+
+type registryElementImpl struct {
+	Array []Element `inject:"excludeSelf"`
+}
+
+func (t *registryElementImpl) BeanName() string {
+	return "registry"
+}
+
+func TestArrayExcludeSelf(t *testing.T) {
+
+	registry := &registryElementImpl{}
+
+	ctx, err := glue.New(
+		&elementImpl{name: "a"},
+		&elementImpl{name: "b"},
+		registry,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.Equal(t, 2, len(registry.Array))
+	for _, element := range registry.Array {
+		require.NotEqual(t, "registry", element.BeanName())
+	}
+
+	runtime := &registryElementImpl{}
+	require.NoError(t, ctx.Inject(runtime))
+	require.Equal(t, 3, len(runtime.Array))
+}
+
+type duplicateElementImpl struct {
+	name  string
+	value string
+}
+
+func (t *duplicateElementImpl) BeanName() string {
+	return t.name
+}
+
+func TestMapDuplicatesPolicy(t *testing.T) {
+
+	first := &struct {
+		Map map[string]Element `inject:"onDuplicate=first"`
+	}{}
+	last := &struct {
+		Map map[string]Element `inject:"onDuplicate=last"`
+	}{}
+
+	ctx, err := glue.New(
+		&duplicateElementImpl{name: "a", value: "1"},
+		&duplicateElementImpl{name: "a", value: "2"},
+		&duplicateElementImpl{name: "b", value: "3"},
+		first,
+		last,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.Equal(t, 2, len(first.Map))
+	require.Equal(t, "1", first.Map["a"].(*duplicateElementImpl).value)
+	require.Equal(t, 2, len(last.Map))
+	require.Equal(t, "2", last.Map["a"].(*duplicateElementImpl).value)
+
+	_, err = glue.New(
+		&struct {
+			Map map[string]Element `inject:"onDuplicate=error"`
+		}{},
+		&duplicateElementImpl{name: "a"},
+		&duplicateElementImpl{name: "a"},
+	)
+	require.Error(t, err)
+	require.True(t, strings.Contains(err.Error(), "duplicates"))
+
+	_, err = glue.New(
+		&struct {
+			Array []Element `inject:"onDuplicate=first"`
+		}{},
+	)
+	require.Error(t, err)
+}
+
+func TestMapKeyTransform(t *testing.T) {
+
+	glue.RegisterKeyFunc("test.trimHandler", func(name string) string {
+		return strings.TrimSuffix(name, "handler")
+	})
+
+	lower := &struct {
+		Map map[string]Element `inject:"keyCase=lower"`
+	}{}
+	short := &struct {
+		Map map[string]*beanA `inject:"keyCase=short"`
+	}{}
+	custom := &struct {
+		Map map[string]Element `inject:"keyCase=lower,keyFunc=test.trimHandler"`
+	}{}
+
+	ctx, err := glue.New(
+		&elementImpl{name: "UserHandler"},
+		&elementImpl{name: "Order"},
+		&beanA{},
+		lower,
+		short,
+		custom,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.NotNil(t, lower.Map["userhandler"])
+	require.NotNil(t, lower.Map["order"])
+	require.NotNil(t, short.Map["beanA"])
+	require.NotNil(t, custom.Map["user"])
+	require.NotNil(t, custom.Map["order"])
+
+	_, err = glue.New(
+		&struct {
+			Map map[string]Element `inject:"keyFunc=test.unknown"`
+		}{},
+	)
+	require.Error(t, err)
+	require.True(t, strings.Contains(err.Error(), "glue.RegisterKeyFunc"))
+}
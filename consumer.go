@@ -0,0 +1,147 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	stdcontext "context"
+	"github.com/pkg/errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+/**
+Delay before the first restart of the failed consumer, doubled for every next one
+*/
+var ConsumerRestartBackoff = time.Second
+
+/**
+Upper limit of the delay between restarts of the failed consumer
+*/
+var ConsumerMaxRestartBackoff = time.Minute
+
+/**
+This interface used by beans that consume messages (Kafka, NATS, queues) in a loop.
+Context starts each consumer in the supervised goroutine after all beans are constructed,
+restarts it with backoff when Consume returns error or panics, failures are reported as warnings. Ctx is canceled first on close, before the stop phase.
+Consumer finishes if Consume returns nil.
+*/
+var ConsumerClass = reflect.TypeOf((*Consumer)(nil)).Elem()
+
+type Consumer interface {
+
+	/**
+	Consumes messages until ctx is done
+	*/
+	Consume(ctx stdcontext.Context) error
+}
+
+/**
+Running consumers of the context
+*/
+type consumerGroup struct {
+	cancel stdcontext.CancelFunc
+	wg     sync.WaitGroup
+}
+
+/**
+Starts supervised goroutines for initialized consumer beans of the context
+*/
+func (t *context) startConsumers() {
+	var list []*bean
+	t.eachBean(func(b Bean) bool {
+		if impl, ok := b.(*bean); ok && impl.Lifecycle() == BeanInitialized {
+			if _, ok := impl.obj.(Consumer); ok {
+				list = append(list, impl)
+			}
+		}
+		return true
+	})
+	if len(list) == 0 {
+		return
+	}
+	ctx, cancel := stdcontext.WithCancel(stdcontext.Background())
+	group := &consumerGroup{cancel: cancel}
+	for _, b := range list {
+		atomic.StoreInt32(&b.started, 1)
+		group.wg.Add(1)
+		go func(b *bean) {
+			defer group.wg.Done()
+			t.superviseConsumer(ctx, b)
+		}(b)
+	}
+	t.consumers = group
+}
+
+func (t *context) superviseConsumer(ctx stdcontext.Context, b *bean) {
+	consumer := b.obj.(Consumer)
+	backoff := ConsumerRestartBackoff
+	for {
+		if t.logger() != nil {
+			t.logger().Printf("Start consumer '%s' with type '%v'\n", b.name, b.beanDef.classPtr)
+		}
+		started := time.Now()
+		err := runConsumer(ctx, b, consumer)
+		if ctx.Err() != nil || err == nil {
+			return
+		}
+		if time.Since(started) > ConsumerMaxRestartBackoff {
+			backoff = ConsumerRestartBackoff
+		}
+		warnf("Consumer '%s' with type '%v' failed, restart in %v, %v\n", b.name, b.beanDef.classPtr, backoff, err)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return
+		}
+		backoff *= 2
+		if ConsumerMaxRestartBackoff > 0 && backoff > ConsumerMaxRestartBackoff {
+			backoff = ConsumerMaxRestartBackoff
+		}
+	}
+}
+
+func runConsumer(ctx stdcontext.Context, b *bean, consumer Consumer) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("consumer '%s' with type '%v' recovered with error: %v", b.name, b.beanDef.classPtr, r)
+		}
+	}()
+	return consumer.Consume(ctx)
+}
+
+/**
+Cancels consumers of the context and waits them to return, zero timeout waits without limit.
+Consumers are abandoned once ctx is done, that is recorded in canceled.
+*/
+func (t *context) stopConsumers(ctx stdcontext.Context, timeout time.Duration, canceled *CloseCanceledError) []error {
+	group := t.consumers
+	if group == nil {
+		return nil
+	}
+	group.cancel()
+	done := make(chan struct{})
+	go func() {
+		group.wg.Wait()
+		close(done)
+	}()
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	select {
+	case <-done:
+		return nil
+	case <-expired:
+		return []error{errors.Errorf("consumers did not return in %v after cancel", timeout)}
+	case <-ctx.Done():
+		canceled.interrupt(ctx, StopPhase)
+		return nil
+	}
+}
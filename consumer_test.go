@@ -0,0 +1,79 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"log"
+	"sync"
+	"testing"
+	"time"
+)
+
+type queueConsumer struct {
+	mu       sync.Mutex
+	attempts int
+	running  chan struct{}
+	stopped  bool
+	events   []string
+}
+
+func (t *queueConsumer) Consume(ctx context.Context) error {
+	t.mu.Lock()
+	t.attempts++
+	attempt := t.attempts
+	t.mu.Unlock()
+	switch attempt {
+	case 1:
+		return errors.New("broker unavailable")
+	case 2:
+		panic("lost connection")
+	}
+	close(t.running)
+	<-ctx.Done()
+	t.mu.Lock()
+	t.events = append(t.events, "consume")
+	t.mu.Unlock()
+	return ctx.Err()
+}
+
+func (t *queueConsumer) Stop() error {
+	t.mu.Lock()
+	t.events = append(t.events, "stop")
+	t.mu.Unlock()
+	return nil
+}
+
+func TestConsumerSupervision(t *testing.T) {
+
+	backoff := glue.ConsumerRestartBackoff
+	glue.ConsumerRestartBackoff = time.Millisecond
+	defer func() { glue.ConsumerRestartBackoff = backoff }()
+
+	var buf bytes.Buffer
+	prev := glue.Warnings(log.New(&buf, "", 0))
+	defer glue.Warnings(prev)
+
+	consumer := &queueConsumer{running: make(chan struct{})}
+	ctx, err := glue.New(consumer)
+	require.NoError(t, err)
+
+	select {
+	case <-consumer.running:
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "consumer was not restarted")
+	}
+
+	require.NoError(t, ctx.Close())
+	require.Equal(t, 3, consumer.attempts)
+	require.Equal(t, []string{"consume", "stop"}, consumer.events)
+	require.Contains(t, buf.String(), "broker unavailable")
+	require.Contains(t, buf.String(), "lost connection")
+}
@@ -0,0 +1,76 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+/**
+Registers the injection of the bean in to the current one, products of factories are also registered as consumed by the factory bean
+*/
+func (t *bean) consume(impl *bean) {
+	if impl == t {
+		return
+	}
+	t.consumes = append(t.consumes, impl)
+	impl.addConsumer(t)
+	if impl.beenFactory != nil && impl.beenFactory.bean != nil {
+		t.consumes = append(t.consumes, impl.beenFactory.bean)
+		impl.beenFactory.bean.addConsumer(t)
+	}
+}
+
+func (t *bean) addConsumer(b *bean) {
+	t.consumersMu.Lock()
+	defer t.consumersMu.Unlock()
+	for _, c := range t.consumers {
+		if c == b {
+			return
+		}
+	}
+	t.consumers = append(t.consumers, b)
+}
+
+func (t *bean) removeConsumer(b *bean) {
+	t.consumersMu.Lock()
+	defer t.consumersMu.Unlock()
+	t.consumers = removeBean(t.consumers, b)
+}
+
+func (t *bean) Consumers() []Bean {
+	t.consumersMu.Lock()
+	defer t.consumersMu.Unlock()
+	list := make([]Bean, len(t.consumers))
+	for i, b := range t.consumers {
+		list[i] = b
+	}
+	return list
+}
+
+/**
+Moves consumers of the old bean to the new one on swap
+*/
+func (t *bean) moveConsumers(b *bean) {
+	t.consumersMu.Lock()
+	consumers := t.consumers
+	t.consumers = nil
+	t.consumersMu.Unlock()
+	for _, c := range consumers {
+		c.consumes = replaceBean(c.consumes, t, b)
+		b.addConsumer(c)
+	}
+}
+
+/**
+Removes beans of the closed context from consumers of the beans they were injected with, including beans of parents
+*/
+func (t *context) releaseConsumers() {
+	for _, list := range t.coreBeans() {
+		for _, b := range list {
+			for _, impl := range b.consumes {
+				impl.removeConsumer(b)
+			}
+			b.consumes = nil
+		}
+	}
+}
@@ -6,19 +6,31 @@
 package glue
 
 import (
+	stdcontext "context"
 	"fmt"
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v3"
+	"io/ioutil"
+	"log"
 	"reflect"
-	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
+/**
+Deadline of the rollback of the context failed on creation
+*/
 var DefaultCloseTimeout = time.Minute
 
+/**
+Maximum number of property source resources opened and read concurrently on creation of the context
+*/
+var PropertySourceParallelism = 8
+
 type context struct {
 	
 	/**
@@ -33,9 +45,14 @@ type context struct {
 
 	/**
 		All instances scanned during creation of context.
-	    No modifications on runtime allowed.
+	    The map is immutable after creation, runtime modifications replace it as a whole under coreMu.
+	*/
+	core atomic.Value // value is map[reflect.Type][]*bean
+
+	/**
+	Serializes writers of the core map
 	*/
-	core map[reflect.Type][]*bean
+	coreMu sync.Mutex
 
 	/**
 	List of beans in initialization order that should depose on close
@@ -57,18 +74,197 @@ type context struct {
 	*/
 	runtimeCache sync.Map // key is reflect.Type (classPtr), value is *beanDef
 
+	/**
+	Guards construction of beans on demand after creation of the context
+	*/
+	constructMu sync.Mutex
+
+	/**
+	Types referenced by glue.WeakRef handles injected in this context
+	*/
+	weakTypes sync.Map // key is reflect.Type, value is true
+
+	/**
+	Injections made on creation of the context, used to repoint fields on Swap
+	*/
+	injections []*injection
+
+	/**
+	Contexts created by Extend and not closed yet, used to repoint fields on Swap
+	*/
+	extensions   []*context
+	extensionsMu sync.Mutex
+
+	/**
+	Injection decisions made on creation of the context
+	*/
+	auditLog AuditLog
+
 	/**
 	Guarantees that context would be closed once
 	*/
 	closeOnce sync.Once
+
+	/**
+	Panics during scan and construction are not converted to errors, set by DisableRecover option
+	*/
+	disableRecover bool
+
+	/**
+	Naming strategy of beans, set by Naming option
+	*/
+	naming NamingStrategy
+
+	/**
+	Path of the property source that set the property
+	*/
+	propertyOrigins sync.Map // key is string, value is string
+
+	/**
+	Pools of objects produced by PoolingFactoryBean
+	*/
+	pools []*objectPool
+
+	/**
+	Timeouts of close phases, set by CloseTimeouts option
+	*/
+	closeTimeouts CloseTimeouts
+
+	/**
+	Running consumers, started after creation of the context
+	*/
+	consumers *consumerGroup
+
+	/**
+	Running leader election, started after creation of the context
+	*/
+	election *leaderElection
+
+	/**
+	Role of the child context that created this context or its ancestor, empty for the root context
+	*/
+	role string
+
+	/**
+	Hooks around retrieval of beans, set by Hooks option
+	*/
+	hooks *Hooks
+
+	/**
+	Packages allowed to register and inject beans, set by Sandbox option
+	*/
+	sandbox *Sandbox
+
+	/**
+	Limits of memory estimation of beans in Stats, set by MemoryAnalyzer option
+	*/
+	memoryAnalyzer *MemoryAnalyzer
+
+	/**
+	Clear injected fields of beans and references of the context on close, set by ReleaseOnClose option
+	*/
+	releaseOnClose bool
+
+	/**
+	Detection of goroutines leaked by beans, set by LeakDetector option
+	*/
+	leakDetector *LeakDetector
+	goroutines   *goroutineOwners
+
+	/**
+	Details included in String, set by StringFormat option
+	*/
+	stringFormat *StringFormat
+
+	/**
+	Verbose logger of the context overriding the global one, set by WithVerbose
+	*/
+	verboseLog *log.Logger
+
+	/**
+	Progress listener of construction of beans, set by OnProgress option, tracked only during creation
+	*/
+	onProgress OnProgress
+	progress   *progress
+
+	/**
+	Concurrent construction of beans, set by Parallel option
+	*/
+	parallel *Parallel
+
+	/**
+	Guards disposables during parallel construction of beans and runtime modifications
+	*/
+	disposablesMu sync.Mutex
+
+	/**
+	Collector of warnings of static analysis of beans, set by OnWarning option
+	*/
+	onWarning OnWarning
+
+	/**
+	Fail creation on warnings of static analysis, set by Strict option
+	*/
+	strict bool
+
+	/**
+	Beans which PostConstruct failed on refresh, guarded by constructMu
+	*/
+	refreshFailed map[*bean]bool
+
+	/**
+	Property sources loaded on creation, read again by ReloadProperties
+	*/
+	propertySources []*PropertySource
+
+	/**
+	Serializes reloads of properties
+	*/
+	reloadMu sync.Mutex
+
+	/**
+	Reload of properties on signals, set by ReloadOnSignal option
+	*/
+	reloadOnSignal *ReloadOnSignal
+
+	/**
+	Creation stops before construction of beans, set by Validate
+	*/
+	dryRun bool
+
+	/**
+	Description of the application on command line flag, set by DescribeOnFlag option
+	*/
+	describeOnFlag *DescribeOnFlag
+
+	/**
+	Inheritance of properties of the parent, set by PropertyInheritance option before scan
+	*/
+	propertyInheritance PropertyInheritance
+
+	/**
+	Diagnostics bundle written on failure of creation, set by Diagnostics option before scan
+	*/
+	diagnostics *Diagnostics
+
+	/**
+	Verbose logger of the context capturing events for diagnostics bundle during creation
+	*/
+	eventLog atomic.Value // value is *log.Logger
+
+	/**
+	Built-in ShutdownSignal and ReadySignal beans
+	*/
+	shutdown *signal
+	ready    *signal
 }
 
 func New(scan ...interface{}) (Context, error) {
-	return createContext(nil, scan)
+	return createContext(nil, nil, scan)
 }
 
 func (t *context) Extend(scan ...interface{}) (Context, error) {
-	return createContext(t, scan)
+	return createContext(t, nil, scan)
 }
 
 func (t *context) Parent() (Context, bool) {
@@ -79,34 +275,81 @@ func (t *context) Parent() (Context, bool) {
 	}
 }
 
-func createContext(parent *context, scan []interface{}) (ctx *context, err error) {
-
-	prev := runtime.GOMAXPROCS(1)
-	defer func() {
-		runtime.GOMAXPROCS(prev)
-	}()
+func createContext(parent *context, snapshot *snapshot, scan []interface{}) (ctx *context, err error) {
 
 	core := make(map[reflect.Type][]*bean)
 	pointers := make(map[reflect.Type][]*injection)
 	interfaces := make(map[reflect.Type][]*injection)
 	var propertySources []*PropertySource
 	var propertyResolvers []PropertyResolver
+	var propertyInterceptors []PropertyInterceptor
+	var weakInjections []*injection
 	var primaryList []*bean
 	var secondaryList []*bean
 
 	ctx = &context{
 		parent: parent,
-		core:   core,
 		registry: registry{
 			beansByName: make(map[string][]*bean),
 			beansByType: make(map[reflect.Type][]*bean),
 			resourceSources: make(map[string]*resourceSource),
 		},
 		properties: NewProperties(),
+		shutdown:   newSignal(ShutdownSignalClass),
+		ready:      newSignal(ReadySignalClass),
 	}
+	created := ctx
 
 	if parent != nil {
-		ctx.properties.Extend(parent.properties)
+		ctx.disableRecover = parent.disableRecover
+		ctx.naming = parent.naming
+		ctx.closeTimeouts = parent.closeTimeouts
+		ctx.role = parent.role
+		ctx.hooks = parent.hooks
+		ctx.sandbox = parent.sandbox
+		ctx.memoryAnalyzer = parent.memoryAnalyzer
+		ctx.releaseOnClose = parent.releaseOnClose
+		ctx.stringFormat = parent.stringFormat
+		ctx.verboseLog = parent.verboseLog
+		ctx.onWarning = parent.onWarning
+		ctx.strict = parent.strict
+		if parent.leakDetector != nil {
+			parent.leakDetector.applyOption(ctx)
+		}
+	}
+
+	/**
+	Options applied before scan
+	*/
+	for _, obj := range scan {
+		switch opt := obj.(type) {
+		case verboseOption:
+			opt.applyOption(ctx)
+		case PropertyInheritance:
+			opt.applyOption(ctx)
+		case Diagnostics:
+			opt.applyOption(ctx)
+		}
+	}
+
+	ctx.core.Store(core)
+
+	events, stopEvents := ctx.captureEvents()
+	defer func() {
+		stopEvents()
+		if err != nil && created.diagnostics != nil {
+			created.writeDiagnostics(err, events)
+		}
+	}()
+
+	if parent != nil {
+		switch ctx.propertyInheritance {
+		case MergeParentProperties:
+			ctx.properties.(*properties).extendUnder(parent.properties)
+		case IsolateParentProperties:
+		default:
+			ctx.properties.Extend(parent.properties)
+		}
 	}
 
 	// add context bean to registry
@@ -131,15 +374,75 @@ func createContext(parent *context, scan []interface{}) (ctx *context, err error
 	}
 	core[propertiesBean.beanDef.classPtr] = []*bean {propertiesBean}
 
+
+	var conditionals []*conditionalScan
+	var adaptations []*adaptation
+	var providers []*provider
+	var groupOrders []groupOrder
+	aliases := make(propertyAliases)
+
 	// scan
-	err = forEach("", scan, func(pos string, obj interface{}) (err error) {
+	scanBean := func(pos string, obj interface{}) (err error) {
+
+		if opt, ok := obj.(contextOption); ok {
+			opt.applyOption(ctx)
+			return nil
+		}
+
+		if group, ok := conditionalOf(obj); ok {
+			conditionals = append(conditionals, &conditionalScan{pos: pos, group: group, template: obj})
+			return nil
+		}
+
+		if a, ok := obj.(*adaptation); ok {
+			adaptations = append(adaptations, a)
+			return nil
+		}
+
+		if g, ok := obj.(groupOrder); ok {
+			groupOrders = append(groupOrders, g)
+			return nil
+		}
+
+		if p, ok := obj.(*provider); ok {
+			providers = append(providers, p)
+			return nil
+		}
+
+		if a, ok := obj.(propertyAliases); ok {
+			for old, key := range a {
+				aliases[old] = key
+			}
+			return nil
+		}
+
+		if v, ok := obj.(*namedValue); ok {
+			if v.value == nil {
+				return errors.Errorf("nil value '%s' on position '%s'", v.name, pos)
+			}
+			if ctx.logger() != nil {
+				ctx.logger().Printf("Value %v with name '%s'\n", reflect.TypeOf(v.value), v.name)
+			}
+			registerBean(core, reflect.TypeOf(v.value), v.bean())
+			return nil
+		}
 
 		var resolver bool
 
+		var propertyPrefix string
+		var lazyInit bool
+		provided := &registration{}
+		if r, ok := obj.(*registration); ok {
+			propertyPrefix = r.prefix
+			lazyInit = r.lazy
+			provided = r
+			obj = r.obj
+		}
+
 		switch instance := obj.(type) {
 		case ChildContext:
-			if verbose != nil {
-				verbose.Printf("ChildContext %s\n", instance.Role())
+			if ctx.logger() != nil {
+				ctx.logger().Printf("ChildContext %s\n", instance.Role())
 			}
 			ctx.children = append(ctx.children, instance)
 			// register interest by making a placeholder
@@ -147,44 +450,55 @@ func createContext(parent *context, scan []interface{}) (ctx *context, err error
 				interfaces[ChildContextClass] = []*injection{}
 			}
 		case ResourceSource:
-			if verbose != nil {
-				verbose.Printf("ResourceSource %s, assets %+v\n", instance.Name, instance.AssetNames)
+			if ctx.logger() != nil {
+				ctx.logger().Printf("ResourceSource %s, assets %+v\n", instance.Name, instance.AssetNames)
 			}
 			if err := ctx.registry.addResourceSource(&instance); err != nil {
 				return err
 			}
 			obj = &instance
 		case *ResourceSource:
-			if verbose != nil {
-				verbose.Printf("ResourceSource %s, assets %+v\n", instance.Name, instance.AssetNames)
+			if ctx.logger() != nil {
+				ctx.logger().Printf("ResourceSource %s, assets %+v\n", instance.Name, instance.AssetNames)
 			}
 			if err := ctx.registry.addResourceSource(instance); err != nil {
 				return err
 			}
 		case PropertySource:
-			if verbose != nil {
-				verbose.Printf("PropertySource %s %d\n", instance.Path, len(instance.Map))
+			if ctx.logger() != nil {
+				ctx.logger().Printf("PropertySource %s %d\n", instance.Path, len(instance.Map))
 			}
 			ptr := &instance
 			propertySources = append(propertySources, ptr)
 			obj = ptr
 		case *PropertySource:
-			if verbose != nil {
-				verbose.Printf("PropertySource %s %d\n", instance.Path, len(instance.Map))
+			if ctx.logger() != nil {
+				ctx.logger().Printf("PropertySource %s %d\n", instance.Path, len(instance.Map))
 			}
 			propertySources = append(propertySources, instance)
 		case PropertyResolver:
-			if verbose != nil {
-				verbose.Printf("PropertyResolver Priority %d\n", instance.Priority())
+			if ctx.logger() != nil {
+				ctx.logger().Printf("PropertyResolver Priority %d\n", instance.Priority())
 			}
 			propertyResolvers = append(propertyResolvers, instance)
 			resolver = true
 		default:
 		}
 
+		if interceptor, ok := obj.(PropertyInterceptor); ok {
+			if ctx.logger() != nil {
+				ctx.logger().Printf("PropertyInterceptor %v\n", reflect.TypeOf(obj))
+			}
+			propertyInterceptors = append(propertyInterceptors, interceptor)
+			resolver = true
+		}
+
 		classPtr := reflect.TypeOf(obj)
 
 		defer func() {
+			if ctx.disableRecover {
+				return
+			}
 			if r := recover(); r != nil {
 				err = errors.Errorf("recover from object scan '%s' on error %v\n", classPtr.String(), r)
 			}
@@ -192,21 +506,46 @@ func createContext(parent *context, scan []interface{}) (ctx *context, err error
 
 		switch classPtr.Kind() {
 		case reflect.Ptr:
+			if err := ctx.sandbox.check(classPtr); err != nil {
+				return errors.Errorf("bean '%v' on position '%s' is not allowed, %v", classPtr, pos, err)
+			}
+
 			/**
 			New bean from object
 			*/
-			objBean, err := investigate(obj, classPtr)
+			objBean, err := investigateWith(obj, classPtr, snapshot.restore(classPtr))
 			if err != nil {
 				return err
 			}
 
+			if objBean.qualifier == "" {
+				objBean.name = ctx.beanName(classPtr)
+			}
+			if provided.name != "" {
+				objBean.name = provided.name
+				objBean.qualifier = provided.name
+			}
+			if provided.ordered {
+				objBean.ordered = true
+				objBean.order = provided.order
+			}
+			objBean.primary = provided.primary
+			objBean.group = provided.group
+
+			if propertyPrefix != "" {
+				objBean.propertyPrefix = propertyPrefix
+				objBean.name = propertyPrefix + objBean.name
+				objBean.qualifier = objBean.name
+			}
+			objBean.lazyInit = lazyInit
+
 			var elemClassPtr reflect.Type
 			factoryBean, isFactoryBean := obj.(FactoryBean)
 			if isFactoryBean {
 				elemClassPtr = factoryBean.ObjectType()
 			}
 
-			if verbose != nil {
+			if ctx.logger() != nil {
 				if isFactoryBean {
 					var info string
 					if factoryBean.Singleton() {
@@ -216,23 +555,25 @@ func createContext(parent *context, scan []interface{}) (ctx *context, err error
 					}
 					objectName := factoryBean.ObjectName()
 					if objectName != "" {
-						verbose.Printf("FactoryBean %v produce %s %v with name '%s'\n", classPtr, info, elemClassPtr, objectName)
+						ctx.logger().Printf("FactoryBean %v produce %s %v with name '%s'\n", classPtr, info, elemClassPtr, objectName)
 					} else {
-						verbose.Printf("FactoryBean %v produce %s %v\n", classPtr, info, elemClassPtr)
+						ctx.logger().Printf("FactoryBean %v produce %s %v\n", classPtr, info, elemClassPtr)
 					}
 				} else {
 					if objBean.qualifier != "" {
-						verbose.Printf("Bean %v with name '%s'\n", classPtr, objBean.qualifier)
+						ctx.logger().Printf("Bean %v with name '%s'\n", classPtr, objBean.qualifier)
 					} else {
-						verbose.Printf("Bean %v\n", classPtr)
+						ctx.logger().Printf("Bean %v\n", classPtr)
 					}
 				}
 			}
 
 			if isFactoryBean {
-				elemClassKind := elemClassPtr.Kind()
-				if elemClassKind != reflect.Ptr && elemClassKind != reflect.Interface {
-					return errors.Errorf("factory bean '%v' on position '%s' can produce ptr or interface, but object type is '%v'", classPtr, pos, elemClassPtr)
+				if !producibleType(elemClassPtr) {
+					return errors.Errorf("factory bean '%v' on position '%s' can produce ptr, interface, func or named slice and map, but object type is '%v'", classPtr, pos, elemClassPtr)
+				}
+				if err := ctx.sandbox.check(elemClassPtr); err != nil {
+					return errors.Errorf("factory bean '%v' on position '%s' can not produce '%v', %v", classPtr, pos, elemClassPtr, err)
 				}
 			}
 
@@ -242,7 +583,7 @@ func createContext(parent *context, scan []interface{}) (ctx *context, err error
 			if len(objBean.beanDef.fields) > 0 {
 				value := objBean.valuePtr.Elem()
 				for _, injectDef := range objBean.beanDef.fields {
-					if verbose != nil {
+					if ctx.logger() != nil {
 						var attr []string
 						if injectDef.lazy {
 							attr = append(attr,  "lazy")
@@ -250,9 +591,33 @@ func createContext(parent *context, scan []interface{}) (ctx *context, err error
 						if injectDef.optional {
 							attr = append(attr,  "optional")
 						}
+						if injectDef.ordered {
+							attr = append(attr,  "ordered")
+						}
+						if injectDef.weakType != nil {
+							attr = append(attr,  "weak")
+						}
 						if injectDef.qualifier != "" {
 							attr = append(attr,  "bean=" + injectDef.qualifier)
 						}
+						if injectDef.keyCase != "" {
+							attr = append(attr,  "keyCase=" + injectDef.keyCase)
+						}
+						if injectDef.keyFunc != "" {
+							attr = append(attr,  "keyFunc=" + injectDef.keyFunc)
+						}
+						if injectDef.onDuplicate != "" {
+							attr = append(attr,  "onDuplicate=" + injectDef.onDuplicate)
+						}
+						if injectDef.excludeSelf {
+							attr = append(attr,  "excludeSelf")
+						}
+						for _, iface := range injectDef.also {
+							attr = append(attr,  fmt.Sprintf("also=%v", iface))
+						}
+						if injectDef.when != "" {
+							attr = append(attr,  "when=" + injectDef.when)
+						}
 						var attrs string
 						if len(attr) > 0 {
 							attrs = fmt.Sprintf("[%s]", strings.Join(attr, ","))
@@ -264,18 +629,22 @@ func createContext(parent *context, scan []interface{}) (ctx *context, err error
 						if injectDef.table {
 							prefix = "map[string]"
 						}
-						verbose.Printf("	Field %s%v %s\n", prefix, injectDef.fieldType, attrs)
+						ctx.logger().Printf("	Field %s%v %s\n", prefix, injectDef.fieldType, attrs)
+					}
+
+					if injectDef.weakType != nil {
+						weakInjections = append(weakInjections, &injection{bean: objBean, value: value, injectionDef: injectDef})
+						continue
 					}
 
 					switch injectDef.fieldType.Kind() {
 					case reflect.Ptr:
-						pointers[injectDef.fieldType] = append(pointers[injectDef.fieldType], &injection{objBean, value, injectDef})
+						pointers[injectDef.fieldType] = append(pointers[injectDef.fieldType], &injection{bean: objBean, value: value, injectionDef: injectDef})
 					case reflect.Interface:
-						interfaces[injectDef.fieldType] = append(interfaces[injectDef.fieldType], &injection{objBean, value, injectDef})
-					case reflect.Func:
-						pointers[injectDef.fieldType] = append(pointers[injectDef.fieldType], &injection{objBean, value, injectDef})
+						interfaces[injectDef.fieldType] = append(interfaces[injectDef.fieldType], &injection{bean: objBean, value: value, injectionDef: injectDef})
 					default:
-						return errors.Errorf("injecting not a pointer or interface on field type '%v' at position '%s' in %v", injectDef.fieldType, pos, classPtr)
+						// functions and values registered by glue.Value are injected by exact type
+						pointers[injectDef.fieldType] = append(pointers[injectDef.fieldType], &injection{bean: objBean, value: value, injectionDef: injectDef})
 					}
 				}
 			}
@@ -290,9 +659,10 @@ func createContext(parent *context, scan []interface{}) (ctx *context, err error
 					factoryClassPtr: classPtr,
 					factoryBean:     factoryBean,
 				}
+				f.elemName = ctx.beanName(elemClassPtr)
 				objectName := factoryBean.ObjectName()
 				if objectName == "" {
-					objectName = elemClassPtr.String()
+					objectName = f.elemName
 				}
 				elemBean := &bean{
 					name:        objectName,
@@ -303,9 +673,18 @@ func createContext(parent *context, scan []interface{}) (ctx *context, err error
 					lifecycle: BeanAllocated,
 				}
 				f.instances = []*bean {elemBean}
+				if pf, ok := obj.(PoolingFactoryBean); ok {
+					min, max := pf.Pooled()
+					f.pool = newObjectPool(ctx, f, min, max)
+					ctx.pools = append(ctx.pools, f.pool)
+				}
 				// we can have singleton or multiple beans in context produced by this factory, let's allocate reference for injections even if those beans are still not exist
 				registerBean(core, elemClassPtr, elemBean)
-				secondaryList = append(secondaryList, elemBean)
+				if lazyInit || f.pool != nil {
+					elemBean.lazyInit = true
+				} else {
+					secondaryList = append(secondaryList, elemBean)
+				}
 			}
 
 			/*
@@ -314,25 +693,25 @@ func createContext(parent *context, scan []interface{}) (ctx *context, err error
 			registerBean(core, classPtr, objBean)
 
 			/**
-				Initialize property resolver beans at first
+				Initialize property resolver and interceptor beans at first
 			 */
 			if resolver {
 				primaryList = append(primaryList, objBean)
-			} else {
+			} else if !lazyInit {
 				secondaryList = append(secondaryList, objBean)
 			}
 
 		case reflect.Func:
 
-			if verbose != nil {
-				verbose.Printf("Function %v\n", classPtr)
+			if ctx.logger() != nil {
+				ctx.logger().Printf("Function %v\n", classPtr)
 			}
 
 			/*
 				Register function in context
 			*/
 			objBean := &bean{
-				name:     classPtr.String(),
+				name:     ctx.beanName(classPtr),
 				obj:      obj,
 				valuePtr: reflect.ValueOf(obj),
 				beanDef: &beanDef{
@@ -348,17 +727,150 @@ func createContext(parent *context, scan []interface{}) (ctx *context, err error
 		}
 
 		return nil
-	})
+	}
 
-	if err != nil {
+	if err = forEach("", scan, scanBean); err != nil {
 		return nil, err
 	}
 
+	/**
+	Load properties and register property resolvers and interceptors before evaluation of conditional groups,
+	enabled groups could bring more property sources and groups
+	 */
+	var loadedSources, registeredResolvers, registeredInterceptors int
+	appliedAliases := make(map[string]bool)
+	for {
+
+		if len(propertySources) > loadedSources {
+			if err := ctx.loadProperties(propertySources[loadedSources:]); err != nil {
+				return nil, err
+			}
+			loadedSources = len(propertySources)
+			ctx.propertySources = propertySources
+		}
+
+		for _, r := range propertyResolvers[registeredResolvers:] {
+			ctx.properties.Register(r)
+		}
+		registeredResolvers = len(propertyResolvers)
+
+		for _, i := range propertyInterceptors[registeredInterceptors:] {
+			ctx.properties.Intercept(i)
+		}
+		registeredInterceptors = len(propertyInterceptors)
+
+		ctx.applyPropertyAliases(aliases, appliedAliases)
+
+		if len(conditionals) == 0 {
+			break
+		}
+
+		list := conditionals
+		conditionals = nil
+		for _, c := range list {
+			enabled, err := c.group.enabled(ctx.properties)
+			if err != nil {
+				return nil, errors.Errorf("conditional group on position '%s' error, %v", c.pos, err)
+			}
+			if ctx.logger() != nil {
+				ctx.logger().Printf("EnabledIf '%s' is %v on position '%s'\n", c.group.key, enabled, c.pos)
+			}
+			if enabled {
+				if err := forEach(c.pos, c.group.beans, c.callback(scanBean)); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	/**
+	Drop injections disabled by 'when' attribute
+	 */
+	for _, m := range []map[reflect.Type][]*injection{pointers, interfaces} {
+		for typ, injects := range m {
+			var enabled []*injection
+			for _, inject := range injects {
+				ok, err := inject.injectionDef.enabled(ctx.properties)
+				if err != nil {
+					return nil, err
+				}
+				if ok {
+					enabled = append(enabled, inject)
+				} else {
+					if ctx.logger() != nil {
+						ctx.logger().Printf("Skip inject '%v' in to '%v' disabled by property '%s'\n", typ, inject, inject.injectionDef.when)
+					}
+					ctx.audit(inject).Disabled = true
+				}
+			}
+			if len(enabled) > 0 || len(injects) == 0 {
+				m[typ] = enabled
+			} else {
+				delete(m, typ)
+			}
+			ctx.injections = append(ctx.injections, enabled...)
+		}
+	}
+
+	if err := ctx.analyzeBeans(); err != nil {
+		return nil, err
+	}
+
+	/**
+	Register constructor functions as factories with injected parameters
+	 */
+	for _, p := range providers {
+		fn, args, err := p.resolve()
+		if err != nil {
+			return nil, err
+		}
+		if ctx.logger() != nil {
+			ctx.logger().Printf("Provider %v\n", fn.Type())
+		}
+		f := &providerFactory{fn: fn, args: args}
+		if err := scanBean("provider", args); err != nil {
+			return nil, err
+		}
+		if err := scanBean("provider", f); err != nil {
+			return nil, err
+		}
+		argsBeans := core[reflect.TypeOf(args)]
+		for _, b := range core[reflect.TypeOf(f)] {
+			if b.obj == f {
+				b.dependencies = append(b.dependencies, argsBeans[len(argsBeans)-1])
+			}
+		}
+	}
+
+	/**
+	Wrap beans of the current context by adapters
+	 */
+	for _, a := range adaptations {
+		from, to, fn, err := a.resolve()
+		if err != nil {
+			return nil, err
+		}
+		for _, source := range ctx.findMatches(from) {
+			if ctx.logger() != nil {
+				ctx.logger().Printf("Adapt bean '%s' from '%v' to '%v'\n", source.name, from, to)
+			}
+			f := &adapterFactory{source: source, to: to, fn: fn}
+			if err := scanBean("adapter", &registration{obj: f, lazy: source.lazyInit}); err != nil {
+				return nil, err
+			}
+			for _, b := range core[reflect.TypeOf(f)] {
+				if b.obj == f {
+					b.dependencies = append(b.dependencies, source)
+				}
+			}
+		}
+	}
+
 	// direct match
 	for requiredType, injects := range pointers {
 
-		if verbose != nil {
-			verbose.Println("Object", requiredType, len(injects))
+		if ctx.logger() != nil {
+			ctx.logger().Println("Object", requiredType, len(injects))
 		}
 
 		direct := ctx.findObjectRecursive(requiredType)
@@ -369,35 +881,38 @@ func createContext(parent *context, scan []interface{}) (ctx *context, err error
 				ctx.registry.addBeanList(requiredType, direct[0].list)
 			}
 
-			if verbose != nil {
-				verbose.Printf("Inject '%v' by pointer '%+v' in to %+v\n", requiredType, direct, injects)
+			if ctx.logger() != nil {
+				ctx.logger().Printf("Inject '%v' by pointer '%+v' in to %+v\n", requiredType, direct, injects)
 			}
 
 			for _, inject := range injects {
-				if err := inject.inject(direct); err != nil {
+				record := ctx.audit(inject)
+				if err := record.fail(inject.inject(direct, record, ctx)); err != nil {
 					return nil, errors.Errorf("required type '%s' injection error, %v", requiredType, err)
 				}
 			}
 
 		} else {
 
-			if verbose != nil {
-				verbose.Printf("Bean '%v' not found in context\n", requiredType)
+			if ctx.logger() != nil {
+				ctx.logger().Printf("Bean '%v' not found in context\n", requiredType)
 			}
 
 			var required []*injection
 			for _, inject := range injects {
+				record := ctx.audit(inject)
 				if inject.injectionDef.optional {
-					if verbose != nil {
-						verbose.Printf("Skip optional inject '%v' in to '%v'\n", requiredType, inject)
+					if ctx.logger() != nil {
+						ctx.logger().Printf("Skip optional inject '%v' in to '%v'\n", requiredType, inject)
 					}
 				} else {
+					record.Error = "bean not found in context"
 					required = append(required, inject)
 				}
 			}
 
 			if len(required) > 0 {
-				return nil, errors.Errorf("can not find candidates for '%v' reference bean required by '%+v'", requiredType, required)
+				return nil, errors.Errorf("can not find candidates for '%v' reference bean required by '%+v'%s", requiredType, required, ctx.genericHint(requiredType))
 			}
 
 		}
@@ -406,24 +921,26 @@ func createContext(parent *context, scan []interface{}) (ctx *context, err error
 	// interface match
 	for ifaceType, injects := range interfaces {
 
-		if verbose != nil {
-			verbose.Println("Interface", ifaceType, len(injects))
+		if ctx.logger() != nil {
+			ctx.logger().Println("Interface", ifaceType, len(injects))
 		}
 
 		candidates := ctx.searchInterfaceCandidatesRecursive(ifaceType)
 		if len(candidates) == 0 {
 
-			if verbose != nil {
-				verbose.Printf("No found bean candidates for interface '%v' in context\n", ifaceType)
+			if ctx.logger() != nil {
+				ctx.logger().Printf("No found bean candidates for interface '%v' in context\n", ifaceType)
 			}
 
 			var required []*injection
 			for _, inject := range injects {
+				record := ctx.audit(inject)
 				if inject.injectionDef.optional {
-					if verbose != nil {
-						verbose.Printf("Skip optional inject of interface '%v' in to '%v'\n", ifaceType, inject)
+					if ctx.logger() != nil {
+						ctx.logger().Printf("Skip optional inject of interface '%v' in to '%v'\n", ifaceType, inject)
 					}
 				} else {
+					record.Error = "no bean candidates implement the interface"
 					required = append(required, inject)
 				}
 			}
@@ -442,11 +959,12 @@ func createContext(parent *context, scan []interface{}) (ctx *context, err error
 
 		for _, inject := range injects {
 
-			if verbose != nil {
-				verbose.Printf("Inject '%v' by implementation '%+v' in to %+v\n", ifaceType, candidates, inject)
+			if ctx.logger() != nil {
+				ctx.logger().Printf("Inject '%v' by implementation '%+v' in to %+v\n", ifaceType, candidates, inject)
 			}
 
-			if err := inject.inject(candidates); err != nil {
+			record := ctx.audit(inject)
+			if err := record.fail(inject.inject(candidates, record, ctx)); err != nil {
 				return nil, errors.Errorf("interface '%s' injection error, %v", ifaceType, err)
 			}
 
@@ -454,94 +972,278 @@ func createContext(parent *context, scan []interface{}) (ctx *context, err error
 
 	}
 
-	/**
-	Load properties from property sources
-	 */
-	if len(propertySources) > 0 {
-		if err := ctx.loadProperties(propertySources); err != nil {
+	// weak references
+	for _, inject := range weakInjections {
+		if ok, err := inject.injectionDef.enabled(ctx.properties); err != nil {
 			return nil, err
+		} else if !ok {
+			ctx.audit(inject).Disabled = true
+			continue
 		}
+		if err := ctx.bindWeak(inject.value, inject.injectionDef); err != nil {
+			return nil, errors.Errorf("weak reference '%v' injection error, %v", inject.injectionDef.weakType, err)
+		}
+	}
+
+	if err := orderTopoInjections(pointers, interfaces); err != nil {
+		return nil, err
+	}
+
+	if err := ctx.checkThreadSafety(); err != nil {
+		return nil, err
 	}
 
 	/**
-	Register property resolvers from context
+	Construct beans after or before others by relative order constraints
 	 */
-	for _, r := range propertyResolvers {
-		ctx.properties.Register(r)
+	ctx.addOrderDependencies()
+
+	/**
+	Construct groups of beans fully one before another by group orders
+	 */
+	if err := ctx.addGroupDependencies(groupOrders); err != nil {
+		return nil, err
+	}
+
+	ctx.warnDeprecatedBeans()
+
+	if ctx.dryRun {
+		return ctx, ctx.validateWiring(primaryList, secondaryList)
 	}
 
 	/**
 	PostConstruct beans
 	 */
-	if err := ctx.postConstruct(primaryList, secondaryList); err != nil {
-		ctx.closeWithTimeout(DefaultCloseTimeout)
+	if ctx.onProgress != nil {
+		ctx.progress = newProgress(ctx.onProgress, primaryList, secondaryList)
+	}
+	err = ctx.postConstruct(primaryList, secondaryList)
+	ctx.progress = nil
+	if err == nil {
+		err = ctx.fillPools()
+	}
+	if err == nil {
+		err = ctx.startLeaderElection()
+	}
+	if err != nil {
+		if rollbackErr := ctx.rollback(DefaultCloseTimeout); len(rollbackErr) > 0 {
+			return nil, &StartupError{Err: err, Rollback: rollbackErr}
+		}
 		return nil, err
 	} else {
+		if ctx.describeOnFlag != nil && ctx.describeOnFlag.requested() {
+			return ctx, ctx.describeOnFlag.describe(ctx)
+		}
+		if parent != nil {
+			parent.attachExtension(ctx)
+		}
+		ctx.startConsumers()
+		ctx.ready.fire()
+		if err := ctx.runRunners(); err != nil {
+			if closeErr := ctx.closeWithReason(StartupFailureReason); closeErr != nil {
+				return nil, &StartupError{Err: err, Rollback: []error{closeErr}}
+			}
+			return nil, err
+		}
+		ctx.watchReloadSignals()
 		return ctx, nil
 	}
 
 }
 
-func (t *context) closeWithTimeout(timeout time.Duration) {
-	ch := make(chan error)
-	go func() {
-		ch <- t.Close()
-		close(ch)
-	}()
-	select {
-	case e := <- ch:
-		if e != nil && verbose != nil {
-			verbose.Printf("Close context error, %v\n", e)
-		}
-	case <- time.After(timeout):
-		if verbose != nil {
-			verbose.Printf("Close context timeout error.\n")
+/**
+Add construction dependencies between beans of current context declared by OrderedAfter and OrderedBefore interfaces
+*/
+func (t *context) addOrderDependencies() {
+	for _, list := range t.coreBeans() {
+		for _, b := range list {
+			for _, typ := range b.after {
+				for _, other := range t.findMatches(typ) {
+					if other != b {
+						b.dependencies = append(b.dependencies, other)
+					}
+				}
+			}
+			for _, typ := range b.before {
+				for _, other := range t.findMatches(typ) {
+					if other != b {
+						other.dependencies = append(other.dependencies, b)
+					}
+				}
+			}
 		}
 	}
 }
 
+func (t *context) findMatches(typ reflect.Type) []*bean {
+	if typ.Kind() == reflect.Interface {
+		return t.searchInterfaceCandidates(typ)
+	}
+	return t.coreBeans()[typ]
+}
+
+func (t *context) audit(inject *injection) *InjectionRecord {
+	record := newInjectionRecord(inject.bean, inject.injectionDef)
+	t.auditLog = append(t.auditLog, record)
+	return record
+}
+
+func (t *context) AuditLog() AuditLog {
+	log := make(AuditLog, len(t.auditLog))
+	copy(log, t.auditLog)
+	return log
+}
+
+/**
+Destroys only beans initialized before the failure of PostConstruct in reverse order and returns rollback errors.
+The context is considered closed after that.
+*/
+func (t *context) rollback(timeout time.Duration) []error {
+	ctx, cancel := stdcontext.WithTimeout(stdcontext.Background(), timeout)
+	defer cancel()
+	listErr := t.closeErrors(ctx, StartupFailureReason)
+	if len(listErr) > 0 && t.logger() != nil {
+		t.logger().Printf("Rollback context errors, %v\n", listErr)
+	}
+	return listErr
+}
+
+/**
+Error returned on creation of the context when PostConstruct failed and destroying of already initialized beans failed too.
+Err is the construction failure, Rollback holds errors of the rollback.
+*/
+type StartupError struct {
+	Err      error
+	Rollback []error
+}
+
+func (t *StartupError) Error() string {
+	return fmt.Sprintf("%v, rollback errors: %v", t.Err, t.Rollback)
+}
+
+func (t *StartupError) Cause() error {
+	return t.Err
+}
+
+func (t *StartupError) Unwrap() error {
+	return t.Err
+}
+
 func (t *context) loadProperties(propertySources []*PropertySource) error {
 
-	for _, source := range propertySources {
+	/**
+	Read resources concurrently, apply them in the order of sources to keep the precedence
+	 */
+	contents := t.readPropertySources(propertySources)
 
-		if source.Path != "" {
+	var errs []error
+	for i, source := range propertySources {
 
-			if resource, ok := t.Resource(source.Path); ok {
+		content := contents[i]
+		if content.err != nil {
+			errs = append(errs, content.err)
+			continue
+		}
 
-				file, err := resource.Open()
-				if err != nil {
-					return errors.Errorf("i/o error with placeholder properties resource '%s', %v", source, err)
-				}
+		before := t.properties.Map()
+		if content.yaml != nil {
+			t.properties.LoadMap(content.yaml)
+		} else if content.data != nil {
+			if err := t.properties.Parse(string(content.data)); err != nil {
+				errs = append(errs, errors.Errorf("load error of placeholder properties resource '%s', %v", source, err))
+				continue
+			}
+		}
+		t.recordOrigins(before, source.Path)
 
-				if isYamlFile(source.Path) {
+		if source.Map != nil {
+			before = t.properties.Map()
+			t.properties.LoadMap(source.Map)
+			t.recordOrigins(before, MapPropertySource)
+		}
 
-					holder := make(map[string]interface{})
-					err = yaml.NewDecoder(file).Decode(holder)
-					if err == nil {
-						t.properties.LoadMap(holder)
-					}
+	}
+
+	return multipleErr(errs)
+}
+
+/**
+Content of the property source resource
+*/
+type propertySourceContent struct {
+	data []byte
+	yaml map[string]interface{} // also holds parsed dotenv files
+	err  error
+}
+
+/**
+Opens and reads resources of property sources with bounded parallelism, the result has the same order as sources
+*/
+func (t *context) readPropertySources(propertySources []*PropertySource) []propertySourceContent {
+
+	contents := make([]propertySourceContent, len(propertySources))
+
+	parallelism := PropertySourceParallelism
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	sem := make(chan struct{}, parallelism)
+
+	var wg sync.WaitGroup
+	for i, source := range propertySources {
+		if source.Path == "" {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(content *propertySourceContent, source *PropertySource) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			*content = t.readPropertySource(source)
+		}(&contents[i], source)
+	}
+	wg.Wait()
+
+	return contents
+}
 
-				} else {
-					err = t.properties.Load(file)
-				}
+func (t *context) readPropertySource(source *PropertySource) (content propertySourceContent) {
 
-				file.Close()
-				if err != nil {
-					return errors.Errorf("load error of placeholder properties resource '%s', %v", source, err)
-				}
+	resource, ok := t.Resource(source.Path)
+	if !ok {
+		content.err = errors.Errorf("placeholder properties resource '%s' is not found", source)
+		return
+	}
 
-			} else {
-				return errors.Errorf("placeholder properties resource '%s' is not found", source)
-			}
+	file, err := resource.Open()
+	if err != nil {
+		content.err = errors.Errorf("i/o error with placeholder properties resource '%s', %v", source, err)
+		return
+	}
+	defer file.Close()
+
+	if isYamlFile(source.Path) {
+		holder := make(map[string]interface{})
+		err = yaml.NewDecoder(file).Decode(holder)
+		content.yaml = holder
+	} else if isEnvFile(source.Path) {
+		var data []byte
+		if data, err = ioutil.ReadAll(file); err == nil {
+			content.yaml, err = parseEnvFile(string(data))
 		}
-
-		if source.Map != nil {
-			t.properties.LoadMap(source.Map)
+	} else {
+		content.data, err = ioutil.ReadAll(file)
+		if content.data == nil {
+			content.data = []byte{}
 		}
-
 	}
 
-	return nil
+	if err != nil {
+		content.err = errors.Errorf("load error of placeholder properties resource '%s', %v", source, err)
+	}
+	return
 }
 
 func isYamlFile(fileName string) bool {
@@ -552,7 +1254,7 @@ func (t *context) findObjectRecursive(requiredType reflect.Type) []beanlist {
 	var candidates []beanlist
 	level := 1
 	for ctx := t; ctx != nil; ctx = ctx.parent {
-		if direct, ok := ctx.core[requiredType]; ok {
+		if direct, ok := ctx.objectBeans(requiredType); ok {
 			candidates = append(candidates, beanlist{level: level, list: direct})
 		}
 		level++
@@ -567,12 +1269,12 @@ func (t *context) searchAndCacheObjectRecursive(requiredType reflect.Type) []bea
 
 		// first lookup in the registry
 		if list, ok := ctx.registry.findByType(requiredType); !ok {
-			list = ctx.core[requiredType]
+			// store in cache, even an empty list, so next time we would not come here
+			direct, _ := ctx.objectBeans(requiredType)
+			list = ctx.registry.cacheBeanList(requiredType, direct)
 			if len(list) > 0 {
 				candidates = append(candidates, beanlist{level: level, list: list})
 			}
-			// store in cache, even an empty list, so next time we would not come here
-			ctx.registry.addBeanList(requiredType, list)
 
 		} else if len(list) > 0 {
 			candidates = append(candidates, beanlist{level: level, list: list})
@@ -583,12 +1285,27 @@ func (t *context) searchAndCacheObjectRecursive(requiredType reflect.Type) []bea
 	return candidates
 }
 
+/**
+Returns the immutable map of beans scanned in the context
+*/
+func (t *context) coreBeans() map[reflect.Type][]*bean {
+	return t.core.Load().(map[reflect.Type][]*bean)
+}
+
+/**
+Sequence of registrations of beans in all contexts
+*/
+var beanSequence uint64
+
 func registerBean(registry map[reflect.Type][]*bean, classPtr reflect.Type, bean *bean) {
+	if bean.seq == 0 {
+		bean.seq = atomic.AddUint64(&beanSequence, 1)
+	}
 	registry[classPtr] = append(registry[classPtr], bean)
 }
 
 func forEach(initialPos string, scan []interface{}, cb func(i string, obj interface{}) error) error {
-	for j, item := range scan {
+	for j, item := range sortScanners(scan) {
 		var pos string
 		if len(initialPos) > 0 {
 			pos = fmt.Sprintf("%s.%d", initialPos, j)
@@ -600,7 +1317,47 @@ func forEach(initialPos string, scan []interface{}, cb func(i string, obj interf
 		}
 		switch obj := item.(type) {
 		case Scanner:
-			if err := forEach(pos, obj.Beans(), cb); err != nil {
+			beans := obj.Beans()
+			scanCb := cb
+			if named, ok := obj.(NamedScanner); ok {
+				scanCb = namespaced(named.ScannerName(), beans, cb)
+			}
+			if err := forEach(pos, beans, scanCb); err != nil {
+				return err
+			}
+		case *lazyGroup:
+			err := forEach(pos, obj.beans, func(pos string, obj interface{}) error {
+				r := registrationOf(obj)
+				r.lazy = true
+				return cb(pos, r)
+			})
+			if err != nil {
+				return err
+			}
+		case *pluginGroup:
+			beans, err := obj.Beans()
+			if err != nil {
+				return err
+			}
+			if err := forEach(pos, beans, cb); err != nil {
+				return err
+			}
+		case *packageGroup:
+			beans, err := obj.Beans()
+			if err != nil {
+				return err
+			}
+			err = forEach(pos, beans, func(pos string, item interface{}) error {
+				return cb(pos, inGroup(obj.name, item))
+			})
+			if err != nil {
+				return err
+			}
+		case *inlineGroup:
+			err := forEach(pos, obj.beans, func(pos string, item interface{}) error {
+				return cb(pos, inGroup(obj.name, item))
+			})
+			if err != nil {
 				return err
 			}
 		case []interface{}:
@@ -620,9 +1377,15 @@ func forEach(initialPos string, scan []interface{}, cb func(i string, obj interf
 
 func (t *context) Core() []reflect.Type {
 	var list []reflect.Type
-	for typ := range t.core {
+	for typ := range t.coreBeans() {
 		list = append(list, typ)
 	}
+	sort.Slice(list, func(i, j int) bool {
+		if a, b := list[i].String(), list[j].String(); a != b {
+			return a < b
+		}
+		return classPackage(list[i]) < classPackage(list[j])
+	})
 	return list
 }
 
@@ -630,56 +1393,369 @@ func (t *context) Bean(typ reflect.Type, level int) []Bean {
 	var beanList []Bean
 	candidates := t.getBean(typ)
 	if len(candidates) > 0 {
-		list := orderBeans(levelBeans(candidates, level))
+		list := orderBeans(levelBeans(candidates, level), t.logger())
+		list = t.constructLazyOrWarn(candidates, list)
 		for _, b := range list {
 			beanList = append(beanList, b)
 		}
 	}
-	return beanList
+	return t.hooks.lookup(typ, beanList)
+}
+
+func (t *context) Prime(types ...reflect.Type) error {
+	var listErr []error
+	for _, typ := range types {
+		candidates := t.getBean(typ)
+		if len(candidates) == 0 {
+			listErr = append(listErr, errors.Errorf("can not find candidates for type '%v' to prime", typ))
+			continue
+		}
+		// first available level the same as default lookup
+		entry := candidates[0]
+		owner := t.contextAt(entry.level)
+		for _, b := range entry.list {
+			if b.Lifecycle() == BeanInitialized {
+				continue
+			}
+			if t.logger() != nil {
+				t.logger().Printf("Prime bean '%s' with type '%v'\n", b.name, b.beanDef.classPtr)
+			}
+			if err := owner.constructOnDemand(b); err != nil {
+				listErr = append(listErr, err)
+			}
+		}
+	}
+	return multipleErr(listErr)
+}
+
+/**
+Construct selected lazy beans found on the lookup levels on demand
+*/
+func (t *context) constructLazy(deep []beanlist, selected []*bean) error {
+	visible := make(map[*bean]bool)
+	for _, b := range selected {
+		if b.lazyInit && b.Lifecycle() != BeanInitialized {
+			visible[b] = true
+		}
+	}
+	if len(visible) == 0 {
+		return nil
+	}
+	var listErr []error
+	for _, entry := range deep {
+		owner := t.contextAt(entry.level)
+		for _, b := range entry.list {
+			if visible[b] {
+				if t.logger() != nil {
+					t.logger().Printf("Construct lazy bean '%s' with type '%v'\n", b.name, b.beanDef.classPtr)
+				}
+				if err := owner.constructOnDemand(b); err != nil {
+					listErr = append(listErr, err)
+				}
+			}
+		}
+	}
+	return multipleErr(listErr)
+}
+
+/**
+Constructs selected lazy beans for the lookup, beans failed to construct are reported as warnings and removed from the result
+*/
+func (t *context) constructLazyOrWarn(deep []beanlist, selected []*bean) []*bean {
+	err := t.constructLazy(deep, selected)
+	if err == nil {
+		return selected
+	}
+	warnf("Lazy bean construction error, %v\n", err)
+	var list []*bean
+	for _, b := range selected {
+		if !b.lazyInit || b.Lifecycle() == BeanInitialized {
+			list = append(list, b)
+		}
+	}
+	return list
+}
+
+/**
+Returns context of the lookup level, where 1 is the current context
+*/
+func (t *context) contextAt(level int) *context {
+	ctx := t
+	for i := 1; i < level && ctx.parent != nil; i++ {
+		ctx = ctx.parent
+	}
+	return ctx
+}
+
+/**
+Construct bean on demand after creation of the context
+*/
+func (t *context) constructOnDemand(b *bean) error {
+	t.constructMu.Lock()
+	defer t.constructMu.Unlock()
+	return t.constructBean(b, nil)
 }
 
 func (t *context) Lookup(iface string, level int) []Bean {
 	var beanList []Bean
 	candidates := t.searchByNameInRepositoryRecursive(iface)
 	if len(candidates) > 0 {
-		list := orderBeans(levelBeans(candidates, level))
+		list := orderBeans(levelBeans(candidates, level), t.logger())
+		list = t.constructLazyOrWarn(candidates, list)
+		for _, b := range list {
+			beanList = append(beanList, b)
+		}
+	}
+	return t.hooks.lookup(nil, beanList)
+}
+
+func (t *context) LookupPattern(pattern string, level int) []Bean {
+	match, err := namePattern(pattern)
+	if err != nil {
+		if t.logger() != nil {
+			t.logger().Printf("Lookup pattern error, %v\n", err)
+		}
+		return nil
+	}
+	var candidates []beanlist
+	depth := 1
+	for ctx := t; ctx != nil; ctx = ctx.parent {
+		if list := ctx.registry.findByPattern(match); len(list) > 0 {
+			candidates = append(candidates, beanlist{level: depth, list: list})
+		}
+		depth++
+	}
+	var beanList []Bean
+	if len(candidates) > 0 {
+		list := levelBeans(candidates, level)
+		list = t.constructLazyOrWarn(candidates, list)
 		for _, b := range list {
 			beanList = append(beanList, b)
 		}
 	}
-	return beanList
+	return t.hooks.lookup(nil, beanList)
+}
+
+func (t *context) EachBean(level int, fn func(Bean) bool) {
+	if level == DefaultLevel {
+		level = LocalLevel
+	}
+	if t.hooks != nil && t.hooks.OnLookup != nil {
+		visit := fn
+		fn = func(b Bean) bool {
+			for _, allowed := range t.hooks.OnLookup(b.Class(), []Bean{b}) {
+				if !visit(allowed) {
+					return false
+				}
+			}
+			return true
+		}
+	}
+	depth := 1
+	for ctx := t; ctx != nil; ctx = ctx.parent {
+		if level > 0 && depth > level {
+			return
+		}
+		if !ctx.eachBean(fn) {
+			return
+		}
+		depth++
+	}
+}
+
+func (t *context) eachBean(fn func(Bean) bool) bool {
+	core := t.coreBeans()
+	types := make([]reflect.Type, 0, len(core))
+	for typ := range core {
+		types = append(types, typ)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].String() < types[j].String()
+	})
+	for _, typ := range types {
+		for _, b := range core[typ] {
+			if !fn(b) {
+				return false
+			}
+			if f := b.beenFactory; f != nil {
+				var produced []*bean
+				f.mu.Lock()
+				if b == f.instances[0] {
+					produced = append(produced, f.instances[1:]...)
+				}
+				f.mu.Unlock()
+				for _, p := range produced {
+					if !fn(p) {
+						return false
+					}
+				}
+			}
+		}
+	}
+	return true
 }
 
 func (t *context) Inject(obj interface{}) error {
+	return t.inject(obj, t.logger())
+}
+
+func (t *context) inject(obj interface{}, logger *log.Logger) error {
+	properties := t.properties
+	if r, ok := obj.(*registration); ok {
+		properties = properties.Sub(r.prefix)
+		obj = r.obj
+	}
 	if obj == nil {
 		return errors.New("null obj is are not allowed")
 	}
+	plan, err := t.planInjection(obj, logger)
+	if err != nil {
+		return err
+	}
+	return plan.apply(t, obj, properties, logger)
+}
+
+func (t *context) InjectAll(objs ...interface{}) error {
+	return t.injectAll(objs, t.logger())
+}
+
+func (t *context) injectAll(objs []interface{}, logger *log.Logger) error {
+	plans := make(map[reflect.Type]*injectionPlan)
+	prefixed := make(map[string]Properties)
+	for i, obj := range objs {
+		properties := t.properties
+		if r, ok := obj.(*registration); ok {
+			if properties, ok = prefixed[r.prefix]; !ok {
+				properties = t.properties.Sub(r.prefix)
+				prefixed[r.prefix] = properties
+			}
+			obj = r.obj
+		}
+		if obj == nil {
+			return errors.Errorf("null obj on position %d is not allowed", i)
+		}
+		classPtr := reflect.TypeOf(obj)
+		plan, ok := plans[classPtr]
+		if !ok {
+			var err error
+			if plan, err = t.planInjection(obj, logger); err != nil {
+				return errors.Errorf("object on position %d with type '%v' injection error, %v", i, classPtr, err)
+			}
+			plans[classPtr] = plan
+		}
+		if err := plan.apply(t, obj, properties, logger); err != nil {
+			return errors.Errorf("object on position %d with type '%v' injection error, %v", i, classPtr, err)
+		}
+	}
+	return nil
+}
+
+/**
+Fields of the runtime object with candidates found in the context, shared by objects of the same type
+*/
+type injectionPlan struct {
+	fields     []*injectionDef
+	candidates [][]beanlist
+	properties []*propInjectionDef
+
+	/**
+	Bean of the context the object becomes on Swap, its injections are recorded to be repointed by next swaps.
+	Beans injected in to objects of runtime injections are marked instead, since those objects are not tracked.
+	*/
+	bean *bean
+}
+
+/**
+Looks up candidates for fields of the object and constructs lazy ones, traces them to the logger if not nil
+*/
+func (t *context) planInjection(obj interface{}, logger *log.Logger) (*injectionPlan, error) {
 	classPtr := reflect.TypeOf(obj)
 	if classPtr.Kind() != reflect.Ptr {
-		return errors.Errorf("non-pointer instances are not allowed, type %v", classPtr)
+		return nil, errors.Errorf("non-pointer instances are not allowed, type %v", classPtr)
 	}
-	valuePtr := reflect.ValueOf(obj)
-	value := valuePtr.Elem()
-	if bd, err := t.cache(obj, classPtr); err != nil {
-		return err
-	} else {
-		for _, inject := range bd.fields {
-			impl := t.getBean(inject.fieldType)
+	bd, err := t.cache(obj, classPtr)
+	if err != nil {
+		return nil, err
+	}
+	plan := &injectionPlan{properties: bd.properties}
+	for _, inject := range bd.fields {
+		if ok, err := inject.enabled(t.properties); err != nil {
+			return nil, err
+		} else if !ok {
+			if logger != nil {
+				logger.Printf("Skip disabled field '%s' of '%v'\n", inject.fieldName, classPtr)
+			}
+			continue
+		}
+		var impl []beanlist
+		if inject.weakType == nil {
+			impl = t.getBean(inject.fieldType)
 			if len(impl) == 0 {
 				if inject.optional {
+					if logger != nil {
+						logger.Printf("Skip optional field '%s' of '%v', implementation not found for type '%v'\n", inject.fieldName, classPtr, inject.fieldType)
+					}
 					continue
 				}
-				return errors.Errorf("implementation not found for field '%s' with type '%v'", inject.fieldName, inject.fieldType)
+				if logger != nil {
+					logger.Printf("Implementation not found for field '%s' of '%v' with type '%v'\n", inject.fieldName, classPtr, inject.fieldType)
+				}
+				return nil, errors.Errorf("implementation not found for field '%s' with type '%v'%s", inject.fieldName, inject.fieldType, t.genericHint(inject.fieldType))
 			}
-			if err := inject.inject(&value, impl); err != nil {
-				return err
+			if logger != nil {
+				logger.Printf("Field '%s' of '%v' with type '%v' candidates %v\n", inject.fieldName, classPtr, inject.fieldType, impl)
+			}
+			if err := t.constructLazy(impl, inject.selectBeans(impl, nil, logger)); err != nil {
+				return nil, err
 			}
 		}
-		for _, inject := range bd.properties {
-			if err := inject.inject(&value, t.properties); err != nil {
-				return err
+		plan.fields = append(plan.fields, inject)
+		plan.candidates = append(plan.candidates, impl)
+	}
+	return plan, nil
+}
+
+func (t *injectionPlan) apply(ctx *context, obj interface{}, properties Properties, logger *log.Logger) error {
+	value := reflect.ValueOf(obj).Elem()
+	for i, inject := range t.fields {
+		var err error
+		if inject.weakType != nil {
+			err = ctx.bindWeak(value, inject)
+		} else {
+			var chosen []*bean
+			if chosen, err = inject.inject(&value, t.candidates[i], ctx, logger); err == nil {
+				if t.bean != nil {
+					ctx.injections = append(ctx.injections, &injection{bean: t.bean, value: value, injectionDef: inject})
+				} else {
+					for _, b := range chosen {
+						atomic.StoreInt32(&b.runtimeInjected, 1)
+					}
+				}
+			}
+		}
+		if logger != nil {
+			if err != nil {
+				logger.Printf("Inject field '%s' of '%v' error, %v\n", inject.fieldName, inject.class, err)
+			} else {
+				logger.Printf("Inject field '%s' of '%v'\n", inject.fieldName, inject.class)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for _, inject := range t.properties {
+		err := inject.inject(&value, properties)
+		if logger != nil {
+			if err != nil {
+				logger.Printf("Inject property '%s' to field '%s' of '%v' error, %v\n", inject.propertyName, inject.fieldName, inject.class, err)
+			} else {
+				logger.Printf("Inject property '%s' to field '%s' of '%v'\n", inject.propertyName, inject.fieldName, inject.class)
 			}
 		}
+		if err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -688,14 +1764,11 @@ func (t *context) Inject(obj interface{}) error {
 func (t *context) getBean(ifaceType reflect.Type) []beanlist {
 
 	switch ifaceType.Kind() {
-	case reflect.Ptr, reflect.Func:
-		return t.searchAndCacheObjectRecursive(ifaceType)
-
 	case reflect.Interface:
 		return t.searchAndCacheInterfaceCandidatesRecursive(ifaceType)
 
 	default:
-		return nil
+		return t.searchAndCacheObjectRecursive(ifaceType)
 	}
 }
 
@@ -768,23 +1841,34 @@ func indent(n int) string {
 
 func (t *context) constructBean(bean *bean, stack []*bean) (err error) {
 
+	if t.progress != nil {
+		defer func() {
+			if err == nil {
+				t.progress.constructed(bean)
+			}
+		}()
+	}
+
 	defer func() {
+		if t.disableRecover {
+			return
+		}
 		if r := recover(); r != nil {
 			err = errors.Errorf("construct bean '%s' with type '%v' recovered with error %v", bean.name, bean.beanDef.classPtr, r)
 		}
 	}()
 
-	if bean.lifecycle == BeanInitialized {
+	if bean.Lifecycle() == BeanInitialized {
 		return nil
 	}
 
 	_, isFactoryBean := bean.obj.(FactoryBean)
 	initializer, hasConstructor := bean.obj.(InitializingBean)
-	if verbose != nil {
-		verbose.Printf("%sConstruct Bean '%s' with type '%v', isFactoryBean=%v, hasFactory=%v, hasObject=%v, hasConstructor=%v\n", indent(len(stack)), bean.name, bean.beanDef.classPtr, isFactoryBean, bean.beenFactory != nil, bean.obj != nil, hasConstructor)
+	if t.logger() != nil {
+		t.logger().Printf("%sConstruct Bean '%s' with type '%v', isFactoryBean=%v, hasFactory=%v, hasObject=%v, hasConstructor=%v\n", indent(len(stack)), bean.name, bean.beanDef.classPtr, isFactoryBean, bean.beenFactory != nil, bean.obj != nil, hasConstructor)
 	}
 
-	if bean.lifecycle == BeanConstructing {
+	if bean.Lifecycle() == BeanConstructing {
 		for i, b := range stack {
 			if b == bean {
 				// cycle dependency detected
@@ -792,7 +1876,7 @@ func (t *context) constructBean(bean *bean, stack []*bean) (err error) {
 			}
 		}
 	}
-	bean.lifecycle = BeanConstructing
+	bean.setLifecycle(BeanConstructing)
 	bean.ctorMu.Lock()
 	defer func() {
 		bean.ctorMu.Unlock()
@@ -802,16 +1886,16 @@ func (t *context) constructBean(bean *bean, stack []*bean) (err error) {
 		if err := t.constructBean(factoryDep.factory.bean, append(stack, bean)); err != nil {
 			return err
 		}
-		if verbose != nil {
-			verbose.Printf("%sFactoryDep (%v).Object()\n", indent(len(stack)+1), factoryDep.factory.factoryClassPtr)
+		if t.logger() != nil {
+			t.logger().Printf("%sFactoryDep (%v).Object()\n", indent(len(stack)+1), factoryDep.factory.factoryClassPtr)
 		}
 		bean, created, err := factoryDep.factory.ctor()
 		if err != nil {
 			return errors.Errorf("factory ctor '%v' failed, %v", factoryDep.factory.factoryClassPtr, err)
 		}
 		if created {
-			if verbose != nil {
-				verbose.Printf("%sDep Created Bean %s with type '%v'\n", indent(len(stack)+1), bean.name, bean.beanDef.classPtr)
+			if t.logger() != nil {
+				t.logger().Printf("%sDep Created Bean %s with type '%v'\n", indent(len(stack)+1), bean.name, bean.beanDef.classPtr)
 			}
 			t.registry.addBean(factoryDep.factory.factoryBean.ObjectType(), bean)
 		}
@@ -831,8 +1915,8 @@ func (t *context) constructBean(bean *bean, stack []*bean) (err error) {
 		if err := t.constructBean(bean.beenFactory.bean, append(stack, bean)); err != nil {
 			return err
 		}
-		if verbose != nil {
-			verbose.Printf("%s(%v).Object()\n", indent(len(stack)), bean.beenFactory.factoryClassPtr)
+		if t.logger() != nil {
+			t.logger().Printf("%s(%v).Object()\n", indent(len(stack)), bean.beenFactory.factoryClassPtr)
 		}
 		_, _, err := bean.beenFactory.ctor() // always new
 		if err != nil {
@@ -847,37 +1931,57 @@ func (t *context) constructBean(bean *bean, stack []*bean) (err error) {
 	// inject properties
 	if len(bean.beanDef.properties) > 0 {
 		value := bean.valuePtr.Elem()
+		properties := bean.scopedProperties(t.properties)
 		for _, propertyDef := range bean.beanDef.properties {
-			if verbose != nil {
-				if propertyDef.defaultValue != "" {
-					verbose.Printf("%sProperty '%s' default '%s'\n", indent(len(stack)+1), propertyDef.propertyName, propertyDef.defaultValue)
+			if t.logger() != nil {
+				if propertyDef.sensitive {
+					t.logger().Printf("%sProperty '%s' sensitive\n", indent(len(stack)+1), bean.propertyPrefix+propertyDef.propertyName)
+				} else if propertyDef.defaultValue != "" {
+					t.logger().Printf("%sProperty '%s' default '%s'\n", indent(len(stack)+1), bean.propertyPrefix+propertyDef.propertyName, propertyDef.defaultValue)
 				} else {
-					verbose.Printf("%sProperty '%s'\n", indent(len(stack)+1), propertyDef.propertyName)
+					t.logger().Printf("%sProperty '%s'\n", indent(len(stack)+1), bean.propertyPrefix+propertyDef.propertyName)
 				}
 			}
-			err = propertyDef.inject(&value, t.properties)
+			err = propertyDef.inject(&value, properties)
 			if err != nil {
-				return errors.Errorf("property '%s' injection in bean '%s' failed, %s, %v", propertyDef.propertyName, bean.name, getStackInfo(reverseStack(append(stack, bean)), " required by "), err)
+				return errors.Errorf("property '%s' injection in bean '%s' failed, %s, %v", bean.propertyPrefix+propertyDef.propertyName, bean.name, getStackInfo(reverseStack(append(stack, bean)), " required by "), err)
 			}
 		}
+		bean.bindings.Store(t.propertyBindings(bean))
 	}
 
 	if hasConstructor {
-		if verbose != nil {
-			verbose.Printf("%sPostConstruct Bean '%s' with type '%v'\n", indent(len(stack)), bean.name, bean.beanDef.classPtr)
+		if t.logger() != nil {
+			t.logger().Printf("%sPostConstruct Bean '%s' with type '%v'\n", indent(len(stack)), bean.name, bean.beanDef.classPtr)
+		}
+		postConstruct := initializer.PostConstruct
+		if t.goroutines != nil {
+			postConstruct = func() error {
+				return t.goroutines.track(bean, initializer.PostConstruct)
+			}
 		}
-		if err := initializer.PostConstruct(); err != nil {
+		if err := postConstruct(); err != nil {
+			// track the bean to report on close, Destroy is skipped since PostConstruct did not complete
+			t.addDisposable(bean)
 			return errors.Errorf("post construct failed %s, %v", getStackInfo(reverseStack(append(stack, bean)), " required by "), err)
 		}
 	}
 
+	atomic.StoreInt32(&bean.postConstructed, 1)
 	t.addDisposable(bean)
-	bean.lifecycle = BeanInitialized
+	bean.setLifecycle(BeanInitialized)
 	return nil
 }
 
 func (t *context) addDisposable(bean *bean) {
-	if _, ok := bean.obj.(DisposableBean); ok {
+	t.disposablesMu.Lock()
+	defer t.disposablesMu.Unlock()
+	if bean.disposable {
+		return
+	}
+	switch bean.obj.(type) {
+	case DisposableBean, DisposableWithReasonBean, StoppableBean, DrainableBean:
+		bean.disposable = true
 		t.disposables = append(t.disposables, bean)
 	}
 }
@@ -885,13 +1989,22 @@ func (t *context) addDisposable(bean *bean) {
 func (t *context) postConstruct(lists... []*bean) (err error) {
 
 	defer func() {
+		if t.disableRecover {
+			return
+		}
 		if r := recover(); r != nil {
 			err = errors.Errorf("post construct recover on error, %v\n", r)
 		}
 	}()
 
+	parallel := t.parallel != nil && t.goroutines == nil
 	for _, list := range lists {
-		if err = t.constructBeanList(list, nil); err != nil {
+		if parallel {
+			err = t.constructParallel(list, t.parallel.Workers)
+		} else {
+			err = t.constructBeanList(list, nil)
+		}
+		if err != nil {
 			return err
 		}
 	}
@@ -900,35 +2013,88 @@ func (t *context) postConstruct(lists... []*bean) (err error) {
 }
 
 // destroy in reverse initialization order
-func (t *context) Close() (err error) {
+func (t *context) Close() error {
+	return t.closeWithReason(ShutdownReason)
+}
+
+func (t *context) CloseWithContext(ctx stdcontext.Context) error {
+	return t.closeWithContext(ctx, ShutdownReason)
+}
+
+func (t *context) closeWithReason(reason CloseReason) error {
+	return t.closeWithContext(stdcontext.Background(), reason)
+}
+
+func (t *context) closeWithContext(ctx stdcontext.Context, reason CloseReason) error {
+	return multipleErr(t.closeErrors(ctx, reason))
+}
+
+/**
+Closes the context once within the deadline of ctx: children, leader election, consumers and close phases of beans.
+Everything abandoned because of ctx is reported by the single CloseCanceledError holding other errors.
+*/
+func (t *context) closeErrors(ctx stdcontext.Context, reason CloseReason) (listErr []error) {
 
 	defer func() {
 		if r := recover(); r != nil {
-			err = errors.Errorf("context close recover error: %v", r)
+			listErr = append(listErr, errors.Errorf("context close recover error: %v", r))
 		}
 	}()
 
-	var listErr []error
 	t.closeOnce.Do(func() {
 
+		t.shutdown.fire()
+		unregisterNamed(t)
+		if t.parent != nil {
+			t.parent.detachExtension(t)
+		}
+
+		canceled := &CloseCanceledError{}
+		childReason := ParentCloseReason
+		if reason == StartupFailureReason {
+			childReason = StartupFailureReason
+		}
 		for _, child := range t.children {
-			if err := child.Close(); err != nil {
+			err := closeChild(ctx, child, childReason)
+			var childCanceled *CloseCanceledError
+			if errors.As(err, &childCanceled) {
+				canceled.merge(childCanceled)
+				listErr = append(listErr, childCanceled.Errors...)
+			} else if err != nil {
 				listErr = append(listErr, err)
 			}
 		}
 
-		n := len(t.disposables)
-		for j := n - 1; j >= 0; j-- {
-			if err := t.destroyBean(t.disposables[j]); err != nil {
-				listErr = append(listErr, err)
-			}
+		listErr = append(listErr, t.stopLeaderElection(ctx, t.closeTimeouts.Stop, canceled)...)
+		listErr = append(listErr, t.stopConsumers(ctx, t.closeTimeouts.Stop, canceled)...)
+		listErr = append(listErr, t.closePhase(ctx, StopPhase, t.closeTimeouts.Stop, t.stopBean, canceled)...)
+		listErr = append(listErr, t.closePhase(ctx, DrainPhase, t.closeTimeouts.Drain, t.drainBean, canceled)...)
+
+		listErr = append(listErr, t.closePools(reason)...)
+		listErr = append(listErr, t.closePhase(ctx, DisposePhase, t.closeTimeouts.Dispose, func(b *bean, _ stdcontext.Context) error {
+			return t.destroyBean(b, reason)
+		}, canceled)...)
+
+		if t.goroutines != nil {
+			listErr = append(listErr, t.goroutines.check(t.leakDetector.Grace)...)
+		}
+
+		t.releaseConsumers()
+
+		if t.releaseOnClose {
+			t.release()
+		}
+
+		if canceled.Err != nil {
+			canceled.Errors = listErr
+			listErr = []error{canceled}
 		}
 	})
 
-	return multipleErr(listErr)
+	return listErr
 }
 
-func (t *context) destroyBean(b *bean) (err error) {
+func (t *context) destroyBean(b *bean, reason CloseReason) (err error) {
 
 	defer func() {
 		if r := recover(); r != nil {
@@ -936,24 +2102,39 @@ func (t *context) destroyBean(b *bean) (err error) {
 		}
 	}()
 
-	if b.lifecycle != BeanInitialized {
+	switch {
+	case b.Lifecycle() == BeanConstructing && atomic.LoadInt32(&b.postConstructed) == 0:
+		skipOnClose(b, "destroy", "PostConstruct did not complete")
+		return nil
+	case b.Lifecycle() != BeanInitialized:
 		return nil
 	}
 
-	b.lifecycle = BeanDestroying
-	if verbose != nil {
-		verbose.Printf("Destroy bean '%s' with type '%v'\n", b.name, b.beanDef.classPtr)
+	b.setLifecycle(BeanDestroying)
+	if t.logger() != nil {
+		t.logger().Printf("Destroy bean '%s' with type '%v', reason %v\n", b.name, b.beanDef.classPtr, reason)
 	}
-	if dis, ok := b.obj.(DisposableBean); ok {
-		if e := dis.Destroy(); e != nil {
-			err = e
-		} else {
-			b.lifecycle = BeanDestroyed
-		}
+	if e := destroyObject(b.obj, reason); e != nil {
+		err = e
+	} else {
+		b.setLifecycle(BeanDestroyed)
 	}
 	return
 }
 
+/**
+Calls DestroyWithReason if the object implements DisposableWithReasonBean, otherwise Destroy of DisposableBean
+*/
+func destroyObject(obj interface{}, reason CloseReason) error {
+	if dis, ok := obj.(DisposableWithReasonBean); ok {
+		return dis.DestroyWithReason(reason)
+	}
+	if dis, ok := obj.(DisposableBean); ok {
+		return dis.Destroy()
+	}
+	return nil
+}
+
 func multipleErr(err []error) error {
 	switch len(err) {
 	case 0:
@@ -986,13 +2167,12 @@ func (t *context) searchAndCacheInterfaceCandidatesRecursive(ifaceType reflect.T
 	for ctx := t; ctx != nil; ctx = ctx.parent {
 		// first lookup in the registry
 		if list, ok := ctx.registry.findByType(ifaceType); !ok {
-			list = ctx.searchInterfaceCandidates(ifaceType)
+			// cache in registry
+			// even empty list, so we would not come here again
+			list = ctx.registry.cacheBeanList(ifaceType, ctx.searchInterfaceCandidates(ifaceType))
 			if len(list) > 0 {
 				candidates = append(candidates, beanlist{ level: level, list: list })
 			}
-			// cache in registry
-			// even empty list, so we would not come here again
-			ctx.registry.addBeanList(ifaceType, list)
 		} else if len(list) > 0 {
 			candidates = append(candidates, beanlist{ level: level, list: list })
 		}
@@ -1003,7 +2183,7 @@ func (t *context) searchAndCacheInterfaceCandidatesRecursive(ifaceType reflect.T
 
 func (t *context) searchInterfaceCandidates(ifaceType reflect.Type) []*bean {
 	var candidates []*bean
-	for _, list := range t.core {
+	for _, list := range t.coreBeans() {
 		if len(list) > 0 && list[0].beanDef.implements(ifaceType) {
 			candidates = append(candidates, list...)
 		}
@@ -1035,7 +2215,19 @@ func (t *context) Properties() Properties {
 }
 
 func (t *context) String() string {
-	return fmt.Sprintf("Context [hasParent=%v, types=%d, destructors=%d]", t.parent != nil, len(t.core), len(t.disposables))
+	if t.stringFormat != nil {
+		return t.stringFormat.format(t)
+	}
+	return fmt.Sprintf("Context [hasParent=%v, types=%d, destructors=%d]", t.parent != nil, len(t.coreBeans()), t.destructors())
+}
+
+/**
+Returns the number of disposables, they are added at runtime by construction on demand and Promote
+*/
+func (t *context) destructors() int {
+	t.disposablesMu.Lock()
+	defer t.disposablesMu.Unlock()
+	return len(t.disposables)
 }
 
 type childContext struct {
@@ -1065,25 +2257,45 @@ func (t *childContext) Role() string {
 
 func (t *childContext) Object() (ctx Context, err error) {
 	t.extendOnes.Do(func() {
-		t.ctx, t.err = t.Parent.Extend(t.scan...)
+		t.ctx, t.err = t.Parent.Extend(append([]interface{}{childRole(t.role)}, t.scan...)...)
 	})
 	return t.ctx, t.err
 }
 
-func (t *childContext) Close() (err error) {
+func (t *childContext) Close() error {
+	return t.closeWithContext(stdcontext.Background(), ShutdownReason)
+}
+
+func (t *childContext) closeWithContext(ctx stdcontext.Context, reason CloseReason) (err error) {
 	t.closeOnes.Do(func() {
 		if t.ctx != nil {
-			err = t.ctx.Close()
+			err = closeChild(ctx, t.ctx, reason)
 		}
 	})
 	return
 }
 
+/**
+Closes the child within the deadline of ctx passing the reason to disposable beans if the child supports it
+*/
+func closeChild(ctx stdcontext.Context, child interface{ Close() error }, reason CloseReason) error {
+	if c, ok := child.(interface {
+		closeWithContext(stdcontext.Context, CloseReason) error
+	}); ok {
+		return c.closeWithContext(ctx, reason)
+	}
+	return child.Close()
+}
+
 
 func (t *childContext) String() string {
 	return fmt.Sprintf("ChildContext [created=%v, role=%s, beans=%d]", t.ctx != nil, t.role, len(t.scan))
 }
 
+func (t *context) Role() string {
+	return t.role
+}
+
 func (t *context) Children() []ChildContext {
 	return t.children
 }
@@ -11,12 +11,15 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/codeallergy/glue"
 	"log"
+	"net/http"
 	"os"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"testing"
+	"testing/fstest"
 )
 
 func TestCreateNil(t *testing.T) {
@@ -437,3 +440,246 @@ func TestRequestMultithreading(t *testing.T) {
 	wg.Wait()
 
 }
+
+type NamingPlugin interface {
+	Plugin() string
+}
+
+type SearchPlugin struct {
+}
+
+func (t *SearchPlugin) Plugin() string {
+	return "search"
+}
+
+type IndexPlugin struct {
+}
+
+func (t *IndexPlugin) Plugin() string {
+	return "index"
+}
+
+type namingHolder struct {
+	Plugins map[string]NamingPlugin `inject`
+}
+
+func TestNamingStrategy(t *testing.T) {
+
+	holder := &namingHolder{}
+	ctx, err := glue.New(
+		glue.Naming(glue.CamelCaseNaming),
+		&SearchPlugin{},
+		holder,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.Equal(t, 1, len(ctx.Lookup("searchPlugin", glue.DefaultLevel)))
+	require.Equal(t, 0, len(ctx.Lookup("*glue_test.SearchPlugin", glue.DefaultLevel)))
+
+	require.Equal(t, 1, len(holder.Plugins))
+	require.NotNil(t, holder.Plugins["searchPlugin"])
+
+	childHolder := &struct {
+		Plugins map[string]NamingPlugin `inject:"level=1"`
+	}{}
+	child, err := ctx.Extend(&IndexPlugin{}, childHolder)
+	require.NoError(t, err)
+	defer child.Close()
+	require.NotNil(t, childHolder.Plugins["indexPlugin"])
+}
+
+type patternStorage struct {
+	name string
+}
+
+func (t *patternStorage) BeanName() string {
+	return t.name
+}
+
+func TestLookupPattern(t *testing.T) {
+
+	holder := &struct {
+		Beans []glue.NamedBean `inject`
+	}{}
+
+	ctx, err := glue.New(
+		&patternStorage{name: "storage.users"},
+		&patternStorage{name: "storage.orders"},
+		&patternStorage{name: "cache.users"},
+		holder,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	list := ctx.LookupPattern("storage.*", glue.DefaultLevel)
+	require.Equal(t, 2, len(list))
+	require.Equal(t, "storage.orders", list[0].Name())
+	require.Equal(t, "storage.users", list[1].Name())
+
+	list = ctx.LookupPattern("regexp:\\.users$", glue.DefaultLevel)
+	require.Equal(t, 2, len(list))
+	require.Equal(t, "cache.users", list[0].Name())
+
+	require.Equal(t, 0, len(ctx.LookupPattern("[", glue.DefaultLevel)))
+	require.Equal(t, 0, len(ctx.LookupPattern("regexp:(", glue.DefaultLevel)))
+
+	child, err := ctx.Extend()
+	require.NoError(t, err)
+	defer child.Close()
+
+	require.Equal(t, 2, len(child.LookupPattern("storage.*", glue.DefaultLevel)))
+	require.Equal(t, 0, len(child.LookupPattern("storage.*", glue.LocalLevel)))
+}
+
+type bundleBean struct {
+	Name string `value:"bundle.name"`
+}
+
+type orderedScannerImpl struct {
+	scannerImpl
+	order int
+}
+
+func (t orderedScannerImpl) ScanOrder() int {
+	return t.order
+}
+
+func TestScannerOrder(t *testing.T) {
+
+	bundle := func(name string) scannerImpl {
+		return scannerImpl{arr: []interface{}{
+			glue.PropertySource{Map: map[string]interface{}{"bundle.name": name}},
+		}}
+	}
+
+	// declared order, the last property source wins
+	b := &bundleBean{}
+	ctx, err := glue.New(bundle("storage"), bundle("metrics"), b)
+	require.NoError(t, err)
+	require.Equal(t, "metrics", b.Name)
+	ctx.Close()
+
+	b = &bundleBean{}
+	ctx, err = glue.New(
+		glue.ScannerOrder(2, bundle("metrics")),
+		b,
+		orderedScannerImpl{scannerImpl: bundle("tracing"), order: 3},
+		glue.ScannerOrder(1, bundle("storage")),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "tracing", b.Name)
+	ctx.Close()
+
+	b = &bundleBean{}
+	ctx, err = glue.New(
+		glue.ScannerOrder(2, bundle("metrics")),
+		glue.ScannerOrder(1, bundle("storage")),
+		b,
+	)
+	require.NoError(t, err)
+	require.Equal(t, "metrics", b.Name)
+	ctx.Close()
+}
+
+type namedBundle struct {
+	scannerImpl
+	name string
+}
+
+func (t namedBundle) ScannerName() string {
+	return t.name
+}
+
+func TestScannerNamespace(t *testing.T) {
+
+	bundle := func(value string) scannerImpl {
+		files := fstest.MapFS{
+			"app.properties": &fstest.MapFile{Data: []byte("bundle.name = " + value + "\n")},
+		}
+		return scannerImpl{arr: []interface{}{
+			glue.ResourceSource{Name: "resources", AssetNames: []string{"app.properties"}, AssetFiles: http.FS(files)},
+			glue.PropertySource{Path: "resources:app.properties"},
+		}}
+	}
+
+	b := &bundleBean{}
+	ctx, err := glue.New(
+		glue.Namespace("billing", bundle("billing")),
+		namedBundle{scannerImpl: bundle("shipping"), name: "shipping"},
+		b,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.Equal(t, "shipping", b.Name)
+
+	_, ok := ctx.Resource("billing:app.properties")
+	require.True(t, ok)
+	_, ok = ctx.Resource("shipping:app.properties")
+	require.True(t, ok)
+	_, ok = ctx.Resource("resources:app.properties")
+	require.False(t, ok)
+
+	// without namespaces bundles clash on the same resource
+	_, err = glue.New(bundle("billing"), bundle("shipping"), &bundleBean{})
+	require.Error(t, err)
+}
+
+func TestInjectAll(t *testing.T) {
+
+	ctx, err := glue.New(
+		glue.PropertySource{Map: map[string]interface{}{
+			"a.name": "first",
+			"b.name": "second",
+		}},
+		&storageImpl{},
+		log.Default(),
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	type handler struct {
+		Storage Storage `inject`
+		Name    string  `value:"name,default=none"`
+	}
+
+	first, second, third := &handler{}, &handler{}, &handler{}
+	require.NoError(t, ctx.InjectAll(glue.WithPrefix("a", first), glue.WithPrefix("b", second), third))
+	for _, h := range []*handler{first, second, third} {
+		require.NotNil(t, h.Storage)
+	}
+	require.Equal(t, "first", first.Name)
+	require.Equal(t, "second", second.Name)
+	require.Equal(t, "none", third.Name)
+
+	err = ctx.InjectAll(&handler{}, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "position 1")
+
+	err = ctx.InjectAll(&handler{}, &struct {
+		Missing *handler `inject`
+	}{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "position 1")
+}
+
+func TestDeterministicCore(t *testing.T) {
+
+	ctx, err := glue.New(&storageImpl{}, log.Default(), &configServiceImpl{}, &userServiceImpl{})
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	var names []string
+	for _, typ := range ctx.Core() {
+		names = append(names, typ.String())
+	}
+	require.True(t, sort.StringsAreSorted(names))
+
+	props := glue.NewProperties()
+	for _, key := range []string{"z.key", "a.key", "m.key", "a.b"} {
+		props.Set(key, "value")
+	}
+	require.Equal(t, []string{"a.b", "a.key", "m.key", "z.key"}, props.Keys())
+	require.Equal(t, []string{"b", "key"}, props.Sub("a").Keys())
+}
@@ -0,0 +1,207 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"github.com/pkg/errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/**
+Cron schedule injected from properties by value tag and validated at injection time.
+Accepts five fields 'minute hour day-of-month month day-of-week' with '*', lists, ranges, steps and names (JAN, MON),
+descriptors @yearly, @monthly, @weekly, @daily, @hourly and '@every <duration>'.
+
+Example:
+	type cleaner struct {
+		Schedule glue.CronSpec `value:"cleaner.schedule,default=@hourly"`
+	}
+
+	next := t.Schedule.Next(time.Now())
+*/
+type CronSpec struct {
+	expr   string
+	every  time.Duration
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+
+	/**
+	Day of month or day of week is '*', otherwise the day matches any of them
+	*/
+	domStar, dowStar bool
+}
+
+var cronSpecClass = reflect.TypeOf(CronSpec{})
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var cronMonths = []string{"", "JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}
+var cronDays = []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}
+
+/**
+Parses and validates the cron expression
+*/
+func ParseCronSpec(expr string) (CronSpec, error) {
+	spec := CronSpec{expr: strings.TrimSpace(expr)}
+	fields := spec.expr
+	if strings.HasPrefix(fields, "@every ") {
+		d, err := time.ParseDuration(strings.TrimSpace(fields[len("@every "):]))
+		if err != nil || d <= 0 {
+			return CronSpec{}, errors.Errorf("invalid cron spec '%s', positive duration expected", expr)
+		}
+		spec.every = d
+		return spec, nil
+	}
+	if d, ok := cronDescriptors[fields]; ok {
+		fields = d
+	}
+	list := strings.Fields(fields)
+	if len(list) != 5 {
+		return CronSpec{}, errors.Errorf("invalid cron spec '%s', expected 5 fields but found %d", expr, len(list))
+	}
+	var err error
+	if spec.minute, err = parseCronField(list[0], 0, 59, nil); err != nil {
+		return CronSpec{}, errors.Errorf("invalid minute of cron spec '%s', %v", expr, err)
+	}
+	if spec.hour, err = parseCronField(list[1], 0, 23, nil); err != nil {
+		return CronSpec{}, errors.Errorf("invalid hour of cron spec '%s', %v", expr, err)
+	}
+	if spec.dom, err = parseCronField(list[2], 1, 31, nil); err != nil {
+		return CronSpec{}, errors.Errorf("invalid day of month of cron spec '%s', %v", expr, err)
+	}
+	if spec.month, err = parseCronField(list[3], 1, 12, cronMonths); err != nil {
+		return CronSpec{}, errors.Errorf("invalid month of cron spec '%s', %v", expr, err)
+	}
+	if spec.dow, err = parseCronField(list[4], 0, 7, cronDays); err != nil {
+		return CronSpec{}, errors.Errorf("invalid day of week of cron spec '%s', %v", expr, err)
+	}
+	// both 0 and 7 are Sunday
+	if spec.dow&(1<<7) != 0 {
+		spec.dow |= 1
+	}
+	spec.domStar = strings.HasPrefix(list[2], "*")
+	spec.dowStar = strings.HasPrefix(list[4], "*")
+	return spec, nil
+}
+
+/**
+Parses list of ranges with optional steps in to the bit set of values
+*/
+func parseCronField(field string, min, max int, names []string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, step := part, 1
+		if i := strings.IndexByte(part, '/'); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, errors.Errorf("invalid step in '%s'", part)
+			}
+			rng, step = part[:i], n
+		}
+		lo, hi := min, max
+		if rng != "*" {
+			bounds := strings.SplitN(rng, "-", 2)
+			var err error
+			if lo, err = cronValue(bounds[0], names); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if len(bounds) == 2 {
+				if hi, err = cronValue(bounds[1], names); err != nil {
+					return 0, err
+				}
+			} else if step > 1 {
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, errors.Errorf("'%s' is out of range %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func cronValue(s string, names []string) (int, error) {
+	for i, name := range names {
+		if name != "" && strings.EqualFold(s, name) {
+			return i, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, errors.Errorf("invalid value '%s'", s)
+	}
+	return v, nil
+}
+
+/**
+Returns the expression of the spec
+*/
+func (t CronSpec) String() string {
+	return t.expr
+}
+
+/**
+Returns true if the spec is not parsed, for example for the optional property without value
+*/
+func (t CronSpec) IsZero() bool {
+	return t.expr == ""
+}
+
+/**
+Returns the next activation time after tm in the location of tm, zero time if there is no activation in five years
+*/
+func (t CronSpec) Next(tm time.Time) time.Time {
+	if t.IsZero() {
+		return time.Time{}
+	}
+	if t.every > 0 {
+		return tm.Add(t.every)
+	}
+	tm = tm.Truncate(time.Minute).Add(time.Minute)
+	limit := tm.AddDate(5, 0, 0)
+	for tm.Before(limit) {
+		switch {
+		case t.month&(1<<uint(tm.Month())) == 0:
+			tm = time.Date(tm.Year(), tm.Month()+1, 1, 0, 0, 0, 0, tm.Location())
+		case !t.dayMatches(tm):
+			tm = time.Date(tm.Year(), tm.Month(), tm.Day()+1, 0, 0, 0, 0, tm.Location())
+		case t.hour&(1<<uint(tm.Hour())) == 0:
+			tm = tm.Truncate(time.Hour).Add(time.Hour)
+		case t.minute&(1<<uint(tm.Minute())) == 0:
+			tm = tm.Add(time.Minute)
+		default:
+			return tm
+		}
+	}
+	return time.Time{}
+}
+
+func (t CronSpec) dayMatches(tm time.Time) bool {
+	dom := t.dom&(1<<uint(tm.Day())) != 0
+	dow := t.dow&(1<<uint(tm.Weekday())) != 0
+	if t.domStar || t.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
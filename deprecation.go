@@ -0,0 +1,124 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"log"
+	"reflect"
+	"sort"
+)
+
+/**
+Warnings of the context like deprecations, static analysis and skipped close callbacks, disabled by default
+*/
+var warnings *log.Logger
+
+/**
+Use this function to enable, redirect or disable warnings of the context, returns previous logger.
+
+Example:
+	glue.Warnings(log.New(os.Stderr, "glue: ", log.LstdFlags))
+*/
+func Warnings(log *log.Logger) (prev *log.Logger) {
+	prev, warnings = warnings, log
+	return
+}
+
+func warnf(format string, args ...interface{}) {
+	if warnings != nil {
+		warnings.Printf(format, args...)
+	}
+	if verbose != nil && verbose != warnings {
+		verbose.Printf("Warning: "+format, args...)
+	}
+}
+
+/**
+This interface used by beans that are going to be removed, context warns about them on creation.
+*/
+var DeprecatedBeanClass = reflect.TypeOf((*DeprecatedBean)(nil)).Elem()
+
+type DeprecatedBean interface {
+
+	/**
+	Returns what to use instead of the bean
+	*/
+	DeprecationNotice() string
+}
+
+/**
+Deprecated property keys mapped to new ones, registered in the scan list
+*/
+type propertyAliases map[string]string
+
+/**
+Maps deprecated property keys to new ones, value of the old key is used for the new key with the warning on creation of the context.
+If both keys are set, the new one wins and the old one is reported as ignored.
+
+Example:
+	glue.New(
+		glue.DeprecatedProperties(map[string]string{
+			"db.url": "datasource.url",
+		}),
+		glue.PropertySource{Path: "resources:application.properties"},
+		&service{},
+	)
+*/
+func DeprecatedProperties(aliases map[string]string) interface{} {
+	m := make(propertyAliases, len(aliases))
+	for old, key := range aliases {
+		m[old] = key
+	}
+	return m
+}
+
+/**
+Copies values of deprecated keys to new keys, applied keys are added to done to warn once
+*/
+func (t *context) applyPropertyAliases(aliases propertyAliases, done map[string]bool) {
+	olds := make([]string, 0, len(aliases))
+	for old := range aliases {
+		olds = append(olds, old)
+	}
+	sort.Strings(olds)
+	for _, old := range olds {
+		if done[old] {
+			continue
+		}
+		value, ok := t.properties.Get(old)
+		if !ok {
+			continue
+		}
+		done[old] = true
+		key := aliases[old]
+		if _, exists := t.properties.Get(key); exists {
+			warnf("Property '%s' is deprecated and ignored, '%s' is set\n", old, key)
+			continue
+		}
+		warnf("Property '%s' is deprecated, use '%s'\n", old, key)
+		t.properties.Set(key, value)
+		if t.properties.IsSensitive(old) {
+			t.properties.MarkSensitive(key)
+		}
+		if origin, ok := t.propertyOrigins.Load(old); ok {
+			t.propertyOrigins.Store(key, origin)
+		}
+	}
+}
+
+/**
+Warns about deprecated beans of the current context
+*/
+func (t *context) warnDeprecatedBeans() {
+	t.eachBean(func(b Bean) bool {
+		if impl, ok := b.(*bean); ok {
+			if d, ok := impl.obj.(DeprecatedBean); ok {
+				warnf("Bean '%s' with type '%v' is deprecated, %s\n", impl.name, impl.beanDef.classPtr, d.DeprecationNotice())
+			}
+		}
+		return true
+	})
+}
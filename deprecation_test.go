@@ -0,0 +1,59 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"bytes"
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"log"
+	"testing"
+)
+
+type legacyCache struct {
+}
+
+func (t *legacyCache) DeprecationNotice() string {
+	return "use redisCache instead"
+}
+
+type datasource struct {
+	Url  string `value:"datasource.url"`
+	User string `value:"datasource.user"`
+}
+
+func TestDeprecations(t *testing.T) {
+
+	var buf bytes.Buffer
+	prev := glue.Warnings(log.New(&buf, "", 0))
+	defer glue.Warnings(prev)
+	require.Nil(t, prev, "warnings are disabled by default")
+
+	ds := &datasource{}
+	ctx, err := glue.New(
+		glue.DeprecatedProperties(map[string]string{
+			"db.url":  "datasource.url",
+			"db.user": "datasource.user",
+		}),
+		glue.PropertySource{Map: map[string]interface{}{
+			"db.url":          "postgres://old",
+			"db.user":         "old",
+			"datasource.user": "new",
+		}},
+		&legacyCache{},
+		ds,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.Equal(t, "postgres://old", ds.Url)
+	require.Equal(t, "new", ds.User)
+
+	out := buf.String()
+	require.Contains(t, out, "Property 'db.url' is deprecated, use 'datasource.url'")
+	require.Contains(t, out, "Property 'db.user' is deprecated and ignored, 'datasource.user' is set")
+	require.Contains(t, out, "is deprecated, use redisCache instead")
+}
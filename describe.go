@@ -0,0 +1,249 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+/**
+This interface used by beans that describe themselves for the report of the assembled application.
+*/
+var DocumentedBeanClass = reflect.TypeOf((*DocumentedBean)(nil)).Elem()
+
+type DocumentedBean interface {
+
+	/**
+	Returns description of the bean, the first line is a summary
+	*/
+	BeanDoc() string
+}
+
+/**
+Description of the bean returned by Context.Describe
+*/
+type BeanDescription struct {
+	Name         string            `json:"name"`
+	Type         string            `json:"type"`
+	Doc          string            `json:"doc,omitempty"`
+	Lifecycle    string            `json:"lifecycle"`
+	Factory      string            `json:"factory,omitempty"`
+	Dependencies []string          `json:"dependencies,omitempty"`
+	Properties   []PropertyBinding `json:"properties,omitempty"`
+}
+
+func (t *context) Describe() []BeanDescription {
+	var list []BeanDescription
+	t.eachBean(func(b Bean) bool {
+		impl, ok := b.(*bean)
+		if !ok || impl.obj == interface{}(t) {
+			return true
+		}
+		d := BeanDescription{
+			Name:       impl.name,
+			Type:       impl.beanDef.classPtr.String(),
+			Lifecycle:  impl.Lifecycle().String(),
+			Properties: impl.Properties(),
+		}
+		if doc, ok := impl.obj.(DocumentedBean); ok {
+			d.Doc = strings.TrimSpace(doc.BeanDoc())
+		}
+		if impl.beenFactory != nil {
+			d.Factory = impl.beenFactory.factoryClassPtr.String()
+		}
+		for _, dep := range impl.dependencies {
+			d.Dependencies = append(d.Dependencies, dep.name)
+		}
+		list = append(list, d)
+		return true
+	})
+	return list
+}
+
+/**
+Writes the markdown report of beans returned by Context.Describe
+
+Example:
+	f, _ := os.Create("components.md")
+	defer f.Close()
+	glue.WriteMarkdown(f, ctx.Describe())
+*/
+func WriteMarkdown(w io.Writer, list []BeanDescription) error {
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, "# Components\n\n")
+	fmt.Fprintf(out, "| Bean | Type | Summary |\n|---|---|---|\n")
+	for _, d := range list {
+		summary := d.Doc
+		if i := strings.IndexByte(summary, '\n'); i >= 0 {
+			summary = summary[:i]
+		}
+		fmt.Fprintf(out, "| %s | `%s` | %s |\n", markdownCell(d.Name), d.Type, markdownCell(summary))
+	}
+	for _, d := range list {
+		fmt.Fprintf(out, "\n## %s\n\n", d.Name)
+		fmt.Fprintf(out, "Type `%s`, %s", d.Type, d.Lifecycle)
+		if d.Factory != "" {
+			fmt.Fprintf(out, ", produced by `%s`", d.Factory)
+		}
+		fmt.Fprintf(out, "\n")
+		if d.Doc != "" {
+			fmt.Fprintf(out, "\n%s\n", d.Doc)
+		}
+		if len(d.Dependencies) > 0 {
+			fmt.Fprintf(out, "\nDepends on:\n")
+			for _, dep := range d.Dependencies {
+				fmt.Fprintf(out, "* %s\n", dep)
+			}
+		}
+		if len(d.Properties) > 0 {
+			fmt.Fprintf(out, "\n| Property | Field | Default | Source |\n|---|---|---|---|\n")
+			for _, p := range d.Properties {
+				fmt.Fprintf(out, "| `%s` | %s | %s | %s |\n", p.Key, p.Field, markdownCell(p.Default), p.Source)
+			}
+		}
+	}
+	return out.Flush()
+}
+
+func markdownCell(s string) string {
+	return strings.ReplaceAll(s, "|", "\\|")
+}
+
+/**
+Description of the application printed by DescribeOnFlag option, read by 'glue describe' developer tool
+*/
+type ApplicationDescription struct {
+	Beans      []BeanDescription `json:"beans"`
+	Properties []PropertyBinding `json:"properties,omitempty"`
+	Resources  []string          `json:"resources,omitempty"`
+}
+
+/**
+Default command line flag of DescribeOnFlag option
+*/
+var DescribeFlag = "--glue-describe"
+
+/**
+Option of the context that prints the JSON description of the application (beans with dependencies, properties schema and resources)
+to stdout and exits if the command line has the flag, so any application built with glue could be inspected without custom commands.
+Consumers are not started, the context is closed before exit. The option is not inherited by contexts created by Extend.
+
+Example:
+	ctx, err := glue.New(
+		glue.DescribeOnFlag{},
+		&storage{},
+		&service{},
+	)
+
+	$ ./app --glue-describe
+	$ glue describe -format markdown ./app
+*/
+type DescribeOnFlag struct {
+
+	/**
+	Command line flag, glue.DescribeFlag if empty
+	*/
+	Flag string
+
+	/**
+	Command line arguments, os.Args[1:] if nil
+	*/
+	Args []string
+
+	/**
+	Output of the description, os.Stdout if nil
+	*/
+	Out io.Writer
+
+	/**
+	Exit function called after the description, os.Exit if nil
+	*/
+	Exit func(code int)
+}
+
+func (t DescribeOnFlag) applyOption(ctx *context) {
+	ctx.describeOnFlag = &t
+}
+
+func (t *DescribeOnFlag) requested() bool {
+	flag, args := t.Flag, t.Args
+	if flag == "" {
+		flag = DescribeFlag
+	}
+	if args == nil && len(os.Args) > 1 {
+		args = os.Args[1:]
+	}
+	for _, arg := range args {
+		if arg == flag {
+			return true
+		}
+	}
+	return false
+}
+
+/**
+Writes the description of the context, closes it and exits
+*/
+func (t *DescribeOnFlag) describe(ctx *context) error {
+	out, exit := t.Out, t.Exit
+	if out == nil {
+		out = os.Stdout
+	}
+	if exit == nil {
+		exit = os.Exit
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	err := enc.Encode(ctx.describeApplication())
+	if closeErr := ctx.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "glue: describe error, %v\n", err)
+		exit(1)
+	} else {
+		exit(0)
+	}
+	return err
+}
+
+/**
+Collects beans, unique property bindings by key and resources of the current context
+*/
+func (t *context) describeApplication() ApplicationDescription {
+	d := ApplicationDescription{Beans: t.Describe()}
+	sort.Slice(d.Beans, func(i, j int) bool {
+		return d.Beans[i].Name < d.Beans[j].Name
+	})
+	keys := make(map[string]bool)
+	for _, b := range d.Beans {
+		for _, p := range b.Properties {
+			if !keys[p.Key] {
+				keys[p.Key] = true
+				d.Properties = append(d.Properties, p)
+			}
+		}
+	}
+	sort.Slice(d.Properties, func(i, j int) bool {
+		return d.Properties[i].Key < d.Properties[j].Key
+	})
+	t.registry.RLock()
+	for source, rc := range t.registry.resourceSources {
+		for name := range rc.resources {
+			d.Resources = append(d.Resources, source+":"+name)
+		}
+	}
+	t.registry.RUnlock()
+	sort.Strings(d.Resources)
+	return d
+}
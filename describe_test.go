@@ -0,0 +1,103 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type documentedStore struct {
+}
+
+func (t *documentedStore) BeanDoc() string {
+	return "Stores orders in postgres.\nOwned by the billing team."
+}
+
+type documentedService struct {
+	Store *documentedStore `inject`
+	Port  int              `value:"service.port,default=8080"`
+}
+
+func (t *documentedService) BeanDoc() string {
+	return "Accepts orders over | HTTP."
+}
+
+func TestDescribe(t *testing.T) {
+
+	ctx, err := glue.New(
+		&documentedStore{},
+		&documentedService{},
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	list := ctx.Describe()
+	require.Equal(t, 2, len(list))
+
+	descriptions := make(map[string]glue.BeanDescription)
+	for _, d := range list {
+		descriptions[d.Type] = d
+	}
+
+	service := descriptions["*glue_test.documentedService"]
+	require.Equal(t, "Accepts orders over | HTTP.", service.Doc)
+	require.Equal(t, "BeanInitialized", service.Lifecycle)
+	require.Equal(t, []string{descriptions["*glue_test.documentedStore"].Name}, service.Dependencies)
+	require.Equal(t, 1, len(service.Properties))
+	require.Equal(t, "service.port", service.Properties[0].Key)
+
+	var buf bytes.Buffer
+	require.NoError(t, glue.WriteMarkdown(&buf, list))
+	report := buf.String()
+	require.Contains(t, report, "# Components")
+	require.Contains(t, report, "| `*glue_test.documentedStore` | Stores orders in postgres. |")
+	require.Contains(t, report, "Accepts orders over \\| HTTP.")
+	require.Contains(t, report, "Owned by the billing team.")
+	require.Contains(t, report, "| `service.port` | Port | 8080 | default |")
+}
+
+func TestDescribeOnFlag(t *testing.T) {
+
+	var buf bytes.Buffer
+	exitCode := -1
+	ctx, err := glue.New(
+		glue.DescribeOnFlag{
+			Args: []string{"-v", glue.DescribeFlag},
+			Out:  &buf,
+			Exit: func(code int) {
+				exitCode = code
+			},
+		},
+		&documentedStore{},
+		&documentedService{},
+	)
+	require.NoError(t, err)
+	require.NotNil(t, ctx)
+	require.Equal(t, 0, exitCode)
+
+	var d glue.ApplicationDescription
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &d))
+	require.Equal(t, 2, len(d.Beans))
+	require.Equal(t, 1, len(d.Properties))
+	require.Equal(t, "service.port", d.Properties[0].Key)
+	require.Equal(t, "8080", d.Properties[0].Default)
+
+	buf.Reset()
+	ctx, err = glue.New(
+		glue.DescribeOnFlag{
+			Args: []string{"-v"},
+			Out:  &buf,
+		},
+		&documentedStore{},
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+	require.Equal(t, 0, buf.Len())
+}
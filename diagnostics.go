@@ -0,0 +1,148 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+/**
+Option of the context that writes diagnostics bundle to the file on failure of the context creation.
+The bundle is a JSON document with the error, bean list, dependency graph, last verbose events and properties with sensitive values masked.
+Events is the number of last verbose events kept for the bundle, 100 if zero.
+
+Example:
+	glue.New(
+		glue.Diagnostics{Path: "/var/log/app/glue-diagnostics.json"},
+		&server{},
+	)
+*/
+type Diagnostics struct {
+	Path   string
+	Events int
+}
+
+func (t Diagnostics) applyOption(ctx *context) {
+	if t.Events == 0 {
+		t.Events = 100
+	}
+	ctx.diagnostics = &t
+}
+
+type diagnostics struct {
+	Error      string            `json:"error"`
+	Time       string            `json:"time"`
+	Beans      []diagnosticBean  `json:"beans"`
+	Events     []string          `json:"events,omitempty"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+type diagnosticBean struct {
+	Name         string   `json:"name"`
+	Type         string   `json:"type"`
+	Lifecycle    string   `json:"lifecycle"`
+	Dependencies []string `json:"dependencies,omitempty"`
+}
+
+/**
+Keeps last lines written to the verbose logger during creation of the context
+*/
+type eventRing struct {
+	sync.Mutex
+	size   int
+	events []string
+}
+
+func (t *eventRing) Write(p []byte) (int, error) {
+	t.Lock()
+	defer t.Unlock()
+	t.events = append(t.events, strings.TrimRight(string(p), "\n"))
+	if len(t.events) > t.size {
+		t.events = t.events[len(t.events)-t.size:]
+	}
+	return len(p), nil
+}
+
+func (t *eventRing) list() []string {
+	t.Lock()
+	defer t.Unlock()
+	return append([]string(nil), t.events...)
+}
+
+/**
+Starts capturing verbose events of the context if the diagnostics bundle is enabled, returns function that stops it.
+Events are written through own logger of the context, so the shared logger is not touched.
+*/
+func (t *context) captureEvents() (*eventRing, func()) {
+	logger := t.logger()
+	if t.diagnostics == nil || logger == nil {
+		return nil, func() {}
+	}
+	ring := &eventRing{size: t.diagnostics.Events}
+	t.eventLog.Store(log.New(io.MultiWriter(logger.Writer(), ring), logger.Prefix(), logger.Flags()))
+	return ring, func() {
+		t.eventLog.Store((*log.Logger)(nil))
+	}
+}
+
+/**
+Writes diagnostics bundle of the failed context to the path of Diagnostics option
+*/
+func (t *context) writeDiagnostics(cause error, events *eventRing) {
+
+	d := &diagnostics{
+		Error: cause.Error(),
+		Time:  time.Now().Format(time.RFC3339),
+	}
+
+	for _, list := range t.coreBeans() {
+		for _, b := range list {
+			db := diagnosticBean{
+				Name:      b.name,
+				Type:      b.beanDef.classPtr.String(),
+				Lifecycle: b.Lifecycle().String(),
+			}
+			for _, dep := range b.dependencies {
+				db.Dependencies = append(db.Dependencies, dep.beanDef.classPtr.String())
+			}
+			d.Beans = append(d.Beans, db)
+		}
+	}
+	sort.Slice(d.Beans, func(i, j int) bool {
+		return d.Beans[i].Type < d.Beans[j].Type
+	})
+
+	if events != nil {
+		d.Events = events.list()
+	}
+
+	keys := t.properties.Keys()
+	if len(keys) > 0 {
+		d.Properties = make(map[string]string)
+		for _, key := range keys {
+			if t.properties.IsSensitive(key) {
+				d.Properties[key] = redactedValue
+			} else {
+				d.Properties[key] = t.properties.GetString(key, "")
+			}
+		}
+	}
+
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err == nil {
+		err = ioutil.WriteFile(t.diagnostics.Path, data, 0600)
+	}
+	if err != nil && t.logger() != nil {
+		t.logger().Printf("Write diagnostics bundle to '%s' error, %v\n", t.diagnostics.Path, err)
+	}
+}
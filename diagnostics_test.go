@@ -0,0 +1,64 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type diagnosticsFailingBean struct {
+	Password string `value:"db.password,sensitive"`
+}
+
+func (t *diagnosticsFailingBean) PostConstruct() error {
+	return errors.New("diagnostics construct error")
+}
+
+func TestDiagnosticsBundle(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "glue-diagnostics.json")
+
+	_, err := glue.New(
+		glue.Diagnostics{Path: path},
+		glue.PropertySource{Map: map[string]interface{}{"db.password": "top-secret", "db.user": "admin"}},
+		&diagnosticsFailingBean{},
+	)
+	require.Error(t, err)
+
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.False(t, strings.Contains(string(data), "top-secret"))
+
+	var bundle struct {
+		Error string `json:"error"`
+		Beans []struct {
+			Type string `json:"type"`
+		} `json:"beans"`
+		Events     []string          `json:"events"`
+		Properties map[string]string `json:"properties"`
+	}
+	require.NoError(t, json.Unmarshal(data, &bundle))
+
+	require.True(t, strings.Contains(bundle.Error, "diagnostics construct error"))
+	require.Equal(t, "admin", bundle.Properties["db.user"])
+	require.Equal(t, "******", bundle.Properties["db.password"])
+	require.NotEmpty(t, bundle.Events)
+
+	var found bool
+	for _, b := range bundle.Beans {
+		if b.Type == "*glue_test.diagnosticsFailingBean" {
+			found = true
+		}
+	}
+	require.True(t, found)
+}
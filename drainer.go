@@ -0,0 +1,127 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	stdcontext "context"
+	"github.com/pkg/errors"
+	"sync"
+	"time"
+)
+
+/**
+Bean coordinating graceful shutdown of in-flight work (requests, jobs) like WaitGroup with timeout.
+Beans acquire the work from the injected Drainer and release it when done, on close the Drainer rejects new work in the stop phase
+and waits for acquired work in the drain phase, so Destroy of beans is called only after in-flight work finished.
+Place the Drainer in the scan list before beans using it, Timeout limits the wait, zero waits up to the Drain timeout of CloseTimeouts.
+
+Example:
+	ctx, err := glue.New(
+		&glue.Drainer{Timeout: 30 * time.Second},
+		&handler{},
+	)
+
+	type handler struct {
+		Drainer *glue.Drainer `inject`
+	}
+
+	func (t *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+		release, ok := t.Drainer.Acquire()
+		if !ok {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		defer release()
+		...
+	}
+*/
+type Drainer struct {
+
+	/**
+	Limit of time to wait for in-flight work on close
+	*/
+	Timeout time.Duration
+
+	mu       sync.Mutex
+	inFlight int
+	stopped  bool
+	idle     chan struct{}
+}
+
+/**
+Registers the work in flight, returns false if the context is closing and the work must be rejected.
+Release function is safe to call more than once.
+*/
+func (t *Drainer) Acquire() (release func(), ok bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stopped {
+		return func() {}, false
+	}
+	if t.inFlight == 0 {
+		t.idle = make(chan struct{})
+	}
+	t.inFlight++
+	var once sync.Once
+	return func() {
+		once.Do(t.release)
+	}, true
+}
+
+func (t *Drainer) release() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.inFlight--
+	if t.inFlight == 0 {
+		close(t.idle)
+	}
+}
+
+/**
+Returns number of acquired and not released works
+*/
+func (t *Drainer) InFlight() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.inFlight
+}
+
+/**
+Rejects new work, called in the stop phase of close
+*/
+func (t *Drainer) Stop() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stopped = true
+	return nil
+}
+
+/**
+Waits for in-flight work, called in the drain phase of close
+*/
+func (t *Drainer) Drain(ctx stdcontext.Context) error {
+	t.mu.Lock()
+	t.stopped = true
+	if t.inFlight == 0 {
+		t.mu.Unlock()
+		return nil
+	}
+	idle := t.idle
+	t.mu.Unlock()
+
+	if t.Timeout > 0 {
+		var cancel stdcontext.CancelFunc
+		ctx, cancel = stdcontext.WithTimeout(ctx, t.Timeout)
+		defer cancel()
+	}
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return errors.Errorf("drainer has %d works in flight, %v", t.InFlight(), ctx.Err())
+	}
+}
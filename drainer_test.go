@@ -0,0 +1,66 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type drainedProcessor struct {
+	Drainer   *glue.Drainer `inject`
+	finished  int32
+	destroyed int32
+}
+
+func (t *drainedProcessor) process(d time.Duration) bool {
+	release, ok := t.Drainer.Acquire()
+	if !ok {
+		return false
+	}
+	go func() {
+		defer release()
+		time.Sleep(d)
+		atomic.StoreInt32(&t.finished, 1)
+	}()
+	return true
+}
+
+func (t *drainedProcessor) Destroy() error {
+	if atomic.LoadInt32(&t.finished) == 1 {
+		atomic.StoreInt32(&t.destroyed, 1)
+	}
+	return nil
+}
+
+func TestDrainer(t *testing.T) {
+
+	drainer := &glue.Drainer{}
+	processor := &drainedProcessor{}
+	ctx, err := glue.New(drainer, processor)
+	require.NoError(t, err)
+
+	require.True(t, processor.process(50*time.Millisecond))
+	require.Equal(t, 1, drainer.InFlight())
+
+	require.NoError(t, ctx.Close())
+	require.Equal(t, 0, drainer.InFlight())
+	require.Equal(t, int32(1), atomic.LoadInt32(&processor.destroyed))
+	require.False(t, processor.process(0))
+
+	drainer = &glue.Drainer{Timeout: 10 * time.Millisecond}
+	processor = &drainedProcessor{}
+	ctx, err = glue.New(drainer, processor)
+	require.NoError(t, err)
+
+	require.True(t, processor.process(time.Second))
+	err = ctx.Close()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "drainer has 1 works in flight")
+}
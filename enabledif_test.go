@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"reflect"
+	"testing"
+)
+
+var searchIndexClass = reflect.TypeOf((*searchIndex)(nil))
+
+type searchIndex struct {
+	Path string `value:"search.path,default=/tmp/index"`
+}
+
+type searchHandler struct {
+	Index *searchIndex `inject`
+}
+
+type searchHolder struct {
+	Index *searchIndex `inject:"optional"`
+}
+
+func TestEnabledIf(t *testing.T) {
+
+	holder := &searchHolder{}
+
+	ctx, err := glue.New(
+		&glue.PropertySource{Map: map[string]interface{}{
+			"feature.search.enabled": "true",
+		}},
+		glue.EnabledIf("feature.search.enabled",
+			&searchIndex{},
+			&searchHandler{},
+			&glue.PropertySource{Map: map[string]interface{}{
+				"search.path": "/var/index",
+			}},
+		),
+		holder,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.NotNil(t, holder.Index)
+	require.Equal(t, "/var/index", holder.Index.Path)
+	require.Equal(t, 1, len(ctx.Bean(searchIndexClass, glue.DefaultLevel)))
+}
+
+func TestEnabledIfDisabled(t *testing.T) {
+
+	for _, props := range []map[string]interface{}{
+		{"feature.search.enabled": "false"},
+		{},
+	} {
+		holder := &searchHolder{}
+
+		ctx, err := glue.New(
+			&glue.PropertySource{Map: props},
+			glue.EnabledIf("feature.search.enabled",
+				&searchIndex{},
+				&searchHandler{},
+			),
+			holder,
+		)
+		require.NoError(t, err)
+
+		require.Nil(t, holder.Index)
+		require.Equal(t, 0, len(ctx.Bean(searchIndexClass, glue.DefaultLevel)))
+		ctx.Close()
+	}
+
+	_, err := glue.New(
+		&glue.PropertySource{Map: map[string]interface{}{
+			"feature.search.enabled": "maybe",
+		}},
+		glue.EnabledIf("feature.search.enabled", &searchIndex{}),
+	)
+	require.Error(t, err)
+}
+
+func TestEnabledIfLazy(t *testing.T) {
+
+	ctx, err := glue.New(
+		&glue.PropertySource{Map: map[string]interface{}{
+			"feature.search.enabled": "true",
+		}},
+		glue.Lazy(
+			glue.EnabledIf("feature.search.enabled", &searchIndex{}),
+		),
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	list := ctx.Bean(searchIndexClass, glue.DefaultLevel)
+	require.Equal(t, 1, len(list))
+	require.Equal(t, glue.BeanInitialized, list[0].Lifecycle())
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"github.com/pkg/errors"
+	"path"
+	"strings"
+)
+
+/**
+Returns true for dotenv files like '.env', '.env.local' or 'prod.env'
+*/
+func isEnvFile(fileName string) bool {
+	base := path.Base(fileName)
+	return base == ".env" || strings.HasPrefix(base, ".env.") || strings.HasSuffix(base, ".env")
+}
+
+/**
+Parses content of the dotenv file: 'KEY=value' lines with optional 'export' prefix, comments starting with '#',
+single quoted literal values, double quoted values with escapes that could span multiple lines and inline comments after unquoted values.
+*/
+func parseEnvFile(content string) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		lineNum := i + 1
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "export ") {
+			line = strings.TrimSpace(line[len("export "):])
+		}
+		eq := strings.IndexByte(line, '=')
+		if eq <= 0 {
+			return nil, errors.Errorf("invalid line %d in env file, expected 'KEY=value'", lineNum)
+		}
+		key := strings.TrimSpace(line[:eq])
+		value := strings.TrimSpace(line[eq+1:])
+		switch {
+		case strings.HasPrefix(value, "'"):
+			end := strings.IndexByte(value[1:], '\'')
+			if end < 0 {
+				return nil, errors.Errorf("unterminated single quoted value of '%s' on line %d in env file", key, lineNum)
+			}
+			value = value[1 : end+1]
+		case strings.HasPrefix(value, "\""):
+			raw := value[1:]
+			for {
+				if v, ok := unquoteEnvValue(raw); ok {
+					value = v
+					break
+				}
+				i++
+				if i >= len(lines) {
+					return nil, errors.Errorf("unterminated double quoted value of '%s' on line %d in env file", key, lineNum)
+				}
+				raw += "\n" + lines[i]
+			}
+		default:
+			if j := strings.Index(value, " #"); j >= 0 {
+				value = strings.TrimSpace(value[:j])
+			}
+		}
+		result[key] = value
+	}
+	return result, nil
+}
+
+/**
+Returns content of the double quoted value up to the closing quote with processed escapes, false if there is no closing quote
+*/
+func unquoteEnvValue(raw string) (string, bool) {
+	var out strings.Builder
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		switch {
+		case c == '"':
+			return out.String(), true
+		case c == '\\' && i+1 < len(raw):
+			i++
+			switch raw[i] {
+			case 'n':
+				out.WriteByte('\n')
+			case 'r':
+				out.WriteByte('\r')
+			case 't':
+				out.WriteByte('\t')
+			default:
+				out.WriteByte(raw[i])
+			}
+		default:
+			out.WriteByte(c)
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"reflect"
+)
+
+func (t *context) Factories(objType reflect.Type) []FactoryBean {
+	return t.factories(objType, false)
+}
+
+/**
+Collects factory beans of the context and its parents, or of the context only if local
+*/
+func (t *context) factories(objType reflect.Type, local bool) []FactoryBean {
+	var list []FactoryBean
+	for ctx := t; ctx != nil; ctx = ctx.parent {
+		ctx.eachBean(func(b Bean) bool {
+			impl, ok := b.(*bean)
+			if !ok || impl.beenFactory != nil {
+				return true
+			}
+			if f, ok := impl.obj.(FactoryBean); ok && producesType(f.ObjectType(), objType) {
+				if len(t.lookupBeans(objType, []*bean{impl})) == 0 {
+					return true
+				}
+				if impl.lazyInit && impl.Lifecycle() != BeanInitialized {
+					if err := ctx.constructOnDemand(impl); err != nil {
+						if ctx.logger() != nil {
+							ctx.logger().Printf("Lazy factory bean construction error, %v\n", err)
+						}
+						return true
+					}
+				}
+				list = append(list, f)
+			}
+			return true
+		})
+		if local {
+			break
+		}
+	}
+	return list
+}
+
+/**
+Checks if objects of the produced type are injectable in to the field of the type
+*/
+func producesType(produced, typ reflect.Type) bool {
+	if produced == typ {
+		return true
+	}
+	return typ.Kind() == reflect.Interface && produced.Implements(typ)
+}
+
+/**
+Checks if FactoryBean could produce objects of the type, slices and maps must be named to be injected by exact type
+*/
+func producibleType(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Func:
+		return true
+	case reflect.Slice, reflect.Map:
+		return typ.Name() != ""
+	}
+	return false
+}
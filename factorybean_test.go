@@ -6,11 +6,15 @@
 package glue_test
 
 import (
+	"bytes"
+	"errors"
 	"github.com/codeallergy/glue"
 	"github.com/stretchr/testify/require"
+	"log"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 type someService struct {
@@ -234,3 +238,445 @@ func TestFactoryInterfaceBean(t *testing.T) {
 	err = bc[0].Object().(BeanConstructed).Run()
 	require.NoError(t, err)
 }
+
+type eachElement struct {
+}
+
+var eachElementClass = reflect.TypeOf((*eachElement)(nil))
+
+type eachElementFactory struct {
+}
+
+func (t *eachElementFactory) Object() (interface{}, error) {
+	return &eachElement{}, nil
+}
+
+func (t *eachElementFactory) ObjectType() reflect.Type {
+	return eachElementClass
+}
+
+func (t *eachElementFactory) ObjectName() string {
+	return ""
+}
+
+func (t *eachElementFactory) Singleton() bool {
+	return false
+}
+
+type eachElementHolder struct {
+	Element *eachElement `inject`
+}
+
+func TestEachBean(t *testing.T) {
+
+	ctx, err := glue.New(
+		&eachElementFactory{},
+		&eachElementHolder{},
+		&eachElementHolder{},
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	count := func(c glue.Context, level int, classPtr reflect.Type) int {
+		n := 0
+		c.EachBean(level, func(b glue.Bean) bool {
+			if b.Class() == classPtr {
+				n++
+			}
+			return true
+		})
+		return n
+	}
+
+	require.Equal(t, 3, count(ctx, glue.DefaultLevel, eachElementClass))
+	require.Equal(t, 1, count(ctx, glue.DefaultLevel, reflect.TypeOf((*eachElementFactory)(nil))))
+
+	visited := 0
+	ctx.EachBean(glue.DefaultLevel, func(b glue.Bean) bool {
+		visited++
+		return false
+	})
+	require.Equal(t, 1, visited)
+
+	child, err := ctx.Extend(&eachElementHolder{})
+	require.NoError(t, err)
+	defer child.Close()
+
+	holderClass := reflect.TypeOf((*eachElementHolder)(nil))
+	require.Equal(t, 1, count(child, glue.DefaultLevel, holderClass))
+	require.Equal(t, 3, count(child, glue.ParentsLevel, holderClass))
+	require.Equal(t, 3, count(child, glue.AllLevel, holderClass))
+	require.Equal(t, 4, count(child, glue.AllLevel, eachElementClass))
+}
+
+type retryElement struct {
+}
+
+var retryElementClass = reflect.TypeOf((*retryElement)(nil))
+
+type retryFactory struct {
+	calls  int
+	failAt func(call int) bool
+	policy glue.FactoryRetryPolicy
+}
+
+func (t *retryFactory) Object() (interface{}, error) {
+	t.calls++
+	if t.failAt(t.calls) {
+		return nil, errors.New("backend is down")
+	}
+	return &retryElement{}, nil
+}
+
+func (t *retryFactory) ObjectType() reflect.Type {
+	return retryElementClass
+}
+
+func (t *retryFactory) ObjectName() string {
+	return ""
+}
+
+func (t *retryFactory) Singleton() bool {
+	return false
+}
+
+func (t *retryFactory) RetryPolicy() glue.FactoryRetryPolicy {
+	return t.policy
+}
+
+type retryHolder struct {
+	Element *retryElement `inject`
+}
+
+func TestFactoryRetry(t *testing.T) {
+
+	factory := &retryFactory{
+		failAt: func(call int) bool {
+			return call == 2 || call == 3
+		},
+		policy: glue.FactoryRetryPolicy{Attempts: 3, Backoff: time.Millisecond},
+	}
+
+	ctx, err := glue.New(factory)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	holder := &retryHolder{}
+	require.NoError(t, ctx.Inject(holder))
+	require.NotNil(t, holder.Element)
+	require.Equal(t, 4, factory.calls)
+}
+
+func TestFactoryCircuitBreaker(t *testing.T) {
+
+	factory := &retryFactory{
+		failAt: func(call int) bool {
+			return call > 1
+		},
+		policy: glue.FactoryRetryPolicy{Attempts: 1, FailureThreshold: 2, OpenTimeout: time.Hour},
+	}
+
+	ctx, err := glue.New(factory)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	for i := 0; i < 2; i++ {
+		err = ctx.Inject(&retryHolder{})
+		require.Error(t, err)
+		require.False(t, errors.Is(err, glue.ErrFactoryCircuitOpen))
+	}
+
+	err = ctx.Inject(&retryHolder{})
+	require.True(t, errors.Is(err, glue.ErrFactoryCircuitOpen))
+	require.Equal(t, 3, factory.calls)
+}
+
+type pooledEncoder struct {
+	destroyed *int
+}
+
+func (t *pooledEncoder) Destroy() error {
+	*t.destroyed++
+	return nil
+}
+
+var pooledEncoderClass = reflect.TypeOf((*pooledEncoder)(nil))
+
+type encoderFactory struct {
+	created   int
+	destroyed int
+}
+
+func (t *encoderFactory) Object() (interface{}, error) {
+	t.created++
+	return &pooledEncoder{destroyed: &t.destroyed}, nil
+}
+
+func (t *encoderFactory) ObjectType() reflect.Type {
+	return pooledEncoderClass
+}
+
+func (t *encoderFactory) ObjectName() string {
+	return ""
+}
+
+func (t *encoderFactory) Singleton() bool {
+	return false
+}
+
+func (t *encoderFactory) Pooled() (int, int) {
+	return 1, 2
+}
+
+type encoderService struct {
+	Encoders *glue.Pool[*pooledEncoder] `inject`
+}
+
+func TestPoolingFactoryBean(t *testing.T) {
+
+	factory := &encoderFactory{}
+	service := &encoderService{}
+
+	ctx, err := glue.New(factory, service)
+	require.NoError(t, err)
+	require.Equal(t, 1, factory.created)
+
+	first, err := service.Encoders.Get()
+	require.NoError(t, err)
+	second, err := service.Encoders.Get()
+	require.NoError(t, err)
+	require.NotSame(t, first, second)
+	require.Equal(t, 2, factory.created)
+
+	service.Encoders.Put(first)
+	third, err := service.Encoders.Get()
+	require.NoError(t, err)
+	require.Same(t, first, third)
+	require.Equal(t, 2, factory.created)
+
+	// double and foreign put are ignored
+	var buf bytes.Buffer
+	prev := glue.Warnings(log.New(&buf, "", 0))
+	defer glue.Warnings(prev)
+	service.Encoders.Put(second)
+	service.Encoders.Put(second)
+	service.Encoders.Put(&pooledEncoder{destroyed: &factory.destroyed})
+	fourth, err := service.Encoders.Get()
+	require.NoError(t, err)
+	require.Same(t, second, fourth)
+	service.Encoders.Put(third)
+	fifth, err := service.Encoders.Get()
+	require.NoError(t, err)
+	require.Same(t, third, fifth)
+	require.Equal(t, 2, factory.created)
+	require.Equal(t, 2, strings.Count(buf.String(), "not checked out"))
+
+	require.NoError(t, ctx.Close())
+	require.Equal(t, 2, factory.destroyed)
+
+	_, err = service.Encoders.Get()
+	require.Error(t, err)
+}
+
+func TestFactories(t *testing.T) {
+
+	parent, err := glue.New(
+		&someService{testing: t},
+		&factoryBeanExample{testing: t},
+	)
+	require.NoError(t, err)
+	defer parent.Close()
+
+	child, err := parent.Extend(glue.Lazy(&repeatedFactoryBeanExample{testing: t}))
+	require.NoError(t, err)
+	defer child.Close()
+
+	list := child.Factories(beanConstructedClass)
+	require.Equal(t, 2, len(list))
+	_, ok := list[0].(*repeatedFactoryBeanExample)
+	require.True(t, ok)
+	_, ok = list[1].(*factoryBeanExample)
+	require.True(t, ok)
+
+	obj, err := list[1].Object()
+	require.NoError(t, err)
+	require.NotSame(t, parent.Bean(beanConstructedClass, glue.DefaultLevel)[0].Object(), obj)
+
+	require.Equal(t, 1, len(parent.Factories(beanConstructedClass)))
+	require.Empty(t, parent.Factories(reflect.TypeOf((*someService)(nil))))
+}
+
+type Routes []string
+
+type Headers map[string]string
+
+type Greeter func(name string) string
+
+type valueFactory struct {
+	typ reflect.Type
+	obj interface{}
+}
+
+func (t *valueFactory) Object() (interface{}, error) {
+	return t.obj, nil
+}
+
+func (t *valueFactory) ObjectType() reflect.Type {
+	return t.typ
+}
+
+func (t *valueFactory) ObjectName() string {
+	return ""
+}
+
+func (t *valueFactory) Singleton() bool {
+	return true
+}
+
+func TestFactoryValueTypes(t *testing.T) {
+
+	holder := &struct {
+		Routes  Routes  `inject:"exact"`
+		Headers Headers `inject:"exact"`
+		Greeter Greeter `inject`
+	}{}
+
+	ctx, err := glue.New(
+		&valueFactory{typ: reflect.TypeOf(Routes{}), obj: Routes{"/users", "/orders"}},
+		&valueFactory{typ: reflect.TypeOf(Headers{}), obj: Headers{"X-Service": "orders"}},
+		&valueFactory{typ: reflect.TypeOf(Greeter(nil)), obj: Greeter(func(name string) string { return "hello " + name })},
+		holder,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.Equal(t, Routes{"/users", "/orders"}, holder.Routes)
+	require.Equal(t, "orders", holder.Headers["X-Service"])
+	require.Equal(t, "hello glue", holder.Greeter("glue"))
+
+	_, err = glue.New(&valueFactory{typ: reflect.TypeOf([]string{}), obj: []string{}})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "named slice and map")
+
+	_, err = glue.New(&struct {
+		Routes []string `inject:"exact"`
+	}{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "'exact' attribute")
+}
+
+type nsPlugin interface {
+	PluginName() string
+}
+
+type nsPlugins []nsPlugin
+
+type nsPluginTable map[string]nsPlugin
+
+type nsPluginImpl struct {
+}
+
+func (t *nsPluginImpl) PluginName() string {
+	return "plugin"
+}
+
+func TestNamedCollectionInjection(t *testing.T) {
+
+	holder := &struct {
+		Plugins nsPlugins     `inject`
+		Table   nsPluginTable `inject`
+	}{}
+
+	ctx, err := glue.New(
+		&nsPluginImpl{},
+		holder,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.Equal(t, 1, len(holder.Plugins))
+	require.Equal(t, "plugin", holder.Plugins[0].PluginName())
+	require.Equal(t, 1, len(holder.Table))
+}
+
+type constructedConsumer struct {
+	Constructed *beanConstructed `inject`
+}
+
+type constructedListConsumer struct {
+	Constructed []*beanConstructed `inject`
+}
+
+func TestFactoryConsumers(t *testing.T) {
+
+	parent, err := glue.New(
+		&someService{testing: t},
+		&factoryBeanExample{testing: t},
+		&constructedConsumer{},
+	)
+	require.NoError(t, err)
+	defer parent.Close()
+
+	product := parent.Bean(beanConstructedClass, glue.DefaultLevel)
+	require.Equal(t, 1, len(product))
+	factory, ok := product[0].FactoryBean()
+	require.True(t, ok)
+
+	consumers := factory.Consumers()
+	require.Equal(t, 1, len(consumers))
+	require.Equal(t, reflect.TypeOf(&constructedConsumer{}), consumers[0].Class())
+
+	service := parent.Bean(reflect.TypeOf(&someService{}), glue.DefaultLevel)
+	require.Equal(t, 1, len(service))
+	require.Equal(t, 1, len(service[0].Consumers()))
+	require.Equal(t, reflect.TypeOf(&factoryBeanExample{}), service[0].Consumers()[0].Class())
+
+	child, err := parent.Extend(&constructedListConsumer{})
+	require.NoError(t, err)
+
+	require.Equal(t, 2, len(factory.Consumers()))
+
+	require.NoError(t, child.Close())
+	require.Equal(t, 1, len(factory.Consumers()))
+}
+
+type gatedEncoderFactory struct {
+	encoderFactory
+	entered chan struct{}
+	gate    chan struct{}
+}
+
+func (t *gatedEncoderFactory) Object() (interface{}, error) {
+	t.entered <- struct{}{}
+	<-t.gate
+	return t.encoderFactory.Object()
+}
+
+func (t *gatedEncoderFactory) Pooled() (int, int) {
+	return 0, 0
+}
+
+func TestPoolCreateAfterClose(t *testing.T) {
+
+	factory := &gatedEncoderFactory{entered: make(chan struct{}), gate: make(chan struct{})}
+	service := &encoderService{}
+
+	ctx, err := glue.New(factory, service)
+	require.NoError(t, err)
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := service.Encoders.Get()
+		errs <- err
+	}()
+
+	<-factory.entered
+	require.NoError(t, ctx.Close())
+	close(factory.gate)
+
+	err = <-errs
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is closed")
+	require.Equal(t, 1, factory.created)
+	require.Equal(t, 1, factory.destroyed)
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+/**
+Parts of bean names replaced by StringFormat when Redact is nil, case-insensitive
+*/
+var DefaultRedactedNames = []string{"password", "secret", "token", "credential"}
+
+/**
+Option of the context that adds details to Context.String for concise inclusion in logs and error messages.
+Bean names containing one of Redact parts (case-insensitive) are replaced by '<redacted>', nil Redact means DefaultRedactedNames.
+The option is inherited by contexts created by Extend. String of beans is not affected, beans do not refer to the context.
+
+Example:
+	ctx, err := glue.New(
+		glue.StringFormat{TopBeans: 5, Children: true, Lifecycle: true},
+		&service{},
+	)
+
+	log.Printf("started %v", ctx)
+	// started Context [hasParent=false, types=4, destructors=1, beans=[*app.service, ...], lifecycle={BeanInitialized=3}]
+*/
+type StringFormat struct {
+
+	/**
+	Number of bean names sorted by name to include, zero omits them
+	*/
+	TopBeans int
+
+	/**
+	Include roles of child contexts
+	*/
+	Children bool
+
+	/**
+	Include the number of beans per lifecycle
+	*/
+	Lifecycle bool
+
+	/**
+	Parts of bean names to redact
+	*/
+	Redact []string
+}
+
+func (t StringFormat) applyOption(ctx *context) {
+	ctx.stringFormat = &t
+}
+
+func (t *StringFormat) format(ctx *context) string {
+	var out strings.Builder
+	fmt.Fprintf(&out, "Context [hasParent=%v, types=%d, destructors=%d", ctx.parent != nil, len(ctx.coreBeans()), ctx.destructors())
+	if ctx.role != "" {
+		fmt.Fprintf(&out, ", role=%s", ctx.role)
+	}
+
+	var names []string
+	lifecycles := make(map[BeanLifecycle]int)
+	ctx.eachBean(func(b Bean) bool {
+		if b.Object() == interface{}(ctx) {
+			return true
+		}
+		names = append(names, t.redact(b.Name()))
+		lifecycles[b.Lifecycle()]++
+		return true
+	})
+
+	if t.TopBeans > 0 {
+		sort.Strings(names)
+		if len(names) > t.TopBeans {
+			fmt.Fprintf(&out, ", beans=[%s, +%d more]", strings.Join(names[:t.TopBeans], ", "), len(names)-t.TopBeans)
+		} else {
+			fmt.Fprintf(&out, ", beans=[%s]", strings.Join(names, ", "))
+		}
+	}
+
+	if t.Children {
+		// children are added on scan only, before the context is returned
+		roles := make([]string, len(ctx.children))
+		for i, child := range ctx.children {
+			roles[i] = child.Role()
+		}
+		fmt.Fprintf(&out, ", children=[%s]", strings.Join(roles, ", "))
+	}
+
+	if t.Lifecycle {
+		var counts []string
+		for l := BeanAllocated; l <= BeanDestroyed; l++ {
+			if n := lifecycles[l]; n > 0 {
+				counts = append(counts, fmt.Sprintf("%v=%d", l, n))
+			}
+		}
+		fmt.Fprintf(&out, ", lifecycle={%s}", strings.Join(counts, ", "))
+	}
+
+	out.WriteString("]")
+	return out.String()
+}
+
+func (t *StringFormat) redact(name string) string {
+	parts := t.Redact
+	if parts == nil {
+		parts = DefaultRedactedNames
+	}
+	lower := strings.ToLower(name)
+	for _, part := range parts {
+		if part != "" && strings.Contains(lower, strings.ToLower(part)) {
+			return "<redacted>"
+		}
+	}
+	return name
+}
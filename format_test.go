@@ -0,0 +1,56 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+type apiSecretHolder struct {
+}
+
+type formatReport struct {
+}
+
+func TestStringFormat(t *testing.T) {
+
+	ctx, err := glue.New(
+		glue.StringFormat{TopBeans: 3, Children: true, Lifecycle: true},
+		&apiSecretHolder{},
+		glue.Lazy(&formatReport{}),
+		glue.Child("admin", &formatReport{}),
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	s := ctx.String()
+	require.True(t, strings.HasPrefix(s, "Context [hasParent=false, "), s)
+	require.Contains(t, s, ", beans=[")
+	require.NotContains(t, s, "more]")
+	require.Contains(t, s, "<redacted>")
+	require.NotContains(t, s, "apiSecretHolder")
+	require.Contains(t, s, ", children=[admin]")
+	require.Contains(t, s, "BeanCreated=1")
+	require.Contains(t, s, "BeanInitialized=")
+
+	child, err := ctx.Extend(glue.StringFormat{TopBeans: 10, Redact: []string{}}, &apiSecretHolder{})
+	require.NoError(t, err)
+	defer child.Close()
+	require.Contains(t, child.String(), "*glue_test.apiSecretHolder")
+
+	short, err := glue.New(glue.StringFormat{TopBeans: 1}, &formatReport{}, &apiSecretHolder{})
+	require.NoError(t, err)
+	defer short.Close()
+	require.Contains(t, short.String(), "more]")
+
+	plain, err := glue.New()
+	require.NoError(t, err)
+	defer plain.Close()
+	require.True(t, strings.HasPrefix(plain.String(), "Context [hasParent=false, types="))
+}
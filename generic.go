@@ -0,0 +1,85 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+/**
+Returns reflect type of T including interfaces and instantiations of generic types, used for lookups like ctx.Bean(glue.TypeOf[Repository[User]](), glue.DefaultLevel).
+
+Example:
+	var RepositoryClass = glue.TypeOf[Repository[User]]()
+*/
+func TypeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+/**
+Returns objects of beans assignable to T with the default lookup level.
+T could be an interface, an instantiation of the generic interface or a pointer to struct.
+
+Example:
+	for _, repo := range glue.ListOf[Repository[User]](ctx) {
+		repo.Find(id)
+	}
+*/
+func ListOf[T any](ctx Context) []T {
+	var list []T
+	for _, b := range ctx.Bean(TypeOf[T](), DefaultLevel) {
+		if obj, ok := b.Object().(T); ok {
+			list = append(list, obj)
+		}
+	}
+	return list
+}
+
+/**
+Returns the hint for the error of missing candidates with beans of other instantiations of the same generic type in the context hierarchy,
+since instantiations with different type arguments are different types, e.g. '*app.cache[string]' is not '*app.cache[int]'
+*/
+func (t *context) genericHint(typ reflect.Type) string {
+	base, ok := genericBase(typ)
+	if !ok {
+		return ""
+	}
+	seen := make(map[reflect.Type]bool)
+	var found []string
+	for ctx := t; ctx != nil; ctx = ctx.parent {
+		for classPtr := range ctx.coreBeans() {
+			if other, ok := genericBase(classPtr); ok && other == base && classPtr != typ && !seen[classPtr] {
+				seen[classPtr] = true
+				found = append(found, classPtr.String())
+			}
+		}
+	}
+	if len(found) == 0 {
+		return ""
+	}
+	sort.Strings(found)
+	return fmt.Sprintf(", generic type '%s' has beans only with other type arguments %v", base, found)
+}
+
+/**
+Returns the name of the generic type without type arguments, '*app.cache[string]' becomes '*app.cache'
+*/
+func genericBase(typ reflect.Type) (string, bool) {
+	prefix := ""
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+		prefix = "*"
+	}
+	name := typ.String()
+	i := strings.IndexByte(name, '[')
+	if i <= 0 || typ.Name() == "" {
+		return "", false
+	}
+	return prefix + name[:i], true
+}
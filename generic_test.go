@@ -0,0 +1,138 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type genericUser struct {
+	Name string
+}
+
+type genericOrder struct {
+	ID int
+}
+
+type genericRepository[T any] interface {
+	Find(id int) T
+}
+
+type genericUserRepo struct {
+}
+
+func (t *genericUserRepo) Find(id int) genericUser {
+	return genericUser{Name: "user"}
+}
+
+type genericAdminRepo struct {
+}
+
+func (t *genericAdminRepo) Find(id int) genericUser {
+	return genericUser{Name: "admin"}
+}
+
+type genericOrderRepo struct {
+}
+
+func (t *genericOrderRepo) Find(id int) genericOrder {
+	return genericOrder{ID: id}
+}
+
+type genericRepoHolder struct {
+	Users  []genericRepository[genericUser]          `inject`
+	ByName map[string]genericRepository[genericUser] `inject`
+	Orders genericRepository[genericOrder]           `inject`
+}
+
+func TestListOf(t *testing.T) {
+
+	holder := &genericRepoHolder{}
+	ctx, err := glue.New(
+		glue.Naming(glue.ShortNaming),
+		&genericUserRepo{},
+		&genericAdminRepo{},
+		&genericOrderRepo{},
+		holder,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.Equal(t, 2, len(holder.Users))
+	require.Equal(t, 2, len(holder.ByName))
+	require.NotNil(t, holder.ByName["genericUserRepo"])
+	require.Equal(t, 7, holder.Orders.Find(7).ID)
+
+	users := glue.ListOf[genericRepository[genericUser]](ctx)
+	require.Equal(t, 2, len(users))
+
+	orders := glue.ListOf[genericRepository[genericOrder]](ctx)
+	require.Equal(t, 1, len(orders))
+	require.Equal(t, 3, orders[0].Find(3).ID)
+
+	require.Equal(t, 1, len(glue.ListOf[*genericOrderRepo](ctx)))
+	require.Equal(t, 0, len(glue.ListOf[genericRepository[string]](ctx)))
+
+	require.Equal(t, "genericRepository[genericUser]", glue.ShortNaming(glue.TypeOf[genericRepository[genericUser]]()))
+	require.Equal(t, "genericRepository[map[string]int]", glue.ShortNaming(glue.TypeOf[genericRepository[map[string]int]]()))
+}
+
+type genericCache[K comparable] struct {
+	entries map[K]string
+}
+
+type genericService[T any] struct {
+	Repo  genericRepository[T]  `inject`
+	Cache *genericCache[string] `inject`
+}
+
+type genericCacheHolder struct {
+	Cache    *genericCache[string]        `inject`
+	Caches   []*genericCache[string]      `inject`
+	Users    *genericService[genericUser] `inject`
+	Optional *genericCache[float64]       `inject:"optional"`
+}
+
+func TestGenericFields(t *testing.T) {
+
+	cache := &genericCache[string]{}
+	holder := &genericCacheHolder{}
+	ctx, err := glue.New(
+		cache,
+		&genericCache[int]{},
+		&genericUserRepo{},
+		&genericService[genericUser]{},
+		holder,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.True(t, holder.Cache == cache)
+	require.Equal(t, 1, len(holder.Caches))
+	require.True(t, holder.Users.Cache == cache)
+	require.Equal(t, "user", holder.Users.Repo.Find(1).Name)
+	require.Nil(t, holder.Optional)
+
+	runtime := &struct {
+		Cache *genericCache[string] `inject`
+	}{}
+	require.NoError(t, ctx.Inject(runtime))
+	require.True(t, runtime.Cache == cache)
+
+	_, err = glue.New(&genericCache[int]{}, &struct {
+		Cache *genericCache[string] `inject`
+	}{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "generic type '*glue_test.genericCache' has beans only with other type arguments [*glue_test.genericCache[int]]")
+
+	err = ctx.Inject(&struct {
+		Cache *genericCache[bool] `inject`
+	}{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "[*glue_test.genericCache[int] *glue_test.genericCache[string]]")
+}
@@ -0,0 +1,190 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"github.com/pkg/errors"
+	"sync"
+)
+
+var packageBeans struct {
+	sync.RWMutex
+	groups map[string][]interface{}
+}
+
+/**
+Registers beans of the package in the global group, usually called from init() function of the package.
+Applications include the group in the scan list by glue.Group(name).
+
+Instances are included as is, so the same instance is shared by every context including the group.
+Register the constructor func() interface{} instead of the instance when the group is included in more than one context,
+the constructor is called on each inclusion, so every context gets own instance of the bean.
+
+Example:
+	func init() {
+		glue.RegisterPackageBeans("storage",
+			&storageConfig{},
+			func() interface{} { return &boltStorage{} },
+		)
+	}
+*/
+func RegisterPackageBeans(group string, beans ...interface{}) {
+	packageBeans.Lock()
+	defer packageBeans.Unlock()
+	if packageBeans.groups == nil {
+		packageBeans.groups = make(map[string][]interface{})
+	}
+	packageBeans.groups[group] = append(packageBeans.groups[group], beans...)
+}
+
+/**
+Group of beans registered by packages, resolved on scan
+*/
+type packageGroup struct {
+	name string
+}
+
+/**
+Includes beans registered by glue.RegisterPackageBeans under the group name.
+Returns error on creation of the context if the group was not registered, usually it means that the package was not imported.
+
+Example:
+	import _ "example.com/app/storage"
+
+	glue.New(
+		glue.Group("storage"),
+		&service{},
+	)
+*/
+func Group(name string) interface{} {
+	return &packageGroup{name: name}
+}
+
+func (t *packageGroup) Beans() ([]interface{}, error) {
+	packageBeans.RLock()
+	defer packageBeans.RUnlock()
+	beans, ok := packageBeans.groups[t.name]
+	if !ok {
+		return nil, errors.Errorf("bean group '%s' is not registered, check that the package is imported", t.name)
+	}
+	list := make([]interface{}, len(beans))
+	for i, item := range beans {
+		switch obj := item.(type) {
+		case nil:
+			return nil, errors.Errorf("bean group '%s' has nil bean at position %d", t.name, i)
+		case func() interface{}:
+			if obj == nil {
+				return nil, errors.Errorf("bean group '%s' has nil constructor at position %d", t.name, i)
+			}
+			list[i] = obj()
+		default:
+			list[i] = obj
+		}
+	}
+	return list, nil
+}
+
+/**
+Group of beans declared inline in the scan list
+*/
+type inlineGroup struct {
+	name  string
+	beans []interface{}
+}
+
+/**
+Includes beans in the group under the name without global registration, so glue.GroupOrder applies to them the same way
+as to beans included by glue.Group. Beans that already belong to a group keep it.
+
+Example:
+	glue.New(
+		glue.GroupOrder("infrastructure", "services"),
+		glue.InGroup("services", &service{}),
+		glue.InGroup("infrastructure", &storage{}, &metrics{}),
+	)
+*/
+func InGroup(name string, beans ...interface{}) interface{} {
+	return &inlineGroup{name: name, beans: beans}
+}
+
+/**
+Marks the bean included by glue.Group or glue.InGroup with the group name, markers of the scan list are returned as is
+*/
+func inGroup(name string, item interface{}) interface{} {
+	switch item.(type) {
+	case contextOption, groupOrder, *adaptation, *provider, propertyAliases, *namedValue:
+		return item
+	}
+	r := registrationOf(item)
+	if r.group == "" {
+		r.group = name
+	}
+	return r
+}
+
+/**
+Order of construction of groups of beans registered by glue.GroupOrder
+*/
+type groupOrder []string
+
+/**
+Declares that beans of each group included by glue.Group or glue.InGroup are constructed fully before beans of the next group,
+giving coarse phase control where fine-grained dependencies are impractical to express.
+Lazy beans of the group are not constructed because of that, groups without beans in the context are skipped.
+
+Example:
+	glue.New(
+		glue.GroupOrder("infrastructure", "services"),
+		glue.Group("services"),
+		glue.Group("infrastructure"),
+	)
+*/
+func GroupOrder(groups ...string) interface{} {
+	return groupOrder(append([]string(nil), groups...))
+}
+
+/**
+Adds construction dependencies of beans of every group on beans of groups going before it
+*/
+func (t *context) addGroupDependencies(orders []groupOrder) error {
+	if len(orders) == 0 {
+		return nil
+	}
+	members := make(map[string][]*bean)
+	for _, list := range t.coreBeans() {
+		for _, b := range list {
+			if b.group != "" {
+				members[b.group] = append(members[b.group], b)
+			}
+		}
+	}
+	for _, order := range orders {
+		position := make(map[string]int, len(order))
+		for i, group := range order {
+			position[group] = i
+		}
+		for i, group := range order {
+			for _, b := range members[group] {
+				for _, dep := range b.dependencies {
+					if j, ok := position[dep.group]; ok && j > i {
+						return errors.Errorf("bean '%s' of group '%s' depends on bean '%s' of group '%s' constructed after it", b.name, group, dep.name, dep.group)
+					}
+				}
+			}
+			for _, before := range order[:i] {
+				for _, dep := range members[before] {
+					if dep.lazyInit {
+						continue
+					}
+					for _, b := range members[group] {
+						b.dependencies = append(b.dependencies, dep)
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
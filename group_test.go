@@ -0,0 +1,165 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type groupStorage struct {
+}
+
+type groupMetrics struct {
+	Storage *groupStorage `inject`
+}
+
+type groupConfig struct {
+}
+
+var sharedGroupConfig = &groupConfig{}
+
+func init() {
+	glue.RegisterPackageBeans("test.storage", func() interface{} { return &groupStorage{} })
+	glue.RegisterPackageBeans("test.storage", func() interface{} { return &groupMetrics{} })
+	glue.RegisterPackageBeans("test.config", sharedGroupConfig)
+}
+
+func TestPackageGroup(t *testing.T) {
+
+	holder := &struct {
+		Metrics *groupMetrics `inject`
+	}{}
+
+	ctx, err := glue.New(
+		glue.Group("test.storage"),
+		holder,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.NotNil(t, holder.Metrics)
+	require.NotNil(t, holder.Metrics.Storage)
+
+	other := &struct {
+		Metrics *groupMetrics `inject`
+	}{}
+	second, err := glue.New(
+		glue.Group("test.storage"),
+		other,
+	)
+	require.NoError(t, err)
+	defer second.Close()
+	require.True(t, holder.Metrics != other.Metrics)
+
+	config := &struct {
+		Config *groupConfig `inject`
+	}{}
+	third, err := glue.New(
+		glue.Group("test.config"),
+		config,
+	)
+	require.NoError(t, err)
+	defer third.Close()
+	require.True(t, config.Config == sharedGroupConfig)
+
+	_, err = glue.New(
+		glue.Group("test.unknown"),
+	)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "test.unknown")
+}
+
+var groupEvents []string
+
+type groupDatabase struct {
+}
+
+func (t *groupDatabase) PostConstruct() error {
+	groupEvents = append(groupEvents, "database")
+	return nil
+}
+
+type groupUsers struct {
+}
+
+func (t *groupUsers) PostConstruct() error {
+	groupEvents = append(groupEvents, "users")
+	return nil
+}
+
+type groupCache struct {
+	Users *groupUsers `inject`
+}
+
+func init() {
+	glue.RegisterPackageBeans("test.infrastructure", func() interface{} { return &groupDatabase{} })
+	glue.RegisterPackageBeans("test.services", func() interface{} { return &groupUsers{} })
+	glue.RegisterPackageBeans("test.broken", func() interface{} { return &groupCache{} })
+}
+
+func TestGroupOrder(t *testing.T) {
+
+	groupEvents = nil
+	ctx, err := glue.New(
+		glue.GroupOrder("test.infrastructure", "test.services"),
+		glue.Group("test.services"),
+		glue.Group("test.infrastructure"),
+	)
+	require.NoError(t, err)
+	ctx.Close()
+	require.Equal(t, []string{"database", "users"}, groupEvents)
+
+	groupEvents = nil
+	ctx, err = glue.New(
+		glue.GroupOrder("test.services", "test.infrastructure"),
+		glue.Group("test.infrastructure"),
+		glue.Group("test.services"),
+	)
+	require.NoError(t, err)
+	ctx.Close()
+	require.Equal(t, []string{"users", "database"}, groupEvents)
+
+	_, err = glue.New(
+		glue.GroupOrder("test.broken", "test.services"),
+		glue.Group("test.broken"),
+		glue.Group("test.services"),
+	)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "of group 'test.broken' depends on bean '*glue_test.groupUsers' of group 'test.services' constructed after it")
+}
+
+func TestInlineGroupOrder(t *testing.T) {
+
+	groupEvents = nil
+	ctx, err := glue.New(
+		glue.GroupOrder("inline.infrastructure", "inline.services"),
+		glue.InGroup("inline.services", &groupUsers{}),
+		glue.InGroup("inline.infrastructure", &groupDatabase{}),
+	)
+	require.NoError(t, err)
+	ctx.Close()
+	require.Equal(t, []string{"database", "users"}, groupEvents)
+
+	groupEvents = nil
+	ctx, err = glue.New(
+		glue.GroupOrder("inline.services", "test.infrastructure"),
+		glue.Group("test.infrastructure"),
+		glue.InGroup("inline.services", []interface{}{&groupUsers{}}),
+	)
+	require.NoError(t, err)
+	ctx.Close()
+	require.Equal(t, []string{"users", "database"}, groupEvents)
+
+	_, err = glue.New(
+		glue.GroupOrder("inline.broken", "inline.services"),
+		glue.InGroup("inline.broken", &groupCache{}),
+		glue.InGroup("inline.services", &groupUsers{}),
+	)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "of group 'inline.broken' depends on bean '*glue_test.groupUsers' of group 'inline.services' constructed after it")
+}
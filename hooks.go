@@ -0,0 +1,97 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"github.com/pkg/errors"
+	"reflect"
+)
+
+/**
+Option of the context with hooks around retrieval of beans, inherited by contexts created by Extend.
+Hooks enable security policies denying certain beans to certain callers and metrics without modifying retrieval code.
+
+OnLookup is called with the found beans and returns beans given to the caller by Bean, Lookup, LookupPattern and glue.ListOf,
+typ is nil for lookups by name. EachBean calls it for every visited bean with the type of the bean, Factories with each found factory bean
+and the requested type, WeakRef.Get and Pool.Get with the candidate of the handle on every resolution.
+
+OnInject is called before injection of beans in to the field of the target pointer class on creation of the context and by Inject,
+returned error fails the injection.
+
+Example:
+	glue.New(
+		glue.Hooks{
+			OnInject: func(target reflect.Type, field string, chosen []glue.Bean) error {
+				if target.Elem().PkgPath() == "plugins" && chosen[0].Class() == secretsClass {
+					return errors.New("plugins can not use secrets")
+				}
+				return nil
+			},
+		},
+		&app{},
+	)
+*/
+type Hooks struct {
+	OnLookup func(typ reflect.Type, result []Bean) []Bean
+	OnInject func(target reflect.Type, field string, chosen []Bean) error
+}
+
+func (t Hooks) applyOption(ctx *context) {
+	ctx.hooks = &t
+}
+
+func (t *Hooks) lookup(typ reflect.Type, result []Bean) []Bean {
+	if t == nil || t.OnLookup == nil {
+		return result
+	}
+	return t.OnLookup(typ, result)
+}
+
+/**
+Filters beans resolved on demand by OnLookup hook of the context
+*/
+func (t *context) lookupBeans(typ reflect.Type, list []*bean) []*bean {
+	if t.hooks == nil || t.hooks.OnLookup == nil {
+		return list
+	}
+	result := make([]Bean, len(list))
+	for i, b := range list {
+		result[i] = b
+	}
+	var out []*bean
+	for _, b := range t.hooks.OnLookup(typ, result) {
+		if impl, ok := b.(*bean); ok {
+			out = append(out, impl)
+		}
+	}
+	return out
+}
+
+/**
+Checks beans chosen for injection in to the field against the sandbox and hooks of the context
+*/
+func (t *context) checkInjection(def *injectionDef, list []*bean) error {
+	for _, b := range list {
+		if err := t.sandbox.check(b.Class()); err != nil {
+			return errors.Errorf("injection of '%v' in to field '%s' in class '%v' is not allowed, %v", b.Class(), def.fieldName, def.class, err)
+		}
+	}
+	return t.hooks.inject(def, list)
+}
+
+func (t *Hooks) inject(def *injectionDef, list []*bean) error {
+	if t == nil || t.OnInject == nil {
+		return nil
+	}
+	chosen := make([]Bean, len(list))
+	for i, b := range list {
+		chosen[i] = b
+	}
+	if err := t.OnInject(reflect.PtrTo(def.class), def.fieldName, chosen); err != nil {
+		return errors.Errorf("injection in to field '%s' in class '%v' is denied, %v", def.fieldName, def.class, err)
+	}
+	return nil
+}
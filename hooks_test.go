@@ -0,0 +1,120 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"errors"
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"reflect"
+	"testing"
+)
+
+type hookSecrets struct {
+}
+
+type hookPlugin struct {
+	Secrets *hookSecrets `inject:""`
+}
+
+type hookService struct {
+	Secrets *hookSecrets `inject:""`
+}
+
+var hookSecretsClass = reflect.TypeOf((*hookSecrets)(nil))
+var hookPluginClass = reflect.TypeOf((*hookPlugin)(nil))
+
+func TestHooks(t *testing.T) {
+
+	denyPlugins := glue.Hooks{
+		OnInject: func(target reflect.Type, field string, chosen []glue.Bean) error {
+			if target == hookPluginClass && chosen[0].Class() == hookSecretsClass {
+				return errors.New("plugins can not use secrets")
+			}
+			return nil
+		},
+	}
+
+	_, err := glue.New(denyPlugins, &hookSecrets{}, &hookPlugin{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "plugins can not use secrets")
+
+	var injected []string
+	lookups := 0
+	ctx, err := glue.New(
+		glue.Hooks{
+			OnLookup: func(typ reflect.Type, result []glue.Bean) []glue.Bean {
+				lookups++
+				if typ == hookSecretsClass {
+					return nil
+				}
+				return result
+			},
+			OnInject: func(target reflect.Type, field string, chosen []glue.Bean) error {
+				injected = append(injected, field)
+				return nil
+			},
+		},
+		&hookSecrets{},
+		&hookService{},
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.Equal(t, []string{"Secrets"}, injected)
+
+	require.Empty(t, ctx.Bean(hookSecretsClass, glue.DefaultLevel))
+	require.Equal(t, 1, len(ctx.Lookup("*glue_test.hookSecrets", glue.DefaultLevel)))
+	require.Equal(t, 2, lookups)
+
+	plugin := &hookPlugin{}
+	require.NoError(t, ctx.Inject(plugin))
+	require.NotNil(t, plugin.Secrets)
+	require.Equal(t, []string{"Secrets", "Secrets"}, injected)
+
+	child, err := ctx.Extend(&hookPlugin{})
+	require.NoError(t, err)
+	defer child.Close()
+	require.Equal(t, 3, len(injected))
+
+	deny, err := glue.New(denyPlugins, &hookSecrets{})
+	require.NoError(t, err)
+	defer deny.Close()
+	require.Error(t, deny.Inject(&hookPlugin{}))
+}
+
+type hookWeakHolder struct {
+	Secrets *glue.WeakRef[*hookSecrets] `inject`
+}
+
+func TestHooksRuntimeResolution(t *testing.T) {
+
+	holder := &hookWeakHolder{}
+	ctx, err := glue.New(
+		glue.Hooks{
+			OnLookup: func(typ reflect.Type, result []glue.Bean) []glue.Bean {
+				if typ == hookSecretsClass {
+					return nil
+				}
+				return result
+			},
+		},
+		&hookSecrets{},
+		holder,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.Empty(t, glue.ListOf[*hookSecrets](ctx))
+
+	ctx.EachBean(glue.DefaultLevel, func(b glue.Bean) bool {
+		require.NotEqual(t, hookSecretsClass, b.Class())
+		return true
+	})
+
+	_, err = holder.Secrets.Get()
+	require.Error(t, err)
+}
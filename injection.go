@@ -8,6 +8,7 @@ package glue
 import (
 	"fmt"
 	"github.com/pkg/errors"
+	"log"
 	"io/fs"
 	"os"
 	"reflect"
@@ -25,6 +26,7 @@ var (
    timeClass = reflect.TypeOf(time.Time{})
    osFileModeClass = reflect.TypeOf(os.FileMode(0777))
    fsFileModeClass = reflect.TypeOf(fs.FileMode(0777))
+   locationClass = reflect.TypeOf((*time.Location)(nil))
 )
 
 type injectionDef struct {
@@ -61,11 +63,47 @@ type injectionDef struct {
 	Optional injection
 	*/
 	optional bool
+	/**
+	All candidates must implement OrderedBean
+	*/
+	ordered bool
+	/**
+	Slice is ordered by dependencies of candidates, the dependency goes before the bean that uses it
+	*/
+	topo bool
+	/**
+	Type of the bean referenced by glue.WeakRef field, nil for regular injection
+	*/
+	weakType reflect.Type
 	/*
 	Injection expects the specific bean to be injected
 	*/
 	qualifier string
 	/**
+	Policy for beans with the same name in map injection: first, last or error (default)
+	*/
+	onDuplicate string
+	/**
+	Transformation of bean names to keys of map injection, nil means bean name as is
+	*/
+	mapKey func(string) string
+	/**
+	Names of 'keyCase' and 'keyFunc' attributes the transformation made of
+	*/
+	keyCase, keyFunc string
+	/**
+	Bean must not receive itself among candidates
+	*/
+	excludeSelf bool
+	/**
+	Additional interfaces that candidates must implement
+	*/
+	also []reflect.Type
+	/**
+	Boolean property that enables injection, field stays empty if property is false or missing
+	*/
+	when string
+	/**
 	Level of how deep we need to search beans for injection
 
 	level 0: look in the current context, if not found then look in the parent context and so on (default)
@@ -94,6 +132,11 @@ type injection struct {
 	Injection information
 	*/
 	injectionDef *injectionDef
+
+	/**
+	Beans injected in to the slice field to order by dependencies after wiring
+	*/
+	topoList []*bean
 }
 
 type propInjectionDef struct {
@@ -132,6 +175,16 @@ type propInjectionDef struct {
 	Layout for date-time property
 	 */
 	layout  string
+
+	/**
+	Value of the property must not appear in dumps, logs and errors
+	 */
+	sensitive bool
+
+	/**
+	Properties of the nested structure with names relative to the property name, nil if field is not a structure
+	 */
+	nested []*propInjectionDef
 }
 
 /*
@@ -171,7 +224,17 @@ func levelBeans(deep []beanlist, level int) []*bean {
 /**
 	Order beans, all or partially
  */
-func orderBeans(candidates []*bean) []*bean {
+func orderBeans(candidates []*bean, logger *log.Logger) []*bean {
+	list := orderBeansByNumber(candidates)
+	for _, b := range list {
+		if len(b.after) > 0 || len(b.before) > 0 {
+			return orderBeansByConstraints(list, logger)
+		}
+	}
+	return list
+}
+
+func orderBeansByNumber(candidates []*bean) []*bean {
 	var ordered []*bean
 	for _, candidate := range candidates {
 		if candidate.ordered {
@@ -199,25 +262,102 @@ func orderBeans(candidates []*bean) []*bean {
 }
 
 /**
-Inject value in to the field by using reflection
-*/
-func (t *injection) inject(deep []beanlist) error {
+	Stable topological sort of beans by OrderedAfter and OrderedBefore constraints.
+	Beans without constraints between each other preserve the incoming order.
+	On cycle the rest of beans preserve the incoming order.
+ */
+func orderBeansByConstraints(list []*bean, logger *log.Logger) []*bean {
+	n := len(list)
+	edges := make([][]int, n)
+	degree := make([]int, n)
+	link := func(from, to int) {
+		edges[from] = append(edges[from], to)
+		degree[to]++
+	}
+	for j, b := range list {
+		for _, typ := range b.after {
+			for i, other := range list {
+				if i != j && other.matches(typ) {
+					link(i, j)
+				}
+			}
+		}
+		for _, typ := range b.before {
+			for i, other := range list {
+				if i != j && other.matches(typ) {
+					link(j, i)
+				}
+			}
+		}
+	}
+	result := make([]*bean, 0, n)
+	done := make([]bool, n)
+	for len(result) < n {
+		next := -1
+		for i := 0; i < n; i++ {
+			if !done[i] && degree[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next == -1 {
+			if logger != nil {
+				logger.Printf("Cycle in OrderedAfter/OrderedBefore constraints among %v\n", list)
+			}
+			for i := 0; i < n; i++ {
+				if !done[i] {
+					result = append(result, list[i])
+				}
+			}
+			return result
+		}
+		done[next] = true
+		result = append(result, list[next])
+		for _, to := range edges[next] {
+			degree[to]--
+		}
+	}
+	return result
+}
 
-	list := orderBeans(levelBeans(deep, t.injectionDef.level))
+/**
+Inject value in to the field by using reflection, record is optional and collects the decision
+*/
+func (t *injection) inject(deep []beanlist, record *InjectionRecord, ctx *context) error {
 
 	field := t.value.Field(t.injectionDef.fieldNum)
 	if !field.CanSet() {
 		return errors.Errorf("field '%s' in class '%v' is not public", t.injectionDef.fieldName, t.injectionDef.class)
 	}
 
-	list = t.injectionDef.filterBeans(list)
+	list := t.injectionDef.excludeObject(t.injectionDef.selectBeans(deep, record, ctx.logger()), t.bean.obj, record)
+
+	if record != nil {
+		if len(list) > 1 && !t.injectionDef.slice && !t.injectionDef.table {
+			for _, b := range list {
+				record.reject(b, "one of multiple candidates for the single field")
+			}
+		} else {
+			record.choose(list)
+		}
+	}
+
+	if err := t.injectionDef.checkOrdered(list); err != nil {
+		return err
+	}
+
+	if len(list) > 0 {
+		if err := ctx.checkInjection(t.injectionDef, list); err != nil {
+			return err
+		}
+	}
 
 	if len(list) == 0 {
 		if !t.injectionDef.optional {
 			if t.injectionDef.qualifier != "" {
-				return errors.Errorf("can not find candidates to inject the required field '%s' in class '%v' with qualifier '%s'", t.injectionDef.fieldName, t.injectionDef.class, t.injectionDef.qualifier)
+				return errors.Errorf("can not find candidates to inject the required field '%s' in class '%v' with qualifier '%s'%s", t.injectionDef.fieldName, t.injectionDef.class, t.injectionDef.qualifier, ctx.genericHint(t.injectionDef.fieldType))
 			} else {
-				return errors.Errorf("can not find candidates to inject the required field '%s' in class '%v'", t.injectionDef.fieldName, t.injectionDef.class)
+				return errors.Errorf("can not find candidates to inject the required field '%s' in class '%v'%s", t.injectionDef.fieldName, t.injectionDef.class, ctx.genericHint(t.injectionDef.fieldType))
 			}
 		}
 		return nil
@@ -232,6 +372,10 @@ func (t *injection) inject(deep []beanlist) error {
 				factoryList = append(factoryList, impl)
 			} else {
 				newSlice = reflect.Append(newSlice, impl.valuePtr)
+				if t.injectionDef.topo {
+					t.topoList = append(t.topoList, impl)
+				}
+				t.bean.consume(impl)
 
 				// register dependency that 'inject.bean' is using if it is not lazy
 				if !t.injectionDef.lazy && t.bean != impl {
@@ -243,6 +387,8 @@ func (t *injection) inject(deep []beanlist) error {
 		field.Set(newSlice)
 
 		for _, instance := range factoryList {
+			instance.injected = true
+			t.bean.consume(instance)
 			// register factory dependency for 'inject.bean' that is using 'factory'
 			t.bean.factoryDependencies = append(t.bean.factoryDependencies,
 				&factoryDependency{
@@ -264,25 +410,34 @@ func (t *injection) inject(deep []beanlist) error {
 		visited := make(map[string]bool)
 		for _, impl := range list {
 			if impl.beenFactory != nil {
+				impl.injected = true
+				t.bean.consume(impl)
 				// register factory dependency for 'inject.bean' that is using 'factory'
 				t.bean.factoryDependencies = append(t.bean.factoryDependencies,
 					&factoryDependency{
 						factory: impl.beenFactory,
 						injection: func(service *bean) error {
-							if visited[service.name] {
-								return errors.Errorf("can not inject duplicates '%s' to the map field '%s' in class '%v' by injecting factory bean '%v'", impl.name, t.injectionDef.fieldName, t.injectionDef.class, service.obj)
+							key := t.injectionDef.key(service.name)
+							put, duplicate := t.injectionDef.putEntry(visited, key)
+							if duplicate {
+								return errors.Errorf("can not inject duplicates '%s' to the map field '%s' in class '%v' by injecting factory bean '%v'", key, t.injectionDef.fieldName, t.injectionDef.class, service.obj)
+							}
+							if put {
+								field.SetMapIndex(reflect.ValueOf(key), service.valuePtr)
 							}
-							visited[service.name] = true
-							field.SetMapIndex(reflect.ValueOf(service.name), service.valuePtr)
 							return nil
 						},
 					})
 			} else {
-				if visited[impl.name] {
-					return errors.Errorf("can not inject duplicates '%s' to the map field '%s' in class '%v' by injecting impl '%v'", impl.name, t.injectionDef.fieldName, t.injectionDef.class, impl.obj)
+				key := t.injectionDef.key(impl.name)
+				put, duplicate := t.injectionDef.putEntry(visited, key)
+				if duplicate {
+					return errors.Errorf("can not inject duplicates '%s' to the map field '%s' in class '%v' by injecting impl '%v'", key, t.injectionDef.fieldName, t.injectionDef.class, impl.obj)
 				}
-				visited[impl.name] = true
-				field.SetMapIndex(reflect.ValueOf(impl.name), impl.valuePtr)
+				if put {
+					field.SetMapIndex(reflect.ValueOf(key), impl.valuePtr)
+				}
+				t.bean.consume(impl)
 
 				// register dependency that 'inject.bean' is using if it is not lazy
 				if !t.injectionDef.lazy && t.bean != impl {
@@ -301,9 +456,11 @@ func (t *injection) inject(deep []beanlist) error {
 	impl := list[0]
 
 	if impl.beenFactory != nil {
+		impl.injected = true
 		if t.injectionDef.lazy {
 			return errors.Errorf("lazy injection is not supported of type '%v' through factory '%v' in to '%v'", impl.beenFactory.factoryBean.ObjectType(), impl.beenFactory.factoryClassPtr, t.String())
 		}
+		t.bean.consume(impl)
 
 		// register factory dependency for 'inject.bean' that is using 'factory'
 		t.bean.factoryDependencies = append(t.bean.factoryDependencies,
@@ -318,7 +475,8 @@ func (t *injection) inject(deep []beanlist) error {
 		return nil
 	}
 
-	field.Set(impl.valuePtr)
+	field.Set(t.injectionDef.contextView(impl))
+	t.bean.consume(impl)
 
 	// register dependency that 'inject.bean' is using if it is not lazy
 	if !t.injectionDef.lazy && t.bean != impl {
@@ -334,27 +492,42 @@ func atomicSet(field reflect.Value, instance reflect.Value) {
 }
 
 // runtime injection
-func (t *injectionDef) inject(value *reflect.Value, deep []beanlist) error {
-
-	list := orderBeans(levelBeans(deep, t.level))
+func (t *injectionDef) inject(value *reflect.Value, deep []beanlist, ctx *context, logger *log.Logger) ([]*bean, error) {
 
 	field := value.Field(t.fieldNum)
 
 	if !field.CanSet() {
-		return errors.Errorf("field '%s' in class '%v' is not public", t.fieldName, t.class)
+		return nil, errors.Errorf("field '%s' in class '%v' is not public", t.fieldName, t.class)
 	}
 
-	list = t.filterBeans(list)
+	list := t.excludeObject(t.selectBeans(deep, nil, logger), value.Addr().Interface(), nil)
+
+	if err := t.checkOrdered(list); err != nil {
+		return nil, err
+	}
+
+	if len(list) > 0 {
+		if err := ctx.checkInjection(t, list); err != nil {
+			return nil, err
+		}
+	}
+
+	if t.topo {
+		var err error
+		if list, err = topoOrder(list); err != nil {
+			return nil, errors.Errorf("field '%s' in class '%v' can not be ordered by dependencies, %v", t.fieldName, t.class, err)
+		}
+	}
 
 	if len(list) == 0 {
 		if !t.optional {
 			if t.qualifier != "" {
-				return errors.Errorf("can not find candidates to inject the required field '%s' in class '%v' with qualifier '%s'", t.fieldName, t.class, t.qualifier)
+				return nil, errors.Errorf("can not find candidates to inject the required field '%s' in class '%v' with qualifier '%s'%s", t.fieldName, t.class, t.qualifier, ctx.genericHint(t.fieldType))
 			} else {
-				return errors.Errorf("can not find candidates to inject the required field '%s' in class '%v'", t.fieldName, t.class)
+				return nil, errors.Errorf("can not find candidates to inject the required field '%s' in class '%v'%s", t.fieldName, t.class, ctx.genericHint(t.fieldType))
 			}
 		}
-		return nil
+		return nil, nil
 	}
 
 	if t.slice {
@@ -368,7 +541,7 @@ func (t *injectionDef) inject(value *reflect.Value, deep []beanlist) error {
 			}
 		}
 		field.Set(newSlice)
-		return nil
+		return list, nil
 	}
 
 	if t.table {
@@ -378,39 +551,205 @@ func (t *injectionDef) inject(value *reflect.Value, deep []beanlist) error {
 		visited := make(map[string]bool)
 		for _, instance := range list {
 			if !instance.valuePtr.IsValid() {
-				if visited[instance.name] {
-					return errors.Errorf("can not inject duplicates '%s' to the map field '%s' in class '%v'", instance.name, t.fieldName, t.class)
+				key := t.key(instance.name)
+				put, duplicate := t.putEntry(visited, key)
+				if duplicate {
+					return nil, errors.Errorf("can not inject duplicates '%s' to the map field '%s' in class '%v'", key, t.fieldName, t.class)
+				}
+				if put {
+					field.SetMapIndex(reflect.ValueOf(key), instance.valuePtr)
 				}
-				visited[instance.name] = true
-				field.SetMapIndex(reflect.ValueOf(instance.name), instance.valuePtr)
 			}
 		}
 
-		return nil
+		return list, nil
 	}
 
 	if len(list) > 1 {
-		return errors.Errorf("field '%s' in class '%v' can not be injected with multiple candidates %+v", t.fieldName, t.class, list)
+		return nil, errors.Errorf("field '%s' in class '%v' can not be injected with multiple candidates %+v", t.fieldName, t.class, list)
 	}
 
 	impl := list[0]
 
-	if impl.lifecycle != BeanInitialized {
-		return errors.Errorf("field '%s' in class '%v' can not be injected with non-initialized bean %+v", t.fieldName, t.class, impl)
+	if impl.Lifecycle() != BeanInitialized {
+		return nil, errors.Errorf("field '%s' in class '%v' can not be injected with non-initialized bean %+v", t.fieldName, t.class, impl)
 	}
 
 	if impl.beenFactory != nil {
 
-		service, _, err := impl.beenFactory.ctor()
+		service, _, err := impl.beenFactory.ctorWithRetry(logger)
 		if err != nil {
-			return errors.Errorf("field '%s' in class '%v' can not be injected because of factory bean %+v error, %v", t.fieldName, t.class, impl, err)
+			return nil, errors.Wrapf(err, "field '%s' in class '%v' can not be injected because of factory bean %+v error", t.fieldName, t.class, impl)
 		}
 
 		impl = service
 	}
 
-	field.Set(impl.valuePtr)
+	field.Set(t.contextView(impl))
+
+	return list, nil
+}
+
+/**
+Select candidates for injection by level, order and qualifier, record is optional and collects rejected candidates
+*/
+func (t *injectionDef) selectBeans(deep []beanlist, record *InjectionRecord, logger *log.Logger) []*bean {
+	list := orderBeans(levelBeans(deep, t.level), logger)
+	if record != nil {
+		record.rejectLevel(deep, list)
+	}
+	filtered := t.filterBeans(list)
+	if record != nil {
+		record.rejectQualifier(list, filtered)
+	}
+	return t.preferPrimary(t.filterAlso(filtered, record), record)
+}
+
+/**
+Selects the primary bean among multiple candidates for the single field
+*/
+func (t *injectionDef) preferPrimary(list []*bean, record *InjectionRecord) []*bean {
+	if len(list) < 2 || t.slice || t.table {
+		return list
+	}
+	var primary *bean
+	for _, b := range list {
+		if b.primary {
+			if primary != nil {
+				return list
+			}
+			primary = b
+		}
+	}
+	if primary == nil {
+		return list
+	}
+	if record != nil {
+		for _, b := range list {
+			if b != primary {
+				record.reject(b, "not primary")
+			}
+		}
+	}
+	return []*bean{primary}
+}
+
+const (
+	duplicateFirst = "first"
+	duplicateLast  = "last"
+	duplicateError = "error"
+)
+
+/**
+Returns key of the map injection for the bean name
+*/
+func (t *injectionDef) key(name string) string {
+	if t.mapKey != nil {
+		return t.mapKey(name)
+	}
+	return name
+}
+
+/**
+Resolve transformation of keys for map injection by 'keyCase' and 'keyFunc' attributes, key case applies first
+*/
+func (t *injectionDef) resolveMapKey(keyCase, keyFunc string) error {
+	t.keyCase, t.keyFunc = keyCase, keyFunc
+	var fns []func(string) string
+	if keyCase != "" {
+		fn, ok := keyCases[keyCase]
+		if !ok {
+			return errors.Errorf("invalid 'keyCase' attribute '%s', expected lower, upper or short", keyCase)
+		}
+		fns = append(fns, fn)
+	}
+	if keyFunc != "" {
+		fn, ok := lookupKeyFunc(keyFunc)
+		if !ok {
+			return errors.Errorf("unknown 'keyFunc' attribute '%s', register it by glue.RegisterKeyFunc", keyFunc)
+		}
+		fns = append(fns, fn)
+	}
+	switch len(fns) {
+	case 0:
+		t.mapKey = nil
+	case 1:
+		t.mapKey = fns[0]
+	default:
+		t.mapKey = func(name string) string {
+			return fns[1](fns[0](name))
+		}
+	}
+	return nil
+}
+
+/**
+Check if the bean with the name goes to the map field, duplicate is true if the name was already injected and policy does not allow it
+*/
+func (t *injectionDef) putEntry(visited map[string]bool, name string) (put bool, duplicate bool) {
+	if !visited[name] {
+		visited[name] = true
+		return true, false
+	}
+	switch t.onDuplicate {
+	case duplicateFirst:
+		return false, false
+	case duplicateLast:
+		return true, false
+	default:
+		return false, true
+	}
+}
+
+/**
+Drop the bean of the object itself from candidates if the injection excludes self
+*/
+func (t *injectionDef) excludeObject(list []*bean, obj interface{}, record *InjectionRecord) []*bean {
+	if !t.excludeSelf {
+		return list
+	}
+	var candidates []*bean
+	for _, b := range list {
+		if b.obj == obj {
+			if record != nil {
+				record.reject(b, "bean itself is excluded")
+			}
+		} else {
+			candidates = append(candidates, b)
+		}
+	}
+	return candidates
+}
+
+/**
+Keep only candidates implementing all additional interfaces
+*/
+func (t *injectionDef) filterAlso(list []*bean, record *InjectionRecord) []*bean {
+	if len(t.also) == 0 {
+		return list
+	}
+	var candidates []*bean
+	for _, b := range list {
+		if iface, ok := b.implementsAll(t.also); ok {
+			candidates = append(candidates, b)
+		} else if record != nil {
+			record.reject(b, "does not implement '%v'", iface)
+		}
+	}
+	return candidates
+}
 
+/**
+Check that all candidates have order if the injection requires it
+*/
+func (t *injectionDef) checkOrdered(list []*bean) error {
+	if t.ordered {
+		for _, b := range list {
+			if !b.isOrdered() {
+				return errors.Errorf("field '%s' in class '%v' requires ordered beans, but bean '%s' does not implement glue.OrderedBean", t.fieldName, t.class, b.name)
+			}
+		}
+	}
 	return nil
 }
 
@@ -453,11 +792,23 @@ func (t *propInjectionDef) inject(value *reflect.Value, properties Properties) e
 		return errors.Errorf("field '%s' in class '%v' is not public", t.fieldName, t.class)
 	}
 
+	if t.sensitive {
+		properties.MarkSensitive(t.propertyName)
+	}
+
+	if t.nested != nil {
+		return t.injectNested(field, properties.Sub(t.propertyName))
+	}
+
+	if isMap(t.fieldType) {
+		return t.injectMap(field, properties)
+	}
+
 	strValue := properties.GetString(t.propertyName, t.defaultValue)
 
 	v, err := convertProperty(strValue, t.fieldType, t.layout)
 	if err != nil {
-		return errors.Errorf("property '%s' in class '%v' has convert error, property resolvers %+v, %v", t.fieldName, t.class, properties.PropertyResolvers(), err)
+		return errors.Errorf("property '%s' in class '%v' has convert error, property resolvers %+v, %v", t.fieldName, t.class, properties.PropertyResolvers(), redactError(properties, t.propertyName, err))
 	}
 
 	field.Set(v)
@@ -465,6 +816,103 @@ func (t *propInjectionDef) inject(value *reflect.Value, properties Properties) e
 
 }
 
+/**
+Bind properties under the prefix to the fields of nested structure or pointer to it
+*/
+func (t *propInjectionDef) injectNested(field reflect.Value, properties Properties) error {
+	target := field
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			field.Set(reflect.New(t.fieldType.Elem()))
+		}
+		target = field.Elem()
+	}
+	for _, def := range t.nested {
+		if err := def.inject(&target, properties); err != nil {
+			return errors.Errorf("property '%s' in class '%v' has nested error, %v", t.fieldName, t.class, err)
+		}
+	}
+	return nil
+}
+
+/**
+Bind all properties under the prefix to the map with relative keys.
+Default value has format 'key=value;key=value' and used if there are no properties under the prefix.
+*/
+func (t *propInjectionDef) injectMap(field reflect.Value, properties Properties) error {
+	prefix := t.propertyName + "."
+	m := reflect.MakeMap(t.fieldType)
+	elemType := t.fieldType.Elem()
+	put := func(key, value string) error {
+		v, err := convertProperty(value, elemType, t.layout)
+		if err != nil {
+			return errors.Errorf("property '%s' in class '%v' has convert error for key '%s', %v", t.fieldName, t.class, key, redactError(properties, prefix+key, err))
+		}
+		m.SetMapIndex(reflect.ValueOf(key).Convert(t.fieldType.Key()), v)
+		return nil
+	}
+	keys := collectPropertyKeys(properties, prefix)
+	for _, key := range keys {
+		if value, ok := properties.Get(prefix + key); ok {
+			if err := put(key, value); err != nil {
+				return err
+			}
+		}
+	}
+	if len(keys) == 0 {
+		for _, pair := range trimSplit(t.defaultValue, ";") {
+			kv := strings.SplitN(pair, "=", 2)
+			if len(kv) != 2 {
+				return errors.Errorf("property '%s' in class '%v' has invalid default map entry '%s'", t.fieldName, t.class, pair)
+			}
+			if err := put(strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])); err != nil {
+				return err
+			}
+		}
+	}
+	field.Set(m)
+	return nil
+}
+
+/**
+Collects keys under the prefix from properties and all property resolvers that expose keys, returns keys relative to the prefix
+*/
+func collectPropertyKeys(properties Properties, prefix string) []string {
+	if sub, ok := properties.(*subProperties); ok {
+		return collectPropertyKeys(sub.parent, sub.prefix + prefix)
+	}
+	type keysResolver interface {
+		Keys() []string
+	}
+	var keys []string
+	visited := make(map[string]bool)
+	for _, r := range properties.PropertyResolvers() {
+		if kr, ok := r.(keysResolver); ok {
+			for _, key := range kr.Keys() {
+				if strings.HasPrefix(key, prefix) && !visited[key] {
+					visited[key] = true
+					keys = append(keys, key[len(prefix):])
+				}
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+/**
+Returns structure type if the field type is structure or pointer to structure that has own properties
+*/
+func nestedPropertyClass(t reflect.Type) (reflect.Type, bool) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Struct && !isTime(t) && t != locationClass.Elem() && t != cronSpecClass {
+		return t, true
+	}
+	return nil, false
+}
+
 func convertProperty(s string, t reflect.Type, layout string) (val reflect.Value, err error) {
 	var v interface{}
 
@@ -492,7 +940,13 @@ func convertProperty(s string, t reflect.Type, layout string) (val reflect.Value
 		v, err = time.Parse(layout, s)
 
 	case isFileMode(t):
-		v, err = parseFileMode(s), nil
+		v, err = parseFileMode(s)
+
+	case t == locationClass:
+		v, err = time.LoadLocation(s)
+
+	case t == cronSpecClass:
+		v, err = ParseCronSpec(s)
 
 	case isBool(t):
 		v, err = parseBool(s)
@@ -501,13 +955,13 @@ func convertProperty(s string, t reflect.Type, layout string) (val reflect.Value
 		v, err = s, nil
 
 	case isFloat(t):
-		v, err = strconv.ParseFloat(s, 64)
+		v, err = strconv.ParseFloat(s, t.Bits())
 
 	case isInt(t):
-		v, err = strconv.ParseInt(s, 10, 64)
+		v, err = parseInt(s, t.Bits())
 
 	case isUint(t):
-		v, err = strconv.ParseUint(s, 10, 64)
+		v, err = parseUint(s, t.Bits())
 
 	default:
 		return reflect.Zero(t), fmt.Errorf("unsupported type %s", t)
@@ -552,6 +1006,10 @@ func isFileMode(t reflect.Type) bool {
 	return t == osFileModeClass || t == fsFileModeClass
 }
 
+func isMap(t reflect.Type) bool {
+	return t.Kind() == reflect.Map && t.Key().Kind() == reflect.String
+}
+
 func isArray(t reflect.Type) bool {
 	return t.Kind() == reflect.Array || t.Kind() == reflect.Slice
 }
@@ -564,4 +1022,18 @@ func trimSplit(s string, sep string) []string {
 		}
 	}
 	return a
-}
\ No newline at end of file
+}
+
+/**
+Check if injection is enabled by the boolean property in 'when' attribute
+*/
+func (t *injectionDef) enabled(properties Properties) (bool, error) {
+	if t.when == "" {
+		return true, nil
+	}
+	enabled, err := propertyEnabled(properties, t.when)
+	if err != nil {
+		return false, errors.Errorf("field '%s' in class '%v' with 'when' attribute, %v", t.fieldName, t.class, err)
+	}
+	return enabled, nil
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"io"
+	"reflect"
+	"sync"
+)
+
+/**
+Interfaces known by name for 'also' attribute of 'inject' tag
+*/
+var knownInterfaces = struct {
+	sync.RWMutex
+	byName map[string]reflect.Type
+}{
+	byName: make(map[string]reflect.Type),
+}
+
+func init() {
+	for _, iface := range []interface{}{
+		(*io.Closer)(nil),
+		(*io.Reader)(nil),
+		(*io.Writer)(nil),
+		(*fmt.Stringer)(nil),
+		(*InitializingBean)(nil),
+		(*DisposableBean)(nil),
+		(*NamedBean)(nil),
+		(*OrderedBean)(nil),
+	} {
+		RegisterInterface(iface)
+	}
+}
+
+/**
+Registers interface by the full name to be used in 'also' attribute of 'inject' tag.
+Interface is given by reflect.Type or by nil pointer like (*Handler)(nil).
+
+Example:
+	glue.RegisterInterface((*Handler)(nil))
+
+	type pipeline struct {
+		Stages []Stage `inject:"also=app.Handler"`
+	}
+*/
+func RegisterInterface(iface interface{}) error {
+	typ := classOf(iface)
+	if typ == nil || typ.Kind() != reflect.Interface {
+		return errors.Errorf("expected interface, but was '%v'", typ)
+	}
+	knownInterfaces.Lock()
+	defer knownInterfaces.Unlock()
+	knownInterfaces.byName[typ.String()] = typ
+	return nil
+}
+
+func lookupInterface(name string) (reflect.Type, bool) {
+	knownInterfaces.RLock()
+	defer knownInterfaces.RUnlock()
+	typ, ok := knownInterfaces.byName[name]
+	return typ, ok
+}
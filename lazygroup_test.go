@@ -0,0 +1,151 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"bytes"
+	"errors"
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var reportGeneratorClass = reflect.TypeOf((*reportGenerator)(nil))
+
+type reportGenerator struct {
+	Storage     *reportStorage `inject`
+	constructed int
+	destroyed   int
+}
+
+func (t *reportGenerator) PostConstruct() error {
+	t.constructed++
+	return nil
+}
+
+func (t *reportGenerator) Destroy() error {
+	t.destroyed++
+	return nil
+}
+
+var reportStorageClass = reflect.TypeOf((*reportStorage)(nil))
+
+type reportStorage struct {
+	constructed int
+}
+
+func (t *reportStorage) PostConstruct() error {
+	t.constructed++
+	return nil
+}
+
+type reportConsumer struct {
+	Generator *reportGenerator `inject`
+}
+
+func TestLazyGroup(t *testing.T) {
+
+	generator := &reportGenerator{}
+	storage := &reportStorage{}
+
+	ctx, err := glue.New(
+		glue.Lazy(
+			generator,
+			storage,
+		),
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, 0, generator.constructed)
+	require.Equal(t, 0, storage.constructed)
+	require.True(t, generator.Storage == storage)
+
+	list := ctx.Bean(reportGeneratorClass, glue.DefaultLevel)
+	require.Equal(t, 1, len(list))
+	require.Equal(t, glue.BeanInitialized, list[0].Lifecycle())
+	require.Equal(t, 1, generator.constructed)
+	require.Equal(t, 1, storage.constructed)
+
+	// second lookup does not construct again
+	ctx.Bean(reportGeneratorClass, glue.DefaultLevel)
+	require.Equal(t, 1, generator.constructed)
+
+	ctx.Close()
+	require.Equal(t, 1, generator.destroyed)
+}
+
+func TestLazyGroupInjection(t *testing.T) {
+
+	generator := &reportGenerator{}
+	storage := &reportStorage{}
+
+	// eager consumer forces construction of the lazy dependency
+	ctx, err := glue.New(
+		glue.Lazy(generator, storage),
+		&reportConsumer{},
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+	require.Equal(t, 1, generator.constructed)
+
+	storage = &reportStorage{}
+	ctx, err = glue.New(
+		glue.Lazy(storage),
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+	require.Equal(t, 0, storage.constructed)
+
+	holder := &struct {
+		Storage *reportStorage `inject`
+	}{}
+	require.NoError(t, ctx.Inject(holder))
+	require.True(t, holder.Storage == storage)
+	require.Equal(t, 1, storage.constructed)
+
+	storage = &reportStorage{}
+	ctx, err = glue.New(
+		glue.Lazy(storage),
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+	require.NoError(t, ctx.Prime(reportStorageClass))
+	require.Equal(t, 1, storage.constructed)
+}
+
+var brokenReportClass = reflect.TypeOf((*brokenReport)(nil))
+
+type brokenReport struct {
+}
+
+func (t *brokenReport) BeanName() string {
+	return "brokenReport"
+}
+
+func (t *brokenReport) PostConstruct() error {
+	return errors.New("report template is missing")
+}
+
+func TestLazyGroupFailure(t *testing.T) {
+
+	var buf bytes.Buffer
+	prev := glue.Warnings(log.New(&buf, "", 0))
+	defer glue.Warnings(prev)
+
+	ctx, err := glue.New(
+		glue.Lazy(&brokenReport{}),
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.Empty(t, ctx.Bean(brokenReportClass, glue.DefaultLevel))
+	require.Empty(t, ctx.Lookup("brokenReport", glue.DefaultLevel))
+	require.Empty(t, ctx.LookupPattern("broken*", glue.DefaultLevel))
+	require.Equal(t, 3, strings.Count(buf.String(), "report template is missing"))
+}
@@ -0,0 +1,202 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	stdcontext "context"
+	"github.com/pkg/errors"
+	"log"
+	"reflect"
+	"sync"
+	"time"
+)
+
+/**
+This interface used by beans that are active only on the leader instance of the application, for example schedulers and singleton workers.
+*/
+var LeaderAwareClass = reflect.TypeOf((*LeaderAware)(nil)).Elem()
+
+type LeaderAware interface {
+
+	/**
+	Called when the instance becomes the leader
+	*/
+	OnElected()
+
+	/**
+	Called when the instance loses the leadership, also on close of the context if it is the leader
+	*/
+	OnRevoked()
+}
+
+/**
+This interface used by the bean that elects the leader among instances of the application (etcd, consul, kubernetes lease).
+Context runs the elector after all beans are constructed and notifies LeaderAware beans of the current context on changes,
+only one elector bean is allowed in the context.
+*/
+var LeaderElectorClass = reflect.TypeOf((*LeaderElector)(nil)).Elem()
+
+type LeaderElector interface {
+
+	/**
+	Campaigns for leadership until ctx is done and calls notify on every change of leadership
+	*/
+	Run(ctx stdcontext.Context, notify func(elected bool)) error
+}
+
+/**
+Delay before the first restart of the failed leader elector, doubled for every next one
+*/
+var LeaderElectorRestartBackoff = time.Second
+
+/**
+Upper limit of the delay between restarts of the failed leader elector
+*/
+var LeaderElectorMaxRestartBackoff = time.Minute
+
+/**
+Running leader election of the context
+*/
+type leaderElection struct {
+	cancel  stdcontext.CancelFunc
+	done    chan struct{}
+	mu      sync.Mutex
+	elected bool
+	aware   []*bean
+	logger  func() *log.Logger
+}
+
+/**
+Starts the elector bean of the context if present
+*/
+func (t *context) startLeaderElection() error {
+	var electors, aware []*bean
+	t.eachBean(func(b Bean) bool {
+		if impl, ok := b.(*bean); ok && impl.Lifecycle() == BeanInitialized {
+			if _, ok := impl.obj.(LeaderElector); ok {
+				electors = append(electors, impl)
+			}
+			if _, ok := impl.obj.(LeaderAware); ok {
+				aware = append(aware, impl)
+			}
+		}
+		return true
+	})
+	switch len(electors) {
+	case 0:
+		return nil
+	case 1:
+	default:
+		return errors.Errorf("context has multiple leader electors %+v", electors)
+	}
+	elector := electors[0]
+	ctx, cancel := stdcontext.WithCancel(stdcontext.Background())
+	election := &leaderElection{cancel: cancel, done: make(chan struct{}), aware: aware, logger: t.logger}
+	go func() {
+		defer close(election.done)
+		t.superviseElector(ctx, elector, election)
+		election.notify(false)
+	}()
+	t.election = election
+	return nil
+}
+
+/**
+Runs the elector until ctx is done, the failed elector revokes the leadership and restarts with backoff
+*/
+func (t *context) superviseElector(ctx stdcontext.Context, elector *bean, election *leaderElection) {
+	backoff := LeaderElectorRestartBackoff
+	for {
+		started := time.Now()
+		err := runElector(ctx, elector, election.notify)
+		if ctx.Err() != nil || err == nil {
+			return
+		}
+		election.notify(false)
+		if time.Since(started) > LeaderElectorMaxRestartBackoff {
+			backoff = LeaderElectorRestartBackoff
+		}
+		warnf("Leader elector '%s' with type '%v' failed, restart in %v, %v\n", elector.name, elector.beanDef.classPtr, backoff, err)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return
+		}
+		backoff *= 2
+		if LeaderElectorMaxRestartBackoff > 0 && backoff > LeaderElectorMaxRestartBackoff {
+			backoff = LeaderElectorMaxRestartBackoff
+		}
+	}
+}
+
+func runElector(ctx stdcontext.Context, b *bean, notify func(elected bool)) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("leader elector '%s' with type '%v' recovered with error: %v", b.name, b.beanDef.classPtr, r)
+		}
+	}()
+	return b.obj.(LeaderElector).Run(ctx, notify)
+}
+
+/**
+Notifies LeaderAware beans on change of leadership, repeated notifications are ignored
+*/
+func (t *leaderElection) notify(elected bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.elected == elected {
+		return
+	}
+	t.elected = elected
+	for _, b := range t.aware {
+		if t.logger() != nil {
+			t.logger().Printf("Leadership elected=%v of bean '%s' with type '%v'\n", elected, b.name, b.beanDef.classPtr)
+		}
+		notifyLeaderAware(b, elected, t.logger())
+	}
+}
+
+func notifyLeaderAware(b *bean, elected bool, logger *log.Logger) {
+	defer func() {
+		if r := recover(); r != nil && logger != nil {
+			logger.Printf("Leader aware bean '%s' with type '%v' recovered with error: %v\n", b.name, b.beanDef.classPtr, r)
+		}
+	}()
+	if elected {
+		b.obj.(LeaderAware).OnElected()
+	} else {
+		b.obj.(LeaderAware).OnRevoked()
+	}
+}
+
+/**
+Stops the elector and revokes leadership of LeaderAware beans, zero timeout waits without limit.
+The elector is abandoned once ctx is done, that is recorded in canceled.
+*/
+func (t *context) stopLeaderElection(ctx stdcontext.Context, timeout time.Duration, canceled *CloseCanceledError) []error {
+	election := t.election
+	if election == nil {
+		return nil
+	}
+	election.cancel()
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	select {
+	case <-election.done:
+		return nil
+	case <-expired:
+		election.notify(false)
+		return []error{errors.Errorf("leader elector did not return in %v after cancel", timeout)}
+	case <-ctx.Done():
+		election.notify(false)
+		canceled.interrupt(ctx, StopPhase)
+		return nil
+	}
+}
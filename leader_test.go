@@ -0,0 +1,133 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"log"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type manualElector struct {
+	changes chan bool
+}
+
+func (t *manualElector) Run(ctx context.Context, notify func(elected bool)) error {
+	for {
+		select {
+		case elected := <-t.changes:
+			notify(elected)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+type leaderScheduler struct {
+	mu     sync.Mutex
+	events []string
+	ch     chan string
+}
+
+func (t *leaderScheduler) OnElected() {
+	t.mu.Lock()
+	t.events = append(t.events, "elected")
+	t.mu.Unlock()
+	t.ch <- "elected"
+}
+
+func (t *leaderScheduler) OnRevoked() {
+	t.mu.Lock()
+	t.events = append(t.events, "revoked")
+	t.mu.Unlock()
+	t.ch <- "revoked"
+}
+
+func TestLeaderElection(t *testing.T) {
+
+	elector := &manualElector{changes: make(chan bool)}
+	scheduler := &leaderScheduler{ch: make(chan string, 10)}
+
+	ctx, err := glue.New(elector, scheduler)
+	require.NoError(t, err)
+
+	elector.changes <- true
+	require.Equal(t, "elected", waitEvent(t, scheduler.ch))
+	elector.changes <- true
+	elector.changes <- false
+	require.Equal(t, "revoked", waitEvent(t, scheduler.ch))
+	elector.changes <- true
+	require.Equal(t, "elected", waitEvent(t, scheduler.ch))
+
+	require.NoError(t, ctx.Close())
+	require.Equal(t, []string{"elected", "revoked", "elected", "revoked"}, scheduler.events)
+}
+
+func TestMultipleLeaderElectors(t *testing.T) {
+
+	_, err := glue.New(
+		&manualElector{changes: make(chan bool)},
+		&manualElector{changes: make(chan bool)},
+	)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "multiple leader electors")
+}
+
+type flakyElector struct {
+	runs int32
+}
+
+func (t *flakyElector) Run(ctx context.Context, notify func(elected bool)) error {
+	notify(true)
+	if atomic.AddInt32(&t.runs, 1) == 1 {
+		return errors.New("lease lost")
+	}
+	<-ctx.Done()
+	return nil
+}
+
+func TestLeaderElectorRestart(t *testing.T) {
+
+	backoff := glue.LeaderElectorRestartBackoff
+	glue.LeaderElectorRestartBackoff = time.Millisecond
+	defer func() { glue.LeaderElectorRestartBackoff = backoff }()
+
+	var buf bytes.Buffer
+	prev := glue.Warnings(log.New(&buf, "", 0))
+	defer glue.Warnings(prev)
+
+	elector := &flakyElector{}
+	scheduler := &leaderScheduler{ch: make(chan string, 10)}
+
+	ctx, err := glue.New(elector, scheduler)
+	require.NoError(t, err)
+
+	require.Equal(t, "elected", waitEvent(t, scheduler.ch))
+	require.Equal(t, "revoked", waitEvent(t, scheduler.ch))
+	require.Equal(t, "elected", waitEvent(t, scheduler.ch))
+
+	require.NoError(t, ctx.Close())
+	require.Equal(t, []string{"elected", "revoked", "elected", "revoked"}, scheduler.events)
+	require.Equal(t, int32(2), atomic.LoadInt32(&elector.runs))
+	require.Contains(t, buf.String(), "lease lost")
+}
+
+func waitEvent(t *testing.T, ch chan string) string {
+	select {
+	case e := <-ch:
+		return e
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "no leadership event")
+		return ""
+	}
+}
@@ -0,0 +1,187 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/pkg/errors"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+/**
+Option of the context that detects goroutines spawned by PostConstruct of beans and still running after the dispose phase,
+Close returns the error listing such beans with stacks of leaked goroutines. Aids tests and graceful shutdown verification.
+Goroutines are attributed to the bean by the time window of its PostConstruct and by the creator goroutine,
+so goroutines started concurrently by other code during the window could be reported too. The option is inherited by contexts created by Extend.
+
+Example:
+	ctx, err := glue.New(
+		glue.LeakDetector{Grace: time.Second},
+		&poller{},
+	)
+	...
+	err = ctx.Close() // reports goroutines of poller still running
+*/
+type LeakDetector struct {
+
+	/**
+	Time to wait for goroutines to finish after the dispose phase, 100ms by default
+	*/
+	Grace time.Duration
+}
+
+func (t LeakDetector) applyOption(ctx *context) {
+	if t.Grace <= 0 {
+		t.Grace = 100 * time.Millisecond
+	}
+	ctx.leakDetector = &t
+	ctx.goroutines = &goroutineOwners{owners: make(map[int64]*bean)}
+}
+
+/**
+Goroutines spawned by PostConstruct of beans
+*/
+type goroutineOwners struct {
+	mu     sync.Mutex
+	owners map[int64]*bean
+}
+
+/**
+Runs PostConstruct of the bean and records goroutines spawned during it
+*/
+func (t *goroutineOwners) track(b *bean, fn func() error) error {
+	before := runningGoroutines()
+	err := fn()
+	after := runningGoroutines()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for id := range after {
+		if _, ok := before[id]; !ok {
+			t.owners[id] = b
+		}
+	}
+	return err
+}
+
+/**
+Waits the grace period for spawned goroutines to finish and returns errors for beans with running ones
+*/
+func (t *goroutineOwners) check(grace time.Duration) []error {
+	deadline := time.Now().Add(grace)
+	for {
+		leaks := t.leaks()
+		if len(leaks) == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return leakErrors(leaks)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func (t *goroutineOwners) leaks() map[*bean][]*goroutineInfo {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	running := runningGoroutines()
+	leaks := make(map[*bean][]*goroutineInfo)
+	for id, g := range running {
+		if b := t.owner(id, running); b != nil {
+			leaks[b] = append(leaks[b], g)
+		}
+	}
+	return leaks
+}
+
+/**
+Finds owner of the goroutine directly or through running creators
+*/
+func (t *goroutineOwners) owner(id int64, running map[int64]*goroutineInfo) *bean {
+	for depth := 0; depth < 64; depth++ {
+		if b, ok := t.owners[id]; ok {
+			return b
+		}
+		g, ok := running[id]
+		if !ok || g.creator == 0 {
+			return nil
+		}
+		id = g.creator
+	}
+	return nil
+}
+
+func leakErrors(leaks map[*bean][]*goroutineInfo) []error {
+	beans := make([]*bean, 0, len(leaks))
+	for b := range leaks {
+		beans = append(beans, b)
+	}
+	sort.Slice(beans, func(i, j int) bool {
+		return beans[i].name < beans[j].name
+	})
+	var list []error
+	for _, b := range beans {
+		var stacks []string
+		for _, g := range leaks[b] {
+			stacks = append(stacks, g.stack)
+		}
+		list = append(list, errors.Errorf("bean '%s' with type '%v' leaked %d goroutines after destroy:\n%s", b.name, b.beanDef.classPtr, len(stacks), strings.Join(stacks, "\n")))
+	}
+	return list
+}
+
+type goroutineInfo struct {
+	id      int64
+	creator int64
+	stack   string
+}
+
+/**
+Returns running goroutines by id parsed from the dump of all stacks
+*/
+func runningGoroutines() map[int64]*goroutineInfo {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, len(buf)*2)
+	}
+	running := make(map[int64]*goroutineInfo)
+	for _, block := range bytes.Split(buf, []byte("\n\n")) {
+		stack := string(bytes.TrimSpace(block))
+		if !strings.HasPrefix(stack, "goroutine ") {
+			continue
+		}
+		header := strings.Fields(stack)
+		if len(header) < 2 {
+			continue
+		}
+		id, err := strconv.ParseInt(header[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		g := &goroutineInfo{id: id, stack: stack}
+		if i := strings.LastIndex(stack, "created by "); i != -1 {
+			line := stack[i:]
+			if j := strings.IndexByte(line, '\n'); j != -1 {
+				line = line[:j]
+			}
+			if k := strings.LastIndex(line, " in goroutine "); k != -1 {
+				fmt.Sscanf(line[k+len(" in goroutine "):], "%d", &g.creator)
+			}
+		}
+		running[id] = g
+	}
+	return running
+}
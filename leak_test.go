@@ -0,0 +1,75 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type leakyPoller struct {
+	stop chan struct{}
+}
+
+func (t *leakyPoller) PostConstruct() error {
+	t.stop = make(chan struct{})
+	go func() {
+		<-t.stop
+	}()
+	return nil
+}
+
+func (t *leakyPoller) Destroy() error {
+	// forgets to close t.stop
+	return nil
+}
+
+type tidyPoller struct {
+	stop chan struct{}
+	done chan struct{}
+}
+
+func (t *tidyPoller) PostConstruct() error {
+	t.stop = make(chan struct{})
+	t.done = make(chan struct{})
+	go func() {
+		defer close(t.done)
+		<-t.stop
+	}()
+	return nil
+}
+
+func (t *tidyPoller) Destroy() error {
+	close(t.stop)
+	<-t.done
+	return nil
+}
+
+func TestLeakDetector(t *testing.T) {
+
+	leaky := &leakyPoller{}
+	ctx, err := glue.New(
+		glue.LeakDetector{Grace: 50 * time.Millisecond},
+		leaky,
+		&tidyPoller{},
+	)
+	require.NoError(t, err)
+
+	err = ctx.Close()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "bean '*glue_test.leakyPoller' with type '*glue_test.leakyPoller' leaked 1 goroutines after destroy")
+	require.NotContains(t, err.Error(), "tidyPoller")
+	close(leaky.stop)
+
+	ctx, err = glue.New(
+		glue.LeakDetector{},
+		&tidyPoller{},
+	)
+	require.NoError(t, err)
+	require.NoError(t, ctx.Close())
+}
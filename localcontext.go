@@ -0,0 +1,79 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"github.com/pkg/errors"
+	"reflect"
+	"strings"
+)
+
+/**
+View of the context restricted to its own beans, injected by `inject:"level=1"` in to glue.Context fields.
+Bean, Lookup, LookupPattern, EachBean, Resource, Factories and Satisfaction never reach parent contexts and the parent is hidden,
+so Promote that moves the bean to the parent is refused.
+Inject, InjectAll, Extend and Properties are not restricted: runtime injections resolve fields by their own levels,
+extended contexts have the full context as parent and properties already include the inherited ones.
+*/
+type localContext struct {
+	*context
+}
+
+func (t *localContext) Parent() (Context, bool) {
+	return nil, false
+}
+
+func (t *localContext) Promote(b Bean) error {
+	return errors.Errorf("can not promote bean '%v' through local view of the context, parent is hidden", b)
+}
+
+func (t *localContext) Bean(typ reflect.Type, level int) []Bean {
+	return t.context.Bean(typ, LocalLevel)
+}
+
+func (t *localContext) Lookup(name string, level int) []Bean {
+	return t.context.Lookup(name, LocalLevel)
+}
+
+func (t *localContext) LookupPattern(pattern string, level int) []Bean {
+	return t.context.LookupPattern(pattern, LocalLevel)
+}
+
+func (t *localContext) EachBean(level int, fn func(Bean) bool) {
+	t.context.EachBean(LocalLevel, fn)
+}
+
+func (t *localContext) Factories(objType reflect.Type) []FactoryBean {
+	return t.context.factories(objType, true)
+}
+
+func (t *localContext) Satisfaction(ifaces ...reflect.Type) SatisfactionReport {
+	return t.context.satisfaction(ifaces, true)
+}
+
+func (t *localContext) Resource(path string) (Resource, bool) {
+	idx := strings.IndexByte(path, ':')
+	if idx == -1 {
+		return nil, false
+	}
+	return t.registry.findResource(path[:idx], path[idx+1:])
+}
+
+func (t *localContext) String() string {
+	return "Local" + t.context.String()
+}
+
+/**
+Returns the value to inject in to the field, context bean injected with level 1 becomes local view
+*/
+func (t *injectionDef) contextView(impl *bean) reflect.Value {
+	if t.level == LocalLevel && t.fieldType == ContextClass {
+		if ctx, ok := impl.obj.(*context); ok {
+			return reflect.ValueOf(&localContext{ctx})
+		}
+	}
+	return impl.valuePtr
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"strings"
+	"sync"
+)
+
+/**
+Key functions by name for 'keyFunc' attribute of 'inject' tag
+*/
+var keyFuncs = struct {
+	sync.RWMutex
+	byName map[string]func(string) string
+}{
+	byName: make(map[string]func(string) string),
+}
+
+/**
+Functions for 'keyCase' attribute of 'inject' tag
+*/
+var keyCases = map[string]func(string) string{
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"short": shortName,
+}
+
+/**
+Registers function by the name to transform keys of map injection in 'keyFunc' attribute of 'inject' tag.
+
+Example:
+	glue.RegisterKeyFunc("route", func(name string) string {
+		return strings.TrimSuffix(name, "Handler")
+	})
+
+	type router struct {
+		Handlers map[string]Handler `inject:"keyFunc=route"`
+	}
+*/
+func RegisterKeyFunc(name string, fn func(string) string) {
+	keyFuncs.Lock()
+	defer keyFuncs.Unlock()
+	keyFuncs.byName[name] = fn
+}
+
+func lookupKeyFunc(name string) (func(string) string, bool) {
+	keyFuncs.RLock()
+	defer keyFuncs.RUnlock()
+	fn, ok := keyFuncs.byName[name]
+	return fn, ok
+}
+
+/**
+Trims pointer mark and package prefix from the bean name, '*app.handler' becomes 'handler'.
+Type arguments of generic types are trimmed as well, '*app.cache[github.com/acme/app.user]' becomes 'cache[user]'
+*/
+func shortName(name string) string {
+	name = strings.TrimPrefix(name, "*")
+	if i := strings.IndexByte(name, '['); i > 0 && strings.HasSuffix(name, "]") {
+		args := splitTypeArgs(name[i+1 : len(name)-1])
+		for j, arg := range args {
+			args[j] = shortName(arg)
+		}
+		return shortName(name[:i]) + "[" + strings.Join(args, ",") + "]"
+	}
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+/**
+Splits type arguments of the generic type by commas outside of nested brackets
+*/
+func splitTypeArgs(args string) []string {
+	var list []string
+	depth, start := 0, 0
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				list = append(list, args[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(list, args[start:])
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"github.com/pkg/errors"
+	"sync"
+)
+
+var namedContexts struct {
+	sync.RWMutex
+	contexts map[string]Context
+}
+
+/**
+Registers the context in the global registry under the name, so frameworks could find it by glue.Find from callbacks.
+Nothing is registered by default, the application opts in explicitly.
+The name is released on Close of the context.
+
+Example:
+	ctx, err := glue.New(...)
+	if err := glue.Named("app", ctx); err != nil {
+		return err
+	}
+*/
+func Named(name string, ctx Context) error {
+	if ctx == nil {
+		return errors.Errorf("named context '%s' is nil", name)
+	}
+	namedContexts.Lock()
+	defer namedContexts.Unlock()
+	if namedContexts.contexts == nil {
+		namedContexts.contexts = make(map[string]Context)
+	}
+	if prev, ok := namedContexts.contexts[name]; ok && prev != ctx {
+		return errors.Errorf("context name '%s' is already registered", name)
+	}
+	namedContexts.contexts[name] = ctx
+	return nil
+}
+
+/**
+Returns the context registered by glue.Named.
+*/
+func Find(name string) (Context, bool) {
+	namedContexts.RLock()
+	defer namedContexts.RUnlock()
+	ctx, ok := namedContexts.contexts[name]
+	return ctx, ok
+}
+
+/**
+Releases all names registered for the context.
+*/
+func unregisterNamed(ctx Context) {
+	namedContexts.Lock()
+	defer namedContexts.Unlock()
+	for name, c := range namedContexts.contexts {
+		if c == ctx {
+			delete(namedContexts.contexts, name)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestNamedContext(t *testing.T) {
+
+	_, ok := glue.Find("test.app")
+	require.False(t, ok)
+
+	ctx, err := glue.New()
+	require.NoError(t, err)
+
+	require.NoError(t, glue.Named("test.app", ctx))
+	require.NoError(t, glue.Named("test.app", ctx))
+
+	found, ok := glue.Find("test.app")
+	require.True(t, ok)
+	require.Equal(t, ctx, found)
+
+	other, err := glue.New()
+	require.NoError(t, err)
+	defer other.Close()
+
+	require.Error(t, glue.Named("test.app", other))
+
+	ctx.Close()
+
+	_, ok = glue.Find("test.app")
+	require.False(t, ok)
+
+	require.NoError(t, glue.Named("test.app", other))
+}
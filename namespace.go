@@ -0,0 +1,99 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"reflect"
+	"strings"
+)
+
+/**
+Scanner of the bean bundle with own namespace of resources. ResourceSources of the bundle are registered under the name of the scanner
+and PropertySources of the bundle pointing to them are rewritten, so resources are available as '<scannerName>:path'
+and independently developed bundles using the same ResourceSource name like "resources" do not clash.
+*/
+var NamedScannerClass = reflect.TypeOf((*NamedScanner)(nil)).Elem()
+
+type NamedScanner interface {
+	Scanner
+
+	/**
+	Returns namespace of resources of the bundle
+	*/
+	ScannerName() string
+}
+
+/**
+Declares namespace of resources of the scanner that does not implement NamedScanner.
+
+Example:
+	glue.New(
+		glue.Namespace("billing", billing.Scanner),
+	)
+
+	res, ok := ctx.Resource("billing:application.properties")
+*/
+func Namespace(name string, scanner Scanner) Scanner {
+	return &namedScanner{Scanner: scanner, name: name}
+}
+
+type namedScanner struct {
+	Scanner
+	name string
+}
+
+func (t *namedScanner) ScannerName() string {
+	return t.name
+}
+
+/**
+Names of ResourceSources declared in the list of the bundle
+*/
+func resourceSourceNames(list []interface{}, names map[string]bool) {
+	for _, item := range list {
+		switch obj := item.(type) {
+		case ResourceSource:
+			names[obj.Name] = true
+		case *ResourceSource:
+			names[obj.Name] = true
+		case []interface{}:
+			resourceSourceNames(obj, names)
+		}
+	}
+}
+
+/**
+Returns callback registering resource and property sources of the bundle under the namespace
+*/
+func namespaced(namespace string, beans []interface{}, cb func(pos string, obj interface{}) error) func(pos string, obj interface{}) error {
+	names := make(map[string]bool)
+	resourceSourceNames(beans, names)
+	rewrite := func(path string) string {
+		if i := strings.IndexByte(path, ':'); i >= 0 && names[path[:i]] {
+			return namespace + path[i:]
+		}
+		return path
+	}
+	return func(pos string, obj interface{}) error {
+		switch instance := obj.(type) {
+		case ResourceSource:
+			instance.Name = namespace
+			obj = instance
+		case *ResourceSource:
+			source := *instance
+			source.Name = namespace
+			obj = &source
+		case PropertySource:
+			instance.Path = rewrite(instance.Path)
+			obj = instance
+		case *PropertySource:
+			source := *instance
+			source.Path = rewrite(source.Path)
+			obj = &source
+		}
+		return cb(pos, obj)
+	}
+}
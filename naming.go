@@ -0,0 +1,72 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"reflect"
+	"unicode"
+	"unicode/utf8"
+)
+
+/**
+Builds the bean name from the type, used for Lookup and keys of map injection.
+Beans implementing NamedBean and factories with ObjectName keep their own names.
+*/
+type NamingStrategy func(classPtr reflect.Type) string
+
+/**
+Default naming, '*app.handler'
+*/
+func TypeNaming(classPtr reflect.Type) string {
+	return classPtr.String()
+}
+
+/**
+Type name without pointer mark and package, '*app.handler' becomes 'handler'
+*/
+func ShortNaming(classPtr reflect.Type) string {
+	return shortName(classPtr.String())
+}
+
+/**
+Short type name starting with lower case, '*app.UserService' becomes 'userService'
+*/
+func CamelCaseNaming(classPtr reflect.Type) string {
+	name := ShortNaming(classPtr)
+	r, n := utf8.DecodeRuneInString(name)
+	if n == 0 {
+		return name
+	}
+	return string(unicode.ToLower(r)) + name[n:]
+}
+
+type namingOption struct {
+	strategy NamingStrategy
+}
+
+/**
+Option of the context to override the default naming of beans, place it first in the scan list.
+
+Example:
+	glue.New(
+		glue.Naming(glue.CamelCaseNaming),
+		&userService{},
+	)
+*/
+func Naming(strategy NamingStrategy) interface{} {
+	return namingOption{strategy: strategy}
+}
+
+func (t namingOption) applyOption(ctx *context) {
+	ctx.naming = t.strategy
+}
+
+func (t *context) beanName(classPtr reflect.Type) string {
+	if t.naming != nil {
+		return t.naming(classPtr)
+	}
+	return classPtr.String()
+}
@@ -71,3 +71,48 @@ func TestOptionalBeanByInterface(t *testing.T) {
 
 	require.Nil(t, b[0].Object().(*beanBServiceImpl).BeanAService)
 }
+
+type beanWhen struct {
+	BeanA   *beanA       `inject:"optional,when=feature.a"`
+	Service FirstService `inject:"when=feature.service"`
+}
+
+func TestOptionalWhen(t *testing.T) {
+
+	for _, enabled := range []string{"true", "false"} {
+
+		b := &beanWhen{}
+		ctx, err := glue.New(
+			&glue.PropertySource{Map: map[string]interface{}{
+				"feature.a":       enabled,
+				"feature.service": enabled,
+			}},
+			&beanA{},
+			&firstServiceImpl{testing: t},
+			b,
+		)
+		require.NoError(t, err)
+
+		if enabled == "true" {
+			require.NotNil(t, b.BeanA)
+			require.NotNil(t, b.Service)
+		} else {
+			require.Nil(t, b.BeanA)
+			require.Nil(t, b.Service)
+
+			disabled := 0
+			for _, record := range ctx.AuditLog() {
+				if record.Disabled {
+					disabled++
+				}
+			}
+			require.Equal(t, 2, disabled)
+		}
+
+		runtime := &beanWhen{}
+		require.NoError(t, ctx.Inject(runtime))
+		require.Equal(t, enabled == "true", runtime.BeanA != nil)
+
+		ctx.Close()
+	}
+}
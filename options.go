@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import "fmt"
+
+/**
+Option of the context placed in the scan list, applies to beans scanned after it, so usually it goes first.
+Options are inherited by contexts created by Extend.
+*/
+type contextOption interface {
+	applyOption(ctx *context)
+}
+
+/**
+Propagates panics during scan and construction of beans with the original stack trace instead of converting them to errors.
+Useful in development, since the formatted error hides the origin of the panic.
+
+Example:
+	glue.New(
+		glue.DisableRecover{},
+		&beanA{},
+	)
+*/
+type DisableRecover struct {
+}
+
+func (DisableRecover) applyOption(ctx *context) {
+	ctx.disableRecover = true
+}
+
+/**
+Role of the child context, applied by glue.Child on creation of the context
+*/
+type childRole string
+
+func (t childRole) applyOption(ctx *context) {
+	ctx.role = string(t)
+}
+
+/**
+Inheritance of properties of the parent context by the child context, applied by Extend on creation of the child.
+The option is not inherited by contexts created by Extend of the child.
+
+Example:
+	tenant, err := ctx.Extend(
+		glue.IsolateParentProperties,
+		glue.PropertySource{Path: "resources:tenant.properties"},
+		&tenantService{},
+	)
+*/
+type PropertyInheritance int
+
+const (
+	/**
+	Properties of the child shadow properties of the parent, default
+	*/
+	ShadowParentProperties PropertyInheritance = iota
+
+	/**
+	Properties of the parent win, the child only adds missing ones
+	*/
+	MergeParentProperties
+
+	/**
+	The child does not see properties, resolvers and interceptors of the parent, for example for tenant isolation
+	*/
+	IsolateParentProperties
+)
+
+func (t PropertyInheritance) applyOption(ctx *context) {
+	ctx.propertyInheritance = t
+}
+
+func (t PropertyInheritance) String() string {
+	switch t {
+	case ShadowParentProperties:
+		return "ShadowParentProperties"
+	case MergeParentProperties:
+		return "MergeParentProperties"
+	case IsolateParentProperties:
+		return "IsolateParentProperties"
+	default:
+		return fmt.Sprintf("PropertyInheritance(%d)", int(t))
+	}
+}
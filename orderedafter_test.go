@@ -0,0 +1,101 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"reflect"
+	"testing"
+)
+
+var StageClass = reflect.TypeOf((*Stage)(nil)).Elem()
+
+type Stage interface {
+	Stage() string
+}
+
+var authStageClass = reflect.TypeOf((*authStage)(nil))
+
+type authStage struct {
+	constructed *[]string
+}
+
+func (t *authStage) Stage() string {
+	return "auth"
+}
+
+func (t *authStage) PostConstruct() error {
+	*t.constructed = append(*t.constructed, t.Stage())
+	return nil
+}
+
+var logStageClass = reflect.TypeOf((*logStage)(nil))
+
+type logStage struct {
+	constructed *[]string
+}
+
+func (t *logStage) Stage() string {
+	return "log"
+}
+
+func (t *logStage) BeanBefore() []reflect.Type {
+	return []reflect.Type{authStageClass}
+}
+
+func (t *logStage) PostConstruct() error {
+	*t.constructed = append(*t.constructed, t.Stage())
+	return nil
+}
+
+type handlerStage struct {
+	constructed *[]string
+}
+
+func (t *handlerStage) Stage() string {
+	return "handler"
+}
+
+func (t *handlerStage) BeanAfter() []reflect.Type {
+	return []reflect.Type{authStageClass, logStageClass}
+}
+
+func (t *handlerStage) PostConstruct() error {
+	*t.constructed = append(*t.constructed, t.Stage())
+	return nil
+}
+
+type stageHolder struct {
+	Stages []Stage `inject`
+}
+
+func TestOrderedAfterBefore(t *testing.T) {
+
+	var constructed []string
+	holder := &stageHolder{}
+
+	ctx, err := glue.New(
+		&handlerStage{constructed: &constructed},
+		&authStage{constructed: &constructed},
+		&logStage{constructed: &constructed},
+		holder,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.Equal(t, 3, len(holder.Stages))
+	require.Equal(t, "log", holder.Stages[0].Stage())
+	require.Equal(t, "auth", holder.Stages[1].Stage())
+	require.Equal(t, "handler", holder.Stages[2].Stage())
+
+	require.Equal(t, []string{"log", "auth", "handler"}, constructed)
+
+	list := ctx.Bean(StageClass, glue.DefaultLevel)
+	require.Equal(t, 3, len(list))
+	require.Equal(t, "log", list[0].Object().(Stage).Stage())
+	require.Equal(t, "handler", list[2].Object().(Stage).Stage())
+}
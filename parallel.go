@@ -0,0 +1,197 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"runtime"
+)
+
+/**
+Option of the context that constructs independent beans concurrently during creation of the context and destroys them concurrently on close.
+Beans are sorted topologically by the dependency graph, PostConstruct of the bean runs only after all its dependencies got initialized
+and Destroy runs only after all beans depending on it got destroyed,
+so beans with slow PostConstruct or Destroy (connections, warm-up, flush) do not wait for each other. Lazy fields are not dependencies,
+if the graph still has a cycle the context falls back to the sequential construction. The sequential construction is used with LeakDetector as well,
+since goroutines are attributed to beans by the time window of PostConstruct.
+The option is not inherited by contexts created by Extend.
+
+Example:
+	ctx, err := glue.New(
+		glue.Parallel{Workers: 4},
+		&storage{},
+		&search{},
+		&service{},
+	)
+*/
+type Parallel struct {
+
+	/**
+	Number of concurrent constructions of beans, runtime.NumCPU() by default
+	*/
+	Workers int
+}
+
+func (t Parallel) applyOption(ctx *context) {
+	if t.Workers <= 0 {
+		t.Workers = runtime.NumCPU()
+	}
+	ctx.parallel = &t
+}
+
+/**
+Dependency graph of beans not initialized yet
+*/
+type constructionGraph struct {
+	nodes      []*bean
+	pending    map[*bean]int
+	dependents map[*bean][]*bean
+}
+
+/**
+Collects beans of the list and their dependencies not initialized yet in the order of sequential construction
+*/
+func newConstructionGraph(list []*bean) *constructionGraph {
+	g := &constructionGraph{
+		pending:    make(map[*bean]int),
+		dependents: make(map[*bean][]*bean),
+	}
+	for _, b := range list {
+		g.visit(b)
+	}
+	return g
+}
+
+func (t *constructionGraph) visit(b *bean) {
+	if b.Lifecycle() == BeanInitialized {
+		return
+	}
+	if _, ok := t.pending[b]; ok {
+		return
+	}
+	t.pending[b] = 0
+	seen := make(map[*bean]bool)
+	for _, dep := range beanDependencies(b) {
+		if seen[dep] || dep == b {
+			continue
+		}
+		seen[dep] = true
+		t.visit(dep)
+		if dep.Lifecycle() != BeanInitialized {
+			t.pending[b]++
+			t.dependents[dep] = append(t.dependents[dep], b)
+		}
+	}
+	t.nodes = append(t.nodes, b)
+}
+
+/**
+Returns beans constructed by constructBean before the bean itself
+*/
+func beanDependencies(b *bean) []*bean {
+	var list []*bean
+	for _, factoryDep := range b.factoryDependencies {
+		list = append(list, factoryDep.factory.bean)
+	}
+	list = append(list, b.dependencies...)
+	if b.beenFactory != nil && b.obj == nil {
+		list = append(list, b.beenFactory.bean)
+	}
+	return list
+}
+
+/**
+Checks that every bean of the graph could be constructed after its dependencies
+*/
+func (t *constructionGraph) acyclic() bool {
+	pending := make(map[*bean]int, len(t.pending))
+	var queue []*bean
+	for _, b := range t.nodes {
+		pending[b] = t.pending[b]
+		if pending[b] == 0 {
+			queue = append(queue, b)
+		}
+	}
+	sorted := 0
+	for len(queue) > 0 {
+		b := queue[0]
+		queue = queue[1:]
+		sorted++
+		for _, dependent := range t.dependents[b] {
+			pending[dependent]--
+			if pending[dependent] == 0 {
+				queue = append(queue, dependent)
+			}
+		}
+	}
+	return sorted == len(t.nodes)
+}
+
+type constructionResult struct {
+	bean *bean
+	err  error
+}
+
+/**
+Constructs beans of the list by workers, the bean is scheduled after all its dependencies got initialized.
+Stops scheduling on the first error and waits for running constructions.
+*/
+func (t *context) constructParallel(list []*bean, workers int) error {
+
+	g := newConstructionGraph(list)
+	if !g.acyclic() {
+		if t.logger() != nil {
+			t.logger().Printf("Parallel construction fallback to sequential, dependency graph has a cycle\n")
+		}
+		return t.constructBeanList(list, nil)
+	}
+
+	ready := make(chan *bean, len(g.nodes))
+	results := make(chan constructionResult, len(g.nodes))
+	defer close(ready)
+
+	if workers > len(g.nodes) {
+		workers = len(g.nodes)
+	}
+	for i := 0; i < workers; i++ {
+		go func() {
+			for b := range ready {
+				results <- constructionResult{bean: b, err: t.constructBean(b, nil)}
+			}
+		}()
+	}
+
+	running := 0
+	for _, b := range g.nodes {
+		if g.pending[b] == 0 {
+			ready <- b
+			running++
+		}
+	}
+
+	var err error
+	for running > 0 {
+		r := <-results
+		running--
+		if r.err != nil {
+			if err == nil {
+				err = r.err
+			}
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		for _, dependent := range g.dependents[r.bean] {
+			g.pending[dependent]--
+			if g.pending[dependent] == 0 {
+				ready <- dependent
+				running++
+			}
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,111 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"errors"
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+/**
+Barrier released when both slow beans are in PostConstruct at the same time
+*/
+type parallelBarrier struct {
+	wg sync.WaitGroup
+}
+
+type parallelStorage struct {
+	Barrier *parallelBarrier `inject`
+	ready   bool
+}
+
+func (t *parallelStorage) PostConstruct() error {
+	return awaitBarrier(t.Barrier, &t.ready)
+}
+
+type parallelSearch struct {
+	Barrier *parallelBarrier `inject`
+	ready   bool
+}
+
+func (t *parallelSearch) PostConstruct() error {
+	return awaitBarrier(t.Barrier, &t.ready)
+}
+
+func awaitBarrier(b *parallelBarrier, ready *bool) error {
+	b.wg.Done()
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		*ready = true
+		return nil
+	case <-time.After(5 * time.Second):
+		return errors.New("constructed sequentially")
+	}
+}
+
+type parallelService struct {
+	Storage *parallelStorage `inject`
+	Search  *parallelSearch  `inject`
+	ready   bool
+}
+
+func (t *parallelService) PostConstruct() error {
+	if !t.Storage.ready || !t.Search.ready {
+		return errors.New("dependencies are not initialized")
+	}
+	t.ready = true
+	return nil
+}
+
+type parallelBroken struct {
+	Service *parallelService `inject`
+}
+
+func (t *parallelBroken) PostConstruct() error {
+	return errors.New("broken")
+}
+
+func TestParallel(t *testing.T) {
+
+	barrier := &parallelBarrier{}
+	barrier.wg.Add(2)
+
+	service := &parallelService{}
+	ctx, err := glue.New(
+		glue.Parallel{Workers: 2},
+		service,
+		&parallelStorage{},
+		&parallelSearch{},
+		barrier,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.True(t, service.ready)
+
+	barrier = &parallelBarrier{}
+	barrier.wg.Add(2)
+
+	_, err = glue.New(
+		glue.Parallel{Workers: 2},
+		&parallelBroken{},
+		&parallelService{},
+		&parallelStorage{},
+		&parallelSearch{},
+		barrier,
+	)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "broken")
+}
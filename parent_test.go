@@ -6,9 +6,11 @@
 package glue_test
 
 import (
+	"errors"
 	"github.com/codeallergy/glue"
 	"github.com/stretchr/testify/require"
 	"reflect"
+	"sync"
 	"testing"
 )
 
@@ -309,4 +311,129 @@ func TestChildren(t *testing.T) {
 	err = child.Close()
 	require.NoError(t, err)
 
-}
\ No newline at end of file
+}
+
+func TestParentSymbolicLevel(t *testing.T) {
+
+	parent, err := glue.New(
+		&implComponent{value: "fromParent", order: 1},
+	)
+	require.NoError(t, err)
+	defer parent.Close()
+
+	holder := &struct {
+		Local   []Component `inject:"optional,level=local"`
+		Parents []Component `inject:"optional,level=parents"`
+		All     []Component `inject:"optional,level=all"`
+	}{}
+
+	child, err := parent.Extend(
+		&implComponent{value: "fromChild", order: 2},
+		holder,
+	)
+	require.NoError(t, err)
+	defer child.Close()
+
+	require.Equal(t, 1, len(holder.Local))
+	require.Equal(t, 2, len(holder.Parents))
+	require.Equal(t, 2, len(holder.All))
+
+	for _, level := range []string{"everywhere", "-2"} {
+		_, err = glue.New(reflect.New(reflect.StructOf([]reflect.StructField{
+			{
+				Name: "Components",
+				Type: reflect.TypeOf([]Component{}),
+				Tag:  reflect.StructTag(`inject:"optional,level=` + level + `"`),
+			},
+		})).Interface())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "level")
+	}
+}
+
+func TestParentConcurrentExtend(t *testing.T) {
+
+	parent, err := glue.New(
+		&coreBean{},
+		&implComponent{value: "fromParent", order: 1},
+	)
+	require.NoError(t, err)
+	defer parent.Close()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 64)
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			child, err := parent.Extend(
+				&implElement{value: "child", order: i},
+				&serviceBean{testing: t},
+			)
+			if err != nil {
+				errs <- err
+				return
+			}
+			defer child.Close()
+			if len(child.Bean(ComponentClass, glue.AllLevel)) != 1 {
+				errs <- errors.New("component is not visible from child")
+			}
+			if len(parent.Bean(coreBeanClass, glue.DefaultLevel)) != 1 {
+				errs <- errors.New("core bean is not visible from parent")
+			}
+			if len(parent.Lookup("*glue_test.coreBean", glue.DefaultLevel)) != 1 {
+				errs <- errors.New("core bean is not found by name")
+			}
+			holder := &struct {
+				Core *coreBean `inject`
+			}{}
+			if err := parent.Inject(holder); err != nil {
+				errs <- err
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+}
+
+type localContextHolder struct {
+	Local glue.Context `inject:"level=1"`
+	Full  glue.Context `inject`
+}
+
+func TestParentLocalContextView(t *testing.T) {
+
+	parent, err := glue.New(
+		&coreBean{},
+	)
+	require.NoError(t, err)
+	defer parent.Close()
+
+	holder := &localContextHolder{}
+	child, err := parent.Extend(holder)
+	require.NoError(t, err)
+	defer child.Close()
+
+	require.Equal(t, 1, len(holder.Full.Bean(coreBeanClass, glue.DefaultLevel)))
+	require.Equal(t, 0, len(holder.Local.Bean(coreBeanClass, glue.DefaultLevel)))
+	require.Equal(t, 0, len(holder.Local.Bean(coreBeanClass, glue.AllLevel)))
+	require.Equal(t, 0, len(holder.Local.Lookup("*glue_test.coreBean", glue.AllLevel)))
+	require.Equal(t, 1, len(holder.Full.Satisfaction(coreBeanClass)[0].Beans))
+	require.Equal(t, 0, len(holder.Local.Satisfaction(coreBeanClass)[0].Beans))
+
+	_, ok := holder.Local.Parent()
+	require.False(t, ok)
+	_, ok = holder.Full.Parent()
+	require.True(t, ok)
+
+	list := holder.Full.Bean(reflect.TypeOf(holder), glue.DefaultLevel)
+	require.Equal(t, 1, len(list))
+	err = holder.Local.Promote(list[0])
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "local view")
+	require.Equal(t, 1, len(holder.Full.Bean(reflect.TypeOf(holder), glue.LocalLevel)))
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"github.com/pkg/errors"
+	"plugin"
+)
+
+/**
+Name of the symbol that plugin must export to provide beans
+*/
+const PluginBeansSymbol = "Beans"
+
+/**
+Go plugin with beans loaded on scan
+*/
+type pluginGroup struct {
+	path string
+}
+
+/**
+Opens Go plugin on creation of the context and registers beans returned by exported function 'Beans() []interface{}'.
+Plugin has to be built with '-buildmode=plugin' by the same toolchain and versions of dependencies as application.
+Usually plugins are loaded in to the child context to keep application beans separated from plugin beans.
+
+Example:
+	child, err := ctx.Extend(
+		glue.LoadPlugin("plugins/auth.so"),
+	)
+*/
+func LoadPlugin(path string) interface{} {
+	return &pluginGroup{path: path}
+}
+
+func (t *pluginGroup) Beans() ([]interface{}, error) {
+	p, err := plugin.Open(t.path)
+	if err != nil {
+		return nil, errors.Errorf("open plugin '%s', %v", t.path, err)
+	}
+	sym, err := p.Lookup(PluginBeansSymbol)
+	if err != nil {
+		return nil, errors.Errorf("plugin '%s' does not export '%s', %v", t.path, PluginBeansSymbol, err)
+	}
+	switch fn := sym.(type) {
+	case func() []interface{}:
+		return fn(), nil
+	case *func() []interface{}:
+		return (*fn)(), nil
+	default:
+		return nil, errors.Errorf("plugin '%s' exports '%s' of type '%T', expected 'func() []interface{}'", t.path, PluginBeansSymbol, sym)
+	}
+}
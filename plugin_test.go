@@ -0,0 +1,28 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadPluginNotFound(t *testing.T) {
+
+	parent, err := glue.New()
+	require.NoError(t, err)
+	defer parent.Close()
+
+	path := filepath.Join(t.TempDir(), "missing.so")
+
+	_, err = parent.Extend(
+		glue.LoadPlugin(path),
+	)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), path)
+}
@@ -0,0 +1,324 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"github.com/pkg/errors"
+	"reflect"
+	"sync"
+)
+
+/**
+This interface used by factory beans which objects are pooled by the context instead of created on every injection.
+Context creates min objects on startup, keeps at most max objects checked out at the same time (unlimited if zero)
+and destroys all created objects on close.
+*/
+var PoolingFactoryBeanClass = reflect.TypeOf((*PoolingFactoryBean)(nil)).Elem()
+
+type PoolingFactoryBean interface {
+	FactoryBean
+
+	/**
+	Returns min and max size of the pool
+	*/
+	Pooled() (min, max int)
+}
+
+/**
+Pool is an injectable handle to check out objects produced by PoolingFactoryBean and return them back.
+Same as WeakRef the handle does not create dependency on the objects.
+
+Example:
+	type service struct {
+		Encoders *glue.Pool[*encoder] `inject`
+	}
+
+	enc, err := t.Encoders.Get()
+	if err != nil {
+		return err
+	}
+	defer t.Encoders.Put(enc)
+*/
+type Pool[T any] struct {
+	ctx *context
+	def *injectionDef
+}
+
+func (t *Pool[T]) weakType() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func (t *Pool[T]) bindWeak(ctx *context, def *injectionDef) {
+	t.ctx = ctx
+	t.def = def
+}
+
+/**
+Checks out the object from the pool, creates a new one if there are no idle objects, blocks if max objects are checked out
+*/
+func (t *Pool[T]) Get() (T, error) {
+	var empty T
+	pool, err := t.pool()
+	if err != nil {
+		return empty, err
+	}
+	obj, err := pool.get()
+	if err != nil {
+		return empty, err
+	}
+	if v, ok := obj.(T); ok {
+		return v, nil
+	}
+	pool.put(obj)
+	return empty, errors.Errorf("pool of '%v' produced incompatible object '%T'", t.weakType(), obj)
+}
+
+/**
+Returns the object back to the pool, objects not checked out from the pool or returned twice are ignored with the warning
+*/
+func (t *Pool[T]) Put(obj T) {
+	if pool, err := t.pool(); err == nil {
+		pool.put(obj)
+	}
+}
+
+func (t *Pool[T]) pool() (*objectPool, error) {
+	if t.ctx == nil {
+		return nil, errors.Errorf("pool of '%v' is not injected", t.weakType())
+	}
+	return t.ctx.resolvePool(t.def)
+}
+
+/**
+Objects produced by pooling factory bean
+*/
+type objectPool struct {
+	ctx      *context
+	factory  *factory
+	min, max int
+
+	/**
+	Limits number of checked out objects, nil if unlimited
+	*/
+	sem chan struct{}
+
+	/**
+	Closed on close of the pool to unblock waiting Get calls
+	*/
+	done chan struct{}
+
+	mu     sync.Mutex
+	idle   []interface{}
+	all    []interface{}
+	closed bool
+
+	/**
+	Reason of close of the pool, objects created after close are destroyed with it
+	*/
+	reason CloseReason
+
+	/**
+	Number of checked out objects by identity
+	*/
+	busy map[interface{}]int
+}
+
+func newObjectPool(ctx *context, f *factory, min, max int) *objectPool {
+	pool := &objectPool{ctx: ctx, factory: f, min: min, max: max, done: make(chan struct{}), busy: make(map[interface{}]int)}
+	if max > 0 {
+		pool.sem = make(chan struct{}, max)
+	}
+	return pool
+}
+
+func (t *objectPool) create() (interface{}, error) {
+	if t.factory.bean.Lifecycle() != BeanInitialized {
+		if err := t.ctx.constructOnDemand(t.factory.bean); err != nil {
+			return nil, err
+		}
+	}
+	obj, err := t.factory.factoryBean.Object()
+	if err != nil {
+		return nil, errors.Errorf("pooling factory bean '%v' failed to create bean '%v', %v", t.factory.factoryClassPtr, t.factory.factoryBean.ObjectType(), err)
+	}
+	t.mu.Lock()
+	if t.closed {
+		reason := t.reason
+		t.mu.Unlock()
+		if err := destroyObject(obj, reason); err != nil {
+			warnf("Object '%T' created after close of pool of '%v' failed to destroy, %v\n", obj, t.factory.factoryBean.ObjectType(), err)
+		}
+		return nil, t.closedErr()
+	}
+	t.all = append(t.all, obj)
+	t.mu.Unlock()
+	return obj, nil
+}
+
+/**
+Creates min idle objects on startup
+*/
+func (t *objectPool) fill() error {
+	for i := 0; i < t.min; i++ {
+		obj, err := t.create()
+		if err != nil {
+			return err
+		}
+		t.mu.Lock()
+		t.idle = append(t.idle, obj)
+		t.mu.Unlock()
+	}
+	return nil
+}
+
+func (t *objectPool) get() (interface{}, error) {
+	if t.sem != nil {
+		select {
+		case t.sem <- struct{}{}:
+		case <-t.done:
+			return nil, t.closedErr()
+		}
+	}
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		t.release()
+		return nil, t.closedErr()
+	}
+	if n := len(t.idle); n > 0 {
+		obj := t.idle[n-1]
+		t.idle = t.idle[:n-1]
+		t.busy[objectKey(obj)]++
+		t.mu.Unlock()
+		return obj, nil
+	}
+	t.mu.Unlock()
+	obj, err := t.create()
+	if err != nil {
+		t.release()
+		return nil, err
+	}
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		t.release()
+		return nil, t.closedErr()
+	}
+	t.busy[objectKey(obj)]++
+	t.mu.Unlock()
+	return obj, nil
+}
+
+func (t *objectPool) closedErr() error {
+	return errors.Errorf("pool of '%v' is closed", t.factory.factoryBean.ObjectType())
+}
+
+func (t *objectPool) put(obj interface{}) {
+	t.mu.Lock()
+	key := objectKey(obj)
+	if t.busy[key] == 0 {
+		closed := t.closed
+		t.mu.Unlock()
+		if !closed {
+			warnf("Put of object '%T' not checked out from pool of '%v' is ignored\n", obj, t.factory.factoryBean.ObjectType())
+		}
+		return
+	}
+	if t.busy[key]--; t.busy[key] == 0 {
+		delete(t.busy, key)
+	}
+	t.idle = append(t.idle, obj)
+	t.mu.Unlock()
+	t.release()
+}
+
+/**
+Returns the key of the object identity, maps, slices and functions are identified by pointer,
+other values not comparable by value share the key of their type
+*/
+func objectKey(obj interface{}) interface{} {
+	v := reflect.ValueOf(obj)
+	if !v.IsValid() || v.Type().Comparable() {
+		return obj
+	}
+	switch v.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Func:
+		return v.Pointer()
+	default:
+		return v.Type()
+	}
+}
+
+func (t *objectPool) release() {
+	if t.sem != nil {
+		select {
+		case <-t.sem:
+		default:
+		}
+	}
+}
+
+/**
+Destroys all objects created by the pool including checked out ones
+*/
+func (t *objectPool) close(reason CloseReason) []error {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return nil
+	}
+	all := t.all
+	t.all, t.idle, t.busy, t.closed, t.reason = nil, nil, nil, true, reason
+	close(t.done)
+	t.mu.Unlock()
+	var listErr []error
+	for j := len(all) - 1; j >= 0; j-- {
+		if err := destroyObject(all[j], reason); err != nil {
+			listErr = append(listErr, err)
+		}
+	}
+	return listErr
+}
+
+func (t *context) resolvePool(def *injectionDef) (*objectPool, error) {
+	deep := t.getBean(def.weakType)
+	if len(deep) == 0 {
+		return nil, errors.Errorf("can not find candidates for pool of '%v'", def.weakType)
+	}
+	list := t.lookupBeans(def.weakType, def.selectBeans(deep, nil, t.logger()))
+	switch len(list) {
+	case 0:
+		return nil, errors.Errorf("can not find candidates for pool of '%v' on level %d", def.weakType, def.level)
+	case 1:
+	default:
+		return nil, errors.Errorf("pool of '%v' has multiple candidates %+v", def.weakType, list)
+	}
+	b := list[0]
+	if b.beenFactory == nil || b.beenFactory.pool == nil {
+		return nil, errors.Errorf("bean '%s' of pool '%v' is not produced by PoolingFactoryBean", b.name, def.weakType)
+	}
+	return b.beenFactory.pool, nil
+}
+
+/**
+Creates min objects of pools of the context
+*/
+func (t *context) fillPools() error {
+	for _, pool := range t.pools {
+		if err := pool.fill(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *context) closePools(reason CloseReason) []error {
+	var listErr []error
+	for j := len(t.pools) - 1; j >= 0; j-- {
+		listErr = append(listErr, t.pools[j].close(reason)...)
+	}
+	return listErr
+}
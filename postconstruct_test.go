@@ -6,12 +6,17 @@
 package glue_test
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"github.com/codeallergy/glue"
 	"github.com/stretchr/testify/require"
+	"log"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 var ServerServiceClass = reflect.TypeOf((*ServerService)(nil)).Elem()
@@ -189,3 +194,441 @@ func TestPostConstructCycle(t *testing.T) {
 	require.True(t, strings.Contains(err.Error(), "cycle"))
 	println(err.Error())
 }
+
+type reasonBean struct {
+	reasons []glue.CloseReason
+}
+
+func (t *reasonBean) DestroyWithReason(reason glue.CloseReason) error {
+	t.reasons = append(t.reasons, reason)
+	return nil
+}
+
+type failingBean struct {
+	Reason *reasonBean `inject`
+}
+
+func (t *failingBean) PostConstruct() error {
+	return errors.New("failing construct")
+}
+
+func TestDestroyWithReason(t *testing.T) {
+
+	b := &reasonBean{}
+	ctx, err := glue.New(b)
+	require.NoError(t, err)
+	require.NoError(t, ctx.Close())
+	require.Equal(t, []glue.CloseReason{glue.ShutdownReason}, b.reasons)
+
+	b = &reasonBean{}
+	_, err = glue.New(b, &failingBean{})
+	require.Error(t, err)
+	require.Equal(t, []glue.CloseReason{glue.StartupFailureReason}, b.reasons)
+
+	b = &reasonBean{}
+	parent, err := glue.New(glue.Child("child", b))
+	require.NoError(t, err)
+	child := parent.Children()[0]
+	_, err = child.Object()
+	require.NoError(t, err)
+	require.NoError(t, parent.Close())
+	require.Equal(t, []glue.CloseReason{glue.ParentCloseReason}, b.reasons)
+
+	b = &reasonBean{}
+	ctx, err = glue.New(b)
+	require.NoError(t, err)
+	defer ctx.Close()
+	list := ctx.Bean(reflect.TypeOf(b), glue.DefaultLevel)
+	require.Equal(t, 1, len(list))
+	require.NoError(t, list[0].Reload())
+	require.Equal(t, []glue.CloseReason{glue.ReloadReason}, b.reasons)
+}
+
+type rollbackLog struct {
+	destroyed []string
+}
+
+type rollbackFirst struct {
+	Log *rollbackLog
+}
+
+func (t *rollbackFirst) Destroy() error {
+	t.Log.destroyed = append(t.Log.destroyed, "first")
+	return nil
+}
+
+type rollbackSecond struct {
+	Log   *rollbackLog
+	First *rollbackFirst `inject`
+}
+
+func (t *rollbackSecond) Destroy() error {
+	t.Log.destroyed = append(t.Log.destroyed, "second")
+	return errors.New("second destroy error")
+}
+
+type rollbackFailing struct {
+	Second *rollbackSecond `inject`
+}
+
+func (t *rollbackFailing) PostConstruct() error {
+	return errors.New("failing construct")
+}
+
+type rollbackLast struct {
+	Log     *rollbackLog
+	Failing *rollbackFailing `inject`
+}
+
+func (t *rollbackLast) Destroy() error {
+	t.Log.destroyed = append(t.Log.destroyed, "last")
+	return nil
+}
+
+func TestPostConstructRollback(t *testing.T) {
+
+	log := &rollbackLog{}
+	_, err := glue.New(
+		&rollbackLast{Log: log},
+		&rollbackFailing{},
+		&rollbackSecond{Log: log},
+		&rollbackFirst{Log: log},
+	)
+	require.Error(t, err)
+
+	var startupErr *glue.StartupError
+	require.True(t, errors.As(err, &startupErr))
+	require.True(t, strings.Contains(startupErr.Err.Error(), "failing construct"))
+	require.Equal(t, 1, len(startupErr.Rollback))
+	require.True(t, strings.Contains(startupErr.Rollback[0].Error(), "second destroy error"))
+
+	require.Equal(t, []string{"second", "first"}, log.destroyed)
+}
+
+type childOpener struct {
+	Ctx glue.Context `inject`
+}
+
+func (t *childOpener) PostConstruct() error {
+	_, err := t.Ctx.Children()[0].Object()
+	return err
+}
+
+type childOpenerFailing struct {
+	Opener *childOpener `inject`
+}
+
+func (t *childOpenerFailing) PostConstruct() error {
+	return errors.New("failing construct")
+}
+
+func TestPostConstructRollbackChildren(t *testing.T) {
+
+	b := &reasonBean{}
+	_, err := glue.New(
+		glue.Child("child", b),
+		&childOpenerFailing{},
+		&childOpener{},
+	)
+	require.Error(t, err)
+	require.True(t, strings.Contains(err.Error(), "failing construct"))
+	require.Equal(t, []glue.CloseReason{glue.StartupFailureReason}, b.reasons)
+}
+
+type panicBean struct {
+}
+
+func (t *panicBean) PostConstruct() error {
+	panic("panic in construct")
+}
+
+func TestPostConstructDisableRecover(t *testing.T) {
+
+	_, err := glue.New(&panicBean{})
+	require.Error(t, err)
+	require.True(t, strings.Contains(err.Error(), "panic in construct"))
+
+	require.PanicsWithValue(t, "panic in construct", func() {
+		glue.New(glue.DisableRecover{}, &panicBean{})
+	})
+}
+
+type phasedBean struct {
+	phases []string
+}
+
+func (t *phasedBean) Stop() error {
+	t.phases = append(t.phases, glue.StopPhase)
+	return nil
+}
+
+func (t *phasedBean) Drain(ctx context.Context) error {
+	t.phases = append(t.phases, glue.DrainPhase)
+	return nil
+}
+
+func (t *phasedBean) Destroy() error {
+	t.phases = append(t.phases, glue.DisposePhase)
+	return nil
+}
+
+type slowDrainBean struct {
+	Phased *phasedBean `inject`
+}
+
+func (t *slowDrainBean) Drain(ctx context.Context) error {
+	time.Sleep(time.Second)
+	return nil
+}
+
+func TestClosePhases(t *testing.T) {
+
+	phased := &phasedBean{}
+	ctx, err := glue.New(
+		glue.CloseTimeouts{Drain: 10 * time.Millisecond},
+		phased,
+		&slowDrainBean{},
+	)
+	require.NoError(t, err)
+
+	err = ctx.Close()
+	require.Error(t, err)
+
+	var timeoutErr *glue.CloseTimeoutError
+	require.True(t, errors.As(err, &timeoutErr))
+	require.Equal(t, glue.DrainPhase, timeoutErr.Phase)
+	require.Equal(t, []string{"*glue_test.slowDrainBean"}, timeoutErr.Beans)
+
+	require.Equal(t, []string{glue.StopPhase, glue.DrainPhase, glue.DisposePhase}, phased.phases)
+}
+
+type earlyDisposable struct {
+	destroyed bool
+}
+
+func (t *earlyDisposable) Destroy() error {
+	t.destroyed = true
+	return nil
+}
+
+type slowDisposable struct {
+	Early *earlyDisposable `inject`
+}
+
+func (t *slowDisposable) Destroy() error {
+	time.Sleep(time.Second)
+	return nil
+}
+
+type lateDisposable struct {
+	Slow      *slowDisposable `inject`
+	destroyed bool
+}
+
+func (t *lateDisposable) Destroy() error {
+	t.destroyed = true
+	return nil
+}
+
+func TestCloseWithContext(t *testing.T) {
+
+	early := &earlyDisposable{}
+	late := &lateDisposable{}
+	ctx, err := glue.New(late, &slowDisposable{}, early)
+	require.NoError(t, err)
+
+	deadline, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err = ctx.CloseWithContext(deadline)
+	require.Error(t, err)
+	require.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	var canceledErr *glue.CloseCanceledError
+	require.True(t, errors.As(err, &canceledErr))
+	require.Equal(t, glue.DisposePhase, canceledErr.Phase)
+	require.Equal(t, []string{"*glue_test.slowDisposable", "*glue_test.earlyDisposable"}, canceledErr.Beans)
+
+	require.True(t, late.destroyed)
+	require.False(t, early.destroyed)
+}
+
+func TestCloseWithContextChildren(t *testing.T) {
+
+	ctx, err := glue.New(glue.Child("child", &slowDisposable{}, &earlyDisposable{}))
+	require.NoError(t, err)
+	_, err = ctx.Children()[0].Object()
+	require.NoError(t, err)
+
+	deadline, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err = ctx.CloseWithContext(deadline)
+	require.Less(t, time.Since(start), 500*time.Millisecond)
+	require.Error(t, err)
+
+	var canceledErr *glue.CloseCanceledError
+	require.True(t, errors.As(err, &canceledErr))
+	require.Equal(t, glue.DisposePhase, canceledErr.Phase)
+	require.Equal(t, []string{"*glue_test.slowDisposable", "*glue_test.earlyDisposable"}, canceledErr.Beans)
+}
+
+type stuckDisposable struct {
+}
+
+func (t *stuckDisposable) Destroy() error {
+	time.Sleep(time.Second)
+	return nil
+}
+
+func (t *stuckDisposable) DestroyTimeout() time.Duration {
+	return 10 * time.Millisecond
+}
+
+type disposalOrder struct {
+	mu   sync.Mutex
+	list []string
+	wg   sync.WaitGroup
+}
+
+func (t *disposalOrder) add(name string) {
+	t.mu.Lock()
+	t.list = append(t.list, name)
+	t.mu.Unlock()
+}
+
+type flushedStorage struct {
+	Order *disposalOrder `inject`
+}
+
+func (t *flushedStorage) Destroy() error {
+	t.Order.add("storage")
+	return nil
+}
+
+type flushingWriter struct {
+	Order   *disposalOrder  `inject`
+	Storage *flushedStorage `inject`
+	name    string
+}
+
+func (t *flushingWriter) BeanName() string {
+	return t.name
+}
+
+func (t *flushingWriter) Destroy() error {
+	// both writers flush at the same time
+	t.Order.wg.Done()
+	t.Order.wg.Wait()
+	t.Order.add(t.name)
+	return nil
+}
+
+func TestDisposalTimeoutAndParallel(t *testing.T) {
+
+	ctx, err := glue.New(&stuckDisposable{})
+	require.NoError(t, err)
+
+	err = ctx.Close()
+	var timeoutErr *glue.CloseTimeoutError
+	require.True(t, errors.As(err, &timeoutErr))
+	require.Equal(t, glue.DisposePhase, timeoutErr.Phase)
+	require.Equal(t, 10*time.Millisecond, timeoutErr.Timeout)
+
+	order := &disposalOrder{}
+	order.wg.Add(2)
+	ctx, err = glue.New(
+		glue.Parallel{Workers: 2},
+		glue.CloseTimeouts{Dispose: 5 * time.Second},
+		order,
+		&flushingWriter{name: "orders"},
+		&flushingWriter{name: "payments"},
+		&flushedStorage{},
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, ctx.Close())
+	require.Equal(t, 3, len(order.list))
+	require.Equal(t, "storage", order.list[2])
+}
+
+type halfInitialized struct {
+	fail      bool
+	destroyed bool
+}
+
+func (t *halfInitialized) PostConstruct() error {
+	if t.fail {
+		return errors.New("half initialized")
+	}
+	return nil
+}
+
+func (t *halfInitialized) Destroy() error {
+	t.destroyed = true
+	return nil
+}
+
+type notStartedConsumer struct {
+	stopped bool
+}
+
+func (t *notStartedConsumer) Consume(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (t *notStartedConsumer) Stop() error {
+	t.stopped = true
+	return nil
+}
+
+func TestSkipDestroyNotInitialized(t *testing.T) {
+
+	var buf bytes.Buffer
+	prev := glue.Warnings(log.New(&buf, "", 0))
+	defer glue.Warnings(prev)
+
+	failing := &halfInitialized{fail: true}
+	_, err := glue.New(failing)
+	require.Error(t, err)
+	require.False(t, failing.destroyed)
+	require.Contains(t, buf.String(), "Skip destroy of bean '*glue_test.halfInitialized' with type '*glue_test.halfInitialized', PostConstruct did not complete")
+
+	buf.Reset()
+	reloaded := &halfInitialized{}
+	ctx, err := glue.New(reloaded)
+	require.NoError(t, err)
+
+	reloaded.fail = true
+	list := ctx.Bean(reflect.TypeOf(reloaded), glue.DefaultLevel)
+	require.Equal(t, 1, len(list))
+	require.Error(t, list[0].Reload())
+	require.True(t, reloaded.destroyed)
+
+	reloaded.destroyed = false
+	require.NoError(t, ctx.Close())
+	require.False(t, reloaded.destroyed)
+	require.Contains(t, buf.String(), "Skip destroy")
+
+	buf.Reset()
+	consumer := &notStartedConsumer{}
+	ctx, err = glue.New(
+		glue.DescribeOnFlag{
+			Args: []string{glue.DescribeFlag},
+			Out:  &bytes.Buffer{},
+			Exit: func(int) {},
+		},
+		consumer,
+	)
+	require.NoError(t, err)
+	require.False(t, consumer.stopped)
+	require.Contains(t, buf.String(), "Skip stop of bean '*glue_test.notStartedConsumer'")
+
+	consumer = &notStartedConsumer{}
+	ctx, err = glue.New(consumer)
+	require.NoError(t, err)
+	require.NoError(t, ctx.Close())
+	require.True(t, consumer.stopped)
+}
@@ -0,0 +1,36 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestPrime(t *testing.T) {
+
+	ctx, err := glue.New(
+		&someService{testing: t},
+		&factoryBeanExample{testing: t},
+		&firstServiceImpl{testing: t},
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	err = ctx.Prime(beanConstructedClass, FirstServiceClass)
+	require.NoError(t, err)
+
+	list := ctx.Bean(beanConstructedClass, glue.DefaultLevel)
+	require.Equal(t, 1, len(list))
+	require.Equal(t, glue.BeanInitialized, list[0].Lifecycle())
+	require.NotNil(t, list[0].Object())
+
+	err = ctx.Prime(SecondServiceClass)
+	require.Error(t, err)
+	require.True(t, strings.Contains(err.Error(), "can not find candidates"))
+}
@@ -0,0 +1,70 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"sync"
+)
+
+/**
+Option of the context that reports progress of construction of beans during creation of the context,
+useful to render a startup progress bar in CLI applications and bootstrap screens.
+The function is called after each eager bean got constructed in the actual construction order,
+total is the number of eager beans of the context, lazy beans are not counted.
+The option is not inherited by contexts created by Extend.
+
+Example:
+	ctx, err := glue.New(
+		glue.OnProgress(func(done, total int, current glue.Bean) {
+			fmt.Printf("\r[%d/%d] %s", done, total, current.Name())
+		}),
+		&storage{},
+		&service{},
+	)
+*/
+type OnProgress func(done, total int, current Bean)
+
+func (t OnProgress) applyOption(ctx *context) {
+	ctx.onProgress = t
+}
+
+/**
+Counts eager beans constructed during creation of the context
+*/
+type progress struct {
+	mu      sync.Mutex
+	fn      OnProgress
+	pending map[*bean]bool
+	done    int
+	total   int
+}
+
+func newProgress(fn OnProgress, lists ...[]*bean) *progress {
+	p := &progress{
+		fn:      fn,
+		pending: make(map[*bean]bool),
+	}
+	for _, list := range lists {
+		for _, b := range list {
+			if b.Lifecycle() != BeanInitialized {
+				p.pending[b] = true
+			}
+		}
+	}
+	p.total = len(p.pending)
+	return p
+}
+
+func (t *progress) constructed(b *bean) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.pending[b] || b.Lifecycle() != BeanInitialized {
+		return
+	}
+	delete(t.pending, b)
+	t.done++
+	t.fn(t.done, t.total, b)
+}
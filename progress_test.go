@@ -0,0 +1,52 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type progressStorage struct {
+}
+
+type progressService struct {
+	Storage *progressStorage `inject`
+}
+
+type progressLazy struct {
+}
+
+func TestOnProgress(t *testing.T) {
+
+	var done []int
+	var totals []int
+	var names []string
+
+	ctx, err := glue.New(
+		glue.OnProgress(func(d, total int, current glue.Bean) {
+			done = append(done, d)
+			totals = append(totals, total)
+			names = append(names, current.Class().String())
+		}),
+		&progressService{},
+		&progressStorage{},
+		glue.Lazy(&progressLazy{}),
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.Equal(t, []int{1, 2}, done)
+	require.Equal(t, []int{2, 2}, totals)
+	require.Equal(t, []string{"*glue_test.progressStorage", "*glue_test.progressService"}, names)
+
+	child, err := ctx.Extend(&progressLazy{})
+	require.NoError(t, err)
+	defer child.Close()
+
+	require.Equal(t, 2, len(done))
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"github.com/pkg/errors"
+	"reflect"
+)
+
+func (t *context) Promote(b Bean) error {
+
+	parent := t.parent
+	if parent == nil {
+		return errors.New("can not promote bean in context without parent")
+	}
+
+	t.constructMu.Lock()
+	defer t.constructMu.Unlock()
+
+	impl, ok := b.(*bean)
+	if !ok || !t.ownsBean(impl) {
+		return errors.Errorf("bean '%v' does not belong to the context", b)
+	}
+	if impl.beenFactory != nil {
+		return errors.Errorf("can not promote bean '%s' produced by factory '%v'", impl.name, impl.beenFactory.factoryClassPtr)
+	}
+	if _, ok := impl.obj.(FactoryBean); ok {
+		return errors.Errorf("can not promote factory bean '%s'", impl.name)
+	}
+	if _, ok := impl.obj.(Consumer); ok {
+		return errors.Errorf("can not promote consumer '%s' running in the context", impl.name)
+	}
+	if impl.Lifecycle() != BeanInitialized {
+		return errors.Errorf("can not promote bean '%s' in lifecycle %v", impl.name, impl.Lifecycle())
+	}
+	for _, dep := range impl.dependencies {
+		if t.ownsBean(dep) {
+			return errors.Errorf("can not promote bean '%s' depending on bean '%s' of the context", impl.name, dep.name)
+		}
+	}
+	for _, dep := range impl.factoryDependencies {
+		if t.ownsBean(dep.factory.bean) {
+			return errors.Errorf("can not promote bean '%s' depending on factory bean '%s' of the context", impl.name, dep.factory.bean.name)
+		}
+	}
+
+	if t.logger() != nil {
+		t.logger().Printf("Promote bean '%s' with type '%v' to parent context\n", impl.name, impl.beanDef.classPtr)
+	}
+
+	t.coreMu.Lock()
+	current := t.coreBeans()
+	core := make(map[reflect.Type][]*bean, len(current))
+	for typ, list := range current {
+		if list = removeBean(list, impl); len(list) > 0 {
+			core[typ] = list
+		}
+	}
+	t.core.Store(core)
+	t.coreMu.Unlock()
+	t.registry.remove(impl)
+	t.disposablesMu.Lock()
+	t.disposables = removeBean(t.disposables, impl)
+	t.disposablesMu.Unlock()
+
+	parent.constructMu.Lock()
+	defer parent.constructMu.Unlock()
+
+	parent.coreMu.Lock()
+	current = parent.coreBeans()
+	core = make(map[reflect.Type][]*bean, len(current)+1)
+	for typ, list := range current {
+		core[typ] = list
+	}
+	classPtr := impl.beanDef.classPtr
+	core[classPtr] = append(append([]*bean(nil), core[classPtr]...), impl)
+	parent.core.Store(core)
+	parent.coreMu.Unlock()
+	parent.registry.promote(impl)
+	parent.disposablesMu.Lock()
+	parent.disposables = append(parent.disposables, impl)
+	parent.disposablesMu.Unlock()
+	return nil
+}
+
+/**
+Checks that the bean is scanned in the current context
+*/
+func (t *context) ownsBean(b *bean) bool {
+	for _, list := range t.coreBeans() {
+		for _, item := range list {
+			if item == b {
+				return true
+			}
+		}
+	}
+	return false
+}
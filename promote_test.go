@@ -0,0 +1,79 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"reflect"
+	"testing"
+)
+
+type sharedModel struct {
+	destroyed int
+}
+
+func (t *sharedModel) Destroy() error {
+	t.destroyed++
+	return nil
+}
+
+var sharedModelClass = reflect.TypeOf((*sharedModel)(nil))
+
+type modelTenant struct {
+}
+
+type tenantModel struct {
+	Tenant *modelTenant `inject`
+}
+
+func TestPromote(t *testing.T) {
+
+	parent, err := glue.New()
+	require.NoError(t, err)
+
+	model := &sharedModel{}
+	first, err := parent.Extend(model)
+	require.NoError(t, err)
+
+	require.Empty(t, parent.Bean(sharedModelClass, glue.LocalLevel))
+	list := first.Bean(sharedModelClass, glue.LocalLevel)
+	require.Equal(t, 1, len(list))
+
+	require.NoError(t, first.Promote(list[0]))
+	require.Empty(t, first.Bean(sharedModelClass, glue.LocalLevel))
+	require.Equal(t, 1, len(first.Bean(sharedModelClass, glue.DefaultLevel)))
+
+	require.NoError(t, first.Close())
+	require.Equal(t, 0, model.destroyed)
+
+	holder := &struct {
+		Model *sharedModel `inject`
+	}{}
+	second, err := parent.Extend(holder)
+	require.NoError(t, err)
+	require.Same(t, model, holder.Model)
+	require.NoError(t, second.Close())
+
+	require.NoError(t, parent.Close())
+	require.Equal(t, 1, model.destroyed)
+
+	parent, err = glue.New()
+	require.NoError(t, err)
+	defer parent.Close()
+
+	child, err := parent.Extend(&modelTenant{}, &tenantModel{})
+	require.NoError(t, err)
+	defer child.Close()
+
+	list = child.Bean(reflect.TypeOf((*tenantModel)(nil)), glue.LocalLevel)
+	require.Equal(t, 1, len(list))
+	err = child.Promote(list[0])
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "depending on bean '*glue_test.modelTenant' of the context")
+
+	require.Error(t, parent.Promote(list[0]))
+}
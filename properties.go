@@ -10,7 +10,9 @@ import (
 	"github.com/pkg/errors"
 	"io"
 	"io/ioutil"
+	"math"
 	"os"
+	"reflect"
 	"sort"
 	"strconv"
 	"strings"
@@ -31,6 +33,11 @@ type properties struct {
 
 	resolvers []PropertyResolver
 
+	interceptors []PropertyInterceptor
+
+	// keys of properties with values hidden from dumps, logs and errors
+	sensitive map[string]bool
+
 	// property conversion error handler
 	errorHandler func(string, error)
 
@@ -42,6 +49,7 @@ func NewProperties() Properties {
 		store: make(map[string]string),
 		comments: make(map[string][]string),
 		resolvers: make([]PropertyResolver, 0, 10),
+		sensitive: make(map[string]bool),
 	}
 	t.Register(t)
 	return t
@@ -50,7 +58,7 @@ func NewProperties() Properties {
 func (t *properties) String() string {
 	t.RLock()
 	defer t.RUnlock()
-	return fmt.Sprintf("Properties{priority=%d,store=%d,comments=%d,resolvers=%d,errorHandler=%v}", t.priority, len(t.store), len(t.comments),len(t.resolvers),t.errorHandler != nil)
+	return fmt.Sprintf("Properties{priority=%d,store=%d,comments=%d,resolvers=%d,interceptors=%d,errorHandler=%v}", t.priority, len(t.store), len(t.comments),len(t.resolvers),len(t.interceptors),t.errorHandler != nil)
 }
 
 func (t *properties) Register(resolver PropertyResolver) {
@@ -64,6 +72,54 @@ func (t *properties) Register(resolver PropertyResolver) {
 	}
 }
 
+func (t *properties) Unregister(resolver PropertyResolver) bool {
+	if resolver == nil || !reflect.TypeOf(resolver).Comparable() {
+		return false
+	}
+	t.Lock()
+	defer t.Unlock()
+	for i, r := range t.resolvers {
+		if reflect.TypeOf(r).Comparable() && r == resolver {
+			t.resolvers = append(t.resolvers[:i], t.resolvers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (t *properties) Explain(key string) []ResolutionStep {
+	sensitive := t.IsSensitive(key)
+	var steps []ResolutionStep
+	var resolved bool
+	for _, r := range t.PropertyResolvers() {
+		if scoped, ok := r.(*scopedResolver); ok && scoped.expired() {
+			continue
+		}
+		step := ResolutionStep{
+			Resolver: describeResolver(r),
+			Priority: r.Priority(),
+		}
+		if value, ok := r.GetProperty(key); ok {
+			step.Matched = true
+			step.Value = value
+			if sensitive {
+				step.Value = redactedValue
+			}
+			step.Winner = !resolved
+			resolved = true
+		}
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+func describeResolver(r PropertyResolver) string {
+	if s, ok := r.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return fmt.Sprintf("%T", r)
+}
+
 func (t *properties) PropertyResolvers() []PropertyResolver {
 	t.RLock()
 	defer t.RUnlock()
@@ -72,6 +128,20 @@ func (t *properties) PropertyResolvers() []PropertyResolver {
 	return buf
 }
 
+func (t *properties) Intercept(interceptor PropertyInterceptor) {
+	t.Lock()
+	defer t.Unlock()
+	t.interceptors = append(t.interceptors, interceptor)
+}
+
+func (t *properties) PropertyInterceptors() []PropertyInterceptor {
+	t.RLock()
+	defer t.RUnlock()
+	buf := make([]PropertyInterceptor, len(t.interceptors))
+	copy(buf, t.interceptors)
+	return buf
+}
+
 func (t *properties) Priority() int {
 	return t.priority
 }
@@ -164,6 +234,13 @@ func (t *properties) Dump() string {
 	keys := t.Keys()
 	sort.Strings(keys)
 
+	sensitive := make(map[string]bool)
+	for _, key := range keys {
+		if t.IsSensitive(key) {
+			sensitive[key] = true
+		}
+	}
+
 	t.RLock()
 	defer t.RUnlock()
 
@@ -179,6 +256,10 @@ func (t *properties) Dump() string {
 				}
 			}
 
+			if sensitive[key] {
+				value = redactedValue
+			}
+
 			output.WriteString(fmt.Sprintf("%s = %s\n", encodeUtf8(key, " :"), encodeUtf8(value, "")))
 
 		}
@@ -190,8 +271,10 @@ func (t *properties) Dump() string {
 
 func (t *properties) Extend(parent Properties) {
 	r := parent.PropertyResolvers()
+	i := parent.PropertyInterceptors()
 	t.Lock()
 	defer t.Unlock()
+	t.interceptors = append(t.interceptors, i...)
 	t.priority = max(t.priority, parent.Priority()) + 1
 	for _, item := range r {
 		t.resolvers = append(t.resolvers, item)
@@ -201,6 +284,52 @@ func (t *properties) Extend(parent Properties) {
 	})
 }
 
+/**
+Extends the parent properties with the lowest priority of own values, so values of the parent and its resolvers win
+*/
+func (t *properties) extendUnder(parent Properties) {
+	r := parent.PropertyResolvers()
+	i := parent.PropertyInterceptors()
+	t.Lock()
+	defer t.Unlock()
+	t.interceptors = append(t.interceptors, i...)
+	priority := parent.Priority()
+	for _, item := range r {
+		priority = min(priority, item.Priority())
+		t.resolvers = append(t.resolvers, item)
+	}
+	t.priority = min(t.priority, priority - 1)
+	sort.Slice(t.resolvers, func(i, j int) bool {
+		return t.resolvers[i].Priority() >= t.resolvers[j].Priority()
+	})
+}
+
+func (t *properties) Sub(prefix string) Properties {
+	return newSubProperties(t, prefix)
+}
+
+func (t *properties) Push(overlay map[string]string) (pop func()) {
+	t.Lock()
+	priority := t.priority
+	for _, r := range t.resolvers {
+		if r != t {
+			priority = max(priority, r.Priority())
+		}
+	}
+	t.Unlock()
+	r := &overlayResolver{store: make(map[string]string, len(overlay)), priority: priority + 1}
+	for key, value := range overlay {
+		r.store[key] = value
+	}
+	t.Register(r)
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			t.Unregister(r)
+		})
+	}
+}
+
 func max(a, b int) int {
 	if a > b {
 		return a
@@ -209,6 +338,14 @@ func max(a, b int) int {
 	}
 }
 
+func min(a, b int) int {
+	if a < b {
+		return a
+	} else {
+		return b
+	}
+}
+
 func (t *properties) Len() int {
 	t.RLock()
 	defer t.RUnlock()
@@ -222,6 +359,7 @@ func (t *properties) Keys() []string {
 	for k, _ := range t.store {
 		keys = append(keys, k)
 	}
+	sort.Strings(keys)
 	return keys
 }
 
@@ -259,11 +397,33 @@ func (t *properties) nextPropertyResolver(i int) (PropertyResolver, bool) {
 }
 
 func (t *properties) Get(key string) (value string, ok bool) {
+	interceptors := t.PropertyInterceptors()
+	if len(interceptors) == 0 {
+		return t.resolve(key)
+	}
+	return t.around(interceptors, key)
+}
+
+func (t *properties) around(interceptors []PropertyInterceptor, key string) (string, bool) {
+	if len(interceptors) == 0 {
+		return t.resolve(key)
+	}
+	return interceptors[0].Around(key, func(key string) (string, bool) {
+		return t.around(interceptors[1:], key)
+	})
+}
+
+func (t *properties) resolve(key string) (value string, ok bool) {
 	for i := 0;; i++ {
 		r, ok := t.nextPropertyResolver(i)
 		if !ok {
 			break
 		}
+		if scoped, ok := r.(*scopedResolver); ok && scoped.expired() {
+			t.Unregister(r)
+			i--
+			continue
+		}
 		if value, ok := r.GetProperty(key); ok {
 			return value, true
 		}
@@ -291,13 +451,41 @@ func (t *properties) SetErrorHandler(onError func(string, error)) {
 	t.errorHandler = onError
 }
 
+func (t *properties) onError(key string, err error) {
+	cb := t.GetErrorHandler()
+	if cb != nil {
+		cb(key, redactError(t, key, err))
+	}
+}
+
+func (t *properties) MarkSensitive(keys ...string) {
+	t.Lock()
+	defer t.Unlock()
+	for _, key := range keys {
+		t.sensitive[key] = true
+	}
+}
+
+func (t *properties) IsSensitive(key string) bool {
+	t.RLock()
+	sensitive := t.sensitive[key]
+	t.RUnlock()
+	if sensitive {
+		return true
+	}
+	// sensitive keys of parent properties
+	for _, r := range t.PropertyResolvers() {
+		if p, ok := r.(Properties); ok && p != t && p.IsSensitive(key) {
+			return true
+		}
+	}
+	return false
+}
+
 func (t *properties) GetBool(key string, def bool) bool {
 	if value, ok := t.Get(key); ok {
 		if v, err := parseBool(value); err != nil {
-			cb := t.GetErrorHandler()
-			if cb != nil {
-				cb(key, err)
-			}
+			t.onError(key, err)
 			return def
 		} else {
 			return v
@@ -309,14 +497,11 @@ func (t *properties) GetBool(key string, def bool) bool {
 
 func (t *properties) GetInt(key string, def int) int {
 	if value, ok := t.Get(key); ok {
-		if v, err := strconv.Atoi(value); err != nil {
-			cb := t.GetErrorHandler()
-			if cb != nil {
-				cb(key, err)
-			}
+		if v, err := parseInt(value, strconv.IntSize); err != nil {
+			t.onError(key, err)
 			return def
 		} else {
-			return v
+			return int(v)
 		}
 	} else {
 		return def
@@ -326,10 +511,7 @@ func (t *properties) GetInt(key string, def int) int {
 func (t *properties) GetFloat(key string, def float32) float32 {
 	if value, ok := t.Get(key); ok {
 		if f, err := strconv.ParseFloat(value, 32); err != nil {
-			cb := t.GetErrorHandler()
-			if cb != nil {
-				cb(key, err)
-			}
+			t.onError(key, err)
 			return def
 		} else {
 			return float32(f)
@@ -342,10 +524,7 @@ func (t *properties) GetFloat(key string, def float32) float32 {
 func (t *properties) GetDouble(key string, def float64) float64 {
 	if value, ok := t.Get(key); ok {
 		if f, err := strconv.ParseFloat(value, 64); err != nil {
-			cb := t.GetErrorHandler()
-			if cb != nil {
-				cb(key, err)
-			}
+			t.onError(key, err)
 			return def
 		} else {
 			return f
@@ -358,10 +537,7 @@ func (t *properties) GetDouble(key string, def float64) float64 {
 func (t *properties) GetDuration(key string, def time.Duration) time.Duration {
 	if str, ok := t.Get(key); ok {
 		if value, err := time.ParseDuration(str); err != nil {
-			cb := t.GetErrorHandler()
-			if cb != nil {
-				cb(key, err)
-			}
+			t.onError(key, err)
 			return def
 		} else {
 			return value
@@ -373,7 +549,12 @@ func (t *properties) GetDuration(key string, def time.Duration) time.Duration {
 
 func (t *properties) GetFileMode(key string, def os.FileMode) os.FileMode {
 	if str, ok := t.Get(key); ok {
-		return parseFileMode(str)
+		if value, err := parseFileMode(str); err != nil {
+			t.onError(key, err)
+			return def
+		} else {
+			return value
+		}
 	} else {
 		return def
 	}
@@ -452,6 +633,21 @@ func escape(r rune, special string) string {
 	}
 }
 
+/**
+Placeholder of sensitive property values
+*/
+const redactedValue = "******"
+
+/**
+Conversion errors usually contain the value, hide it for sensitive properties
+*/
+func redactError(p Properties, key string, err error) error {
+	if err != nil && p.IsSensitive(key) {
+		return errors.Errorf("invalid value of sensitive property '%s'", key)
+	}
+	return err
+}
+
 func parseBool(str string) (bool, error) {
 	switch str {
 	case "1", "t", "T", "true", "TRUE", "True", "on", "ON", "On":
@@ -463,27 +659,256 @@ func parseBool(str string) (bool, error) {
 }
 
 /**
-Parses only os.Unix file mode with 0777 mask
+Parses integer in decimal form with optional '_' separators like '1_000_000' or with '0x', '0o', '0b' prefixes,
+the value must fit in bitSize. Leading zeros of decimal form do not switch it to octal.
 */
-func parseFileMode(s string) os.FileMode {
+func parseInt(s string, bitSize int) (int64, error) {
+	digits, base, err := numberLiteral(s)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(digits, base, bitSize)
+}
 
-	var m uint32
+func parseUint(s string, bitSize int) (uint64, error) {
+	digits, base, err := numberLiteral(s)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(digits, base, bitSize)
+}
+
+/**
+Returns digits of the integer literal with the sign and the base of them, validates '_' separators
+*/
+func numberLiteral(s string) (string, int, error) {
+	s = strings.TrimSpace(s)
+	sign, body := "", s
+	if strings.HasPrefix(body, "-") || strings.HasPrefix(body, "+") {
+		sign, body = body[:1], body[1:]
+	}
+	base := 10
+	if len(body) > 2 && body[0] == '0' {
+		switch body[1] {
+		case 'x', 'X':
+			base = 16
+		case 'o', 'O':
+			base = 8
+		case 'b', 'B':
+			base = 2
+		}
+		if base != 10 {
+			body = body[2:]
+		}
+	}
+	if strings.Contains(body, "_") {
+		if strings.HasPrefix(body, "_") || strings.HasSuffix(body, "_") || strings.Contains(body, "__") {
+			return "", 0, errors.Errorf("invalid number '%s', misplaced '_'", s)
+		}
+		body = strings.ReplaceAll(body, "_", "")
+	}
+	return sign + body, base, nil
+}
+
+/**
+Parses file mode in octal form like '0644' or '4755', or in symbolic form like '-rwxr-xr-x', 'drwxrwxrwt' or 'rwsr-xr-x'.
+Symbolic form accepts setuid, setgid and sticky bits as 's', 'S', 't', 'T' in execute positions or 'u', 'g', 't' prefixes
+produced by os.FileMode.String, short symbolic forms are aligned to the right.
+*/
+func parseFileMode(s string) (os.FileMode, error) {
+
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, errors.New("empty file mode")
+	}
+
+	if isOctalFileMode(s) {
+		m, err := strconv.ParseUint(strings.TrimPrefix(s, "0o"), 8, 32)
+		if err != nil || m > 07777 {
+			return 0, errors.Errorf("invalid octal file mode '%s'", s)
+		}
+		mode := os.FileMode(m & 0777)
+		if m&04000 != 0 {
+			mode |= os.ModeSetuid
+		}
+		if m&02000 != 0 {
+			mode |= os.ModeSetgid
+		}
+		if m&01000 != 0 {
+			mode |= os.ModeSticky
+		}
+		return mode, nil
+	}
 
 	const rwx = "rwxrwxrwx"
-	off := len(s) - len(rwx)
-	if off < 0 {
-		buf := []byte("---------")
-		copy(buf[-off:], s)
-		s = string(buf)
+	prefix, perm := "", s
+	if off := len(s) - len(rwx); off < 0 {
+		perm = strings.Repeat("-", -off) + s
 	} else {
-		s = s[off:]
+		prefix, perm = s[:off], s[off:]
 	}
 
-	for i, c := range rwx {
-		if byte(c) == s[i] {
-			m |= 1<<uint(9-1-i)
+	var mode os.FileMode
+	for _, c := range prefix {
+		switch c {
+		case '-':
+		case 'd':
+			mode |= os.ModeDir
+		case 'l', 'L':
+			mode |= os.ModeSymlink
+		case 'u':
+			mode |= os.ModeSetuid
+		case 'g':
+			mode |= os.ModeSetgid
+		case 't':
+			mode |= os.ModeSticky
+		default:
+			return 0, errors.Errorf("invalid file mode '%s', unknown type '%c'", s, c)
 		}
 	}
 
-	return os.FileMode(m)
-}
\ No newline at end of file
+	for i := 0; i < len(rwx); i++ {
+		c := perm[i]
+		bit := os.FileMode(1 << uint(9-1-i))
+		switch {
+		case c == rwx[i]:
+			mode |= bit
+		case c == '-':
+		case i == 2 && (c == 's' || c == 'S'):
+			mode |= os.ModeSetuid
+		case i == 5 && (c == 's' || c == 'S'):
+			mode |= os.ModeSetgid
+		case i == 8 && (c == 't' || c == 'T'):
+			mode |= os.ModeSticky
+		default:
+			return 0, errors.Errorf("invalid file mode '%s', unexpected '%c' at position %d", s, c, len(prefix)+i+1)
+		}
+		// lower case special bit means execute bit is set too
+		if c == 's' || c == 't' {
+			mode |= bit
+		}
+	}
+
+	return mode, nil
+}
+
+func isOctalFileMode(s string) bool {
+	s = strings.TrimPrefix(s, "0o")
+	if s == "" || len(s) > 5 {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '7' {
+			return false
+		}
+	}
+	return true
+}
+
+/**
+Property resolver answering only keys with the prefix until the time to live expires, expired resolver is removed from properties on the next lookup.
+Helps to register dynamic resolvers like per-test overrides or temporary remote sources without accumulating them.
+
+Example:
+	r := glue.ScopedResolver("feature.", time.Minute, overrides)
+	ctx.Properties().Register(r)
+	defer ctx.Properties().Unregister(r)
+*/
+func ScopedResolver(prefix string, ttl time.Duration, resolver PropertyResolver) PropertyResolver {
+	t := &scopedResolver{prefix: prefix, resolver: resolver}
+	if ttl > 0 {
+		t.expires = time.Now().Add(ttl)
+	}
+	return t
+}
+
+type scopedResolver struct {
+	prefix   string
+	expires  time.Time
+	resolver PropertyResolver
+}
+
+func (t *scopedResolver) expired() bool {
+	return !t.expires.IsZero() && !time.Now().Before(t.expires)
+}
+
+func (t *scopedResolver) Priority() int {
+	return t.resolver.Priority()
+}
+
+func (t *scopedResolver) GetProperty(key string) (string, bool) {
+	if !strings.HasPrefix(key, t.prefix) || t.expired() {
+		return "", false
+	}
+	return t.resolver.GetProperty(key)
+}
+
+func (t *scopedResolver) String() string {
+	return fmt.Sprintf("ScopedResolver{prefix=%s,expires=%v,resolver=%T}", t.prefix, t.expires, t.resolver)
+}
+
+/**
+Temporary overlay of properties registered by Push above all other resolvers
+*/
+type overlayResolver struct {
+	store    map[string]string
+	priority int
+}
+
+/**
+Priority of property overrides set by Builder, above regular resolvers and below overlays of Push
+*/
+const overridesResolverPriority = 1 << 20
+
+func (t *overlayResolver) Priority() int {
+	return t.priority
+}
+
+func (t *overlayResolver) GetProperty(key string) (string, bool) {
+	value, ok := t.store[key]
+	return value, ok
+}
+
+func (t *overlayResolver) String() string {
+	return fmt.Sprintf("PropertyOverlay{keys=%d,priority=%d}", len(t.store), t.priority)
+}
+
+/**
+Catalogue of default values of properties registered in the scan list as the resolver with the lowest priority,
+centralizes defaults of libraries instead of scattering them across 'default=' clauses of value tags and makes them visible by Explain and bindings.
+
+Example:
+	glue.New(
+		glue.Defaults(map[string]string{
+			"http.client.timeout": "10s",
+			"db.pool.size":        "8",
+		}),
+		&service{},
+	)
+*/
+func Defaults(values map[string]string) PropertyResolver {
+	t := &defaultsResolver{values: make(map[string]string, len(values))}
+	for k, v := range values {
+		t.values[k] = v
+	}
+	return t
+}
+
+const defaultsResolverPriority = math.MinInt32
+
+type defaultsResolver struct {
+	values map[string]string
+}
+
+func (t *defaultsResolver) Priority() int {
+	return defaultsResolverPriority
+}
+
+func (t *defaultsResolver) GetProperty(key string) (string, bool) {
+	value, ok := t.values[key]
+	return value, ok
+}
+
+func (t *defaultsResolver) String() string {
+	return fmt.Sprintf("Defaults{keys=%d}", len(t.values))
+}
@@ -14,7 +14,10 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"testing/fstest"
 	"time"
 )
 
@@ -378,3 +381,733 @@ func TestParseFileMode(t *testing.T) {
 
 }
 
+
+type countingInterceptor struct {
+	access map[string]int
+}
+
+func (t *countingInterceptor) Around(key string, next func(string) (string, bool)) (string, bool) {
+	t.access[key]++
+	return next(key)
+}
+
+type upperInterceptor struct {
+}
+
+func (t upperInterceptor) Around(key string, next func(string) (string, bool)) (string, bool) {
+	value, ok := next(key)
+	return strings.ToUpper(value), ok
+}
+
+func TestPropertyInterceptor(t *testing.T) {
+
+	p := glue.NewProperties()
+	p.Set("example.str", "value")
+
+	counter := &countingInterceptor{access: make(map[string]int)}
+	p.Intercept(counter)
+	p.Intercept(upperInterceptor{})
+
+	require.Equal(t, "VALUE", p.GetString("example.str", ""))
+	require.Equal(t, "def", p.GetString("example.none", "def"))
+	require.Equal(t, 1, counter.access["example.str"])
+	require.Equal(t, 1, counter.access["example.none"])
+	require.Equal(t, 2, len(p.PropertyInterceptors()))
+
+	child := glue.NewProperties()
+	child.Extend(p)
+	require.Equal(t, "VALUE", child.GetString("example.str", ""))
+	require.Equal(t, 2, counter.access["example.str"])
+}
+
+type interceptedBean struct {
+	Str string `value:"example.str"`
+}
+
+func TestPropertyInterceptorBean(t *testing.T) {
+
+	counter := &countingInterceptor{access: make(map[string]int)}
+	b := new(interceptedBean)
+
+	ctx, err := glue.New(
+		glue.PropertySource{Map: map[string]interface{}{"example.str": "value"}},
+		counter,
+		b,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.Equal(t, "value", b.Str)
+	require.Equal(t, 1, counter.access["example.str"])
+}
+
+type sensitiveBean struct {
+	Password string `value:"db.password,sensitive"`
+	Port     int    `value:"db.port,sensitive"`
+}
+
+func TestSensitiveProperties(t *testing.T) {
+
+	p := glue.NewProperties()
+	p.Set("db.user", "admin")
+	p.Set("db.secret", "secret")
+	p.MarkSensitive("db.secret")
+
+	require.True(t, p.IsSensitive("db.secret"))
+	require.False(t, p.IsSensitive("db.user"))
+
+	dump := p.Dump()
+	require.True(t, strings.Contains(dump, "db.user = admin"))
+	require.False(t, strings.Contains(dump, "secret = secret"))
+	require.Equal(t, "secret", p.GetString("db.secret", ""))
+
+	var handled error
+	p.Set("db.secret", "abc")
+	p.SetErrorHandler(func(key string, err error) {
+		handled = err
+	})
+	require.Equal(t, 5, p.GetInt("db.secret", 5))
+	require.Error(t, handled)
+	require.False(t, strings.Contains(handled.Error(), "abc"))
+
+	child := glue.NewProperties()
+	child.Extend(p)
+	require.True(t, child.IsSensitive("db.secret"))
+}
+
+func TestSensitivePropertiesValueTag(t *testing.T) {
+
+	b := new(sensitiveBean)
+	ctx, err := glue.New(
+		glue.PropertySource{Map: map[string]interface{}{"db.password": "pass", "db.port": "8080"}},
+		b,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.Equal(t, "pass", b.Password)
+	require.True(t, ctx.Properties().IsSensitive("db.password"))
+	require.True(t, strings.Contains(ctx.Properties().Dump(), "db.password = ******"))
+
+	_, err = glue.New(
+		glue.PropertySource{Map: map[string]interface{}{"db.password": "pass", "db.port": "top-secret-port"}},
+		new(sensitiveBean),
+	)
+	require.Error(t, err)
+	require.False(t, strings.Contains(err.Error(), "top-secret-port"))
+}
+
+func TestSubProperties(t *testing.T) {
+
+	p := glue.NewProperties()
+	p.Set("db.host", "localhost")
+	p.Set("db.port", "5432")
+	p.Set("db.pool.size", "10")
+	p.Set("server.port", "8080")
+
+	db := p.Sub("db")
+	require.Equal(t, 3, db.Len())
+	require.Equal(t, "localhost", db.GetString("host", ""))
+	require.Equal(t, 5432, db.GetInt("port", 0))
+	require.False(t, db.Contains("server.port"))
+
+	pool := db.Sub("pool.")
+	require.Equal(t, 10, pool.GetInt("size", 0))
+
+	// live view
+	db.Set("user", "admin")
+	require.Equal(t, "admin", p.GetString("db.user", ""))
+	p.Set("db.host", "remote")
+	require.Equal(t, "remote", db.GetString("host", ""))
+
+	db.MarkSensitive("user")
+	require.True(t, p.IsSensitive("db.user"))
+	require.True(t, strings.Contains(db.Dump(), "user = ******"))
+	require.False(t, strings.Contains(db.Dump(), "server"))
+
+	require.NoError(t, db.Parse("timeout = 5s\n"))
+	require.Equal(t, "5s", p.GetString("db.timeout", ""))
+
+	db.Clear()
+	require.Equal(t, 0, db.Len())
+	require.Equal(t, 1, p.Len())
+	require.Equal(t, "8080", p.GetString("server.port", ""))
+}
+
+var prefixedServerClass = reflect.TypeOf((*prefixedServer)(nil))
+
+type prefixedServer struct {
+	Host string `value:"host,default=localhost"`
+	Port int    `value:"port"`
+}
+
+type prefixedServerHolder struct {
+	ServerA *prefixedServer `inject:"bean=instanceA.*glue_test.prefixedServer"`
+	ServerB *prefixedServer `inject:"bean=instanceB.*glue_test.prefixedServer"`
+}
+
+func TestWithPrefix(t *testing.T) {
+
+	holder := new(prefixedServerHolder)
+	ctx, err := glue.New(
+		glue.PropertySource{Map: map[string]interface{}{
+			"instanceA.port": 8080,
+			"instanceB.host": "remote",
+			"instanceB.port": 9090,
+		}},
+		glue.WithPrefix("instanceA.", new(prefixedServer)),
+		glue.WithPrefix("instanceB", new(prefixedServer)),
+		holder,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.Equal(t, "localhost", holder.ServerA.Host)
+	require.Equal(t, 8080, holder.ServerA.Port)
+	require.Equal(t, "remote", holder.ServerB.Host)
+	require.Equal(t, 9090, holder.ServerB.Port)
+
+	require.Equal(t, 2, len(ctx.Bean(prefixedServerClass, glue.DefaultLevel)))
+	list := ctx.Lookup("instanceB.*glue_test.prefixedServer", glue.DefaultLevel)
+	require.Equal(t, 1, len(list))
+	require.Equal(t, holder.ServerB, list[0].Object())
+}
+
+type poolConfig struct {
+	Size    int           `value:"size,default=5"`
+	Timeout time.Duration `value:"timeout,default=1s"`
+}
+
+type dbConfig struct {
+	Host string      `value:"host,default=localhost"`
+	Pool *poolConfig `value:"pool"`
+}
+
+type requestConfig struct {
+	DB       dbConfig          `value:"db"`
+	Labels   map[string]string `value:"labels"`
+	Limits   map[string]int    `value:"limits,default=read=10;write=5"`
+	Password string            `value:"password,sensitive"`
+}
+
+func TestRuntimeInjectNestedProperties(t *testing.T) {
+
+	ctx, err := glue.New(
+		glue.PropertySource{Map: map[string]interface{}{
+			"request.db.host":      "remote",
+			"request.db.pool.size": 20,
+			"request.labels.env":   "prod",
+			"request.labels.zone":  "us",
+			"request.password":     "secret",
+		}},
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	cfg := new(requestConfig)
+	require.NoError(t, ctx.Inject(glue.WithPrefix("request", cfg)))
+
+	require.Equal(t, "remote", cfg.DB.Host)
+	require.NotNil(t, cfg.DB.Pool)
+	require.Equal(t, 20, cfg.DB.Pool.Size)
+	require.Equal(t, time.Second, cfg.DB.Pool.Timeout)
+	require.Equal(t, map[string]string{"env": "prod", "zone": "us"}, cfg.Labels)
+	require.Equal(t, map[string]int{"read": 10, "write": 5}, cfg.Limits)
+	require.Equal(t, "secret", cfg.Password)
+	require.True(t, ctx.Properties().IsSensitive("request.password"))
+
+	child, err := ctx.Extend(
+		glue.PropertySource{Map: map[string]interface{}{
+			"request.labels.zone": "eu",
+			"request.limits.read": 1,
+		}},
+	)
+	require.NoError(t, err)
+	defer child.Close()
+
+	cfg = new(requestConfig)
+	require.NoError(t, child.Inject(glue.WithPrefix("request", cfg)))
+	require.Equal(t, map[string]string{"env": "prod", "zone": "eu"}, cfg.Labels)
+	require.Equal(t, map[string]int{"read": 1}, cfg.Limits)
+}
+
+type nestedPropertiesBean struct {
+	DB dbConfig `value:"db"`
+}
+
+func TestNestedPropertiesBean(t *testing.T) {
+
+	b := new(nestedPropertiesBean)
+	ctx, err := glue.New(
+		glue.PropertySource{Map: map[string]interface{}{"db.pool.size": 7}},
+		b,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.Equal(t, "localhost", b.DB.Host)
+	require.Equal(t, 7, b.DB.Pool.Size)
+}
+
+func TestPropertySourcesOrder(t *testing.T) {
+
+	files := fstest.MapFS{
+		"a.properties": &fstest.MapFile{Data: []byte("name = a\nfirst = a\n")},
+		"b.yaml":       &fstest.MapFile{Data: []byte("name: b\nsecond: b\n")},
+		"c.properties": &fstest.MapFile{Data: []byte("name = c\n")},
+	}
+
+	ctx, err := glue.New(
+		glue.ResourceSource{
+			Name:       "resources",
+			AssetNames: []string{"a.properties", "b.yaml", "c.properties"},
+			AssetFiles: http.FS(files),
+		},
+		glue.PropertySource{Path: "resources:a.properties"},
+		glue.PropertySource{Path: "resources:b.yaml"},
+		glue.PropertySource{Path: "resources:c.properties"},
+		glue.PropertySource{Map: map[string]interface{}{"third": "map"}},
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.Equal(t, "c", ctx.Properties().GetString("name", ""))
+	require.Equal(t, "a", ctx.Properties().GetString("first", ""))
+	require.Equal(t, "b", ctx.Properties().GetString("second", ""))
+	require.Equal(t, "map", ctx.Properties().GetString("third", ""))
+
+	_, err = glue.New(
+		glue.PropertySource{Path: "resources:missing1.properties"},
+		glue.PropertySource{Path: "resources:missing2.properties"},
+	)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "missing1.properties")
+	require.Contains(t, err.Error(), "missing2.properties")
+}
+
+type bindingServer struct {
+	Host   string         `value:"server.host"`
+	Port   int            `value:"server.port,default=8080"`
+	Token  string         `value:"server.token"`
+	Region string         `value:"server.region"`
+	Limits map[string]int `value:"server.limits"`
+}
+
+func TestBeanPropertyBindings(t *testing.T) {
+
+	files := fstest.MapFS{
+		"app.properties": &fstest.MapFile{Data: []byte("server.host = localhost\nserver.limits.rps = 10\n")},
+	}
+
+	ctx, err := glue.New(
+		glue.ResourceSource{
+			Name:       "resources",
+			AssetNames: []string{"app.properties"},
+			AssetFiles: http.FS(files),
+		},
+		glue.PropertySource{Path: "resources:app.properties"},
+		glue.PropertySource{Map: map[string]interface{}{"server.token": "secret"}},
+		&onePropertyResolver{key: "server.region", value: "eu"},
+		&bindingServer{},
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	list := ctx.Bean(reflect.TypeOf((*bindingServer)(nil)), glue.DefaultLevel)
+	require.Equal(t, 1, len(list))
+
+	sources := make(map[string]string)
+	for _, b := range list[0].Properties() {
+		sources[b.Field+" "+b.Key] = b.Source
+	}
+
+	require.Equal(t, map[string]string{
+		"Host server.host":       "resources:app.properties",
+		"Port server.port":       glue.DefaultPropertySource,
+		"Token server.token":     glue.MapPropertySource,
+		"Region server.region":   "*glue_test.onePropertyResolver",
+		"Limits server.limits.*": "resources:app.properties",
+	}, sources)
+}
+
+type envFileBean struct {
+	Host     string `value:"DB_HOST"`
+	Password string `value:"DB_PASSWORD"`
+	Cert     string `value:"TLS_CERT"`
+	Greeting string `value:"GREETING"`
+	Port     int    `value:"PORT"`
+}
+
+func TestEnvFilePropertySource(t *testing.T) {
+
+	files := fstest.MapFS{
+		".env": &fstest.MapFile{Data: []byte(`# local development
+export DB_HOST=localhost # inline comment
+DB_PASSWORD='p@ss #word'
+TLS_CERT="-----BEGIN-----
+abc
+-----END-----"
+GREETING="hello \"world\"\n"
+PORT = 8080
+`)},
+	}
+
+	b := new(envFileBean)
+	ctx, err := glue.New(
+		glue.ResourceSource{
+			Name:       "resources",
+			AssetNames: []string{".env"},
+			AssetFiles: http.FS(files),
+		},
+		glue.PropertySource{Path: "resources:.env"},
+		b,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.Equal(t, "localhost", b.Host)
+	require.Equal(t, "p@ss #word", b.Password)
+	require.Equal(t, "-----BEGIN-----\nabc\n-----END-----", b.Cert)
+	require.Equal(t, "hello \"world\"\n", b.Greeting)
+	require.Equal(t, 8080, b.Port)
+}
+
+type fileModeBean struct {
+	Mode os.FileMode `value:"mode"`
+}
+
+func TestFileModeParsing(t *testing.T) {
+
+	p := glue.NewProperties()
+	for value, expected := range map[string]os.FileMode{
+		"0644":       0644,
+		"755":        0755,
+		"0o600":      0600,
+		"4755":       os.ModeSetuid | 0755,
+		"1777":       os.ModeSticky | 0777,
+		"-rw-r--r--": 0644,
+		"rwsr-sr-x":  os.ModeSetuid | os.ModeSetgid | 0755,
+		"drwxrwxrwt": os.ModeDir | os.ModeSticky | 0777,
+		"rwSr--r--":  os.ModeSetuid | 0644,
+		"urwxr-xr-x": os.ModeSetuid | 0755,
+		"r--":        0004,
+	} {
+		p.Set("mode", value)
+		require.Equal(t, expected, p.GetFileMode("mode", 0), value)
+	}
+
+	var handled error
+	p.SetErrorHandler(func(key string, err error) {
+		handled = err
+	})
+	for _, value := range []string{"rwxq-xr-x", "abc", "99999", "xrwxrwxrwx"} {
+		handled = nil
+		p.Set("mode", value)
+		require.Equal(t, os.FileMode(0600), p.GetFileMode("mode", 0600), value)
+		require.Error(t, handled, value)
+	}
+
+	_, err := glue.New(
+		glue.PropertySource{Map: map[string]interface{}{"mode": "rw-rw-rz-"}},
+		&fileModeBean{},
+	)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid file mode")
+}
+
+type numbersBean struct {
+	Hex       int     `value:"num.hex"`
+	Million   int64   `value:"num.million"`
+	Mask      uint16  `value:"num.mask"`
+	Negative  int8    `value:"num.negative"`
+	Zeros     int     `value:"num.zeros"`
+	Precision float32 `value:"num.precision"`
+}
+
+func TestNumberParsing(t *testing.T) {
+
+	numbers := map[string]interface{}{
+		"num.hex":       "0x1F",
+		"num.million":   "1_000_000",
+		"num.mask":      "0b1111_0000",
+		"num.negative":  "-0x80",
+		"num.zeros":     "010",
+		"num.precision": "1.5",
+	}
+
+	b := new(numbersBean)
+	ctx, err := glue.New(
+		glue.PropertySource{Map: numbers},
+		b,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.Equal(t, 31, b.Hex)
+	require.Equal(t, int64(1000000), b.Million)
+	require.Equal(t, uint16(0xF0), b.Mask)
+	require.Equal(t, int8(-128), b.Negative)
+	require.Equal(t, 10, b.Zeros)
+	require.Equal(t, float32(1.5), b.Precision)
+	require.Equal(t, 1000000, ctx.Properties().GetInt("num.million", 0))
+
+	for key, value := range map[string]string{
+		"num.negative":  "200",
+		"num.mask":      "0x10000",
+		"num.million":   "1__000",
+		"num.precision": "1e39",
+	} {
+		_, err := glue.New(
+			glue.PropertySource{Map: numbers},
+			glue.PropertySource{Map: map[string]interface{}{key: value}},
+			new(numbersBean),
+		)
+		require.Error(t, err, key)
+		require.Contains(t, err.Error(), key)
+	}
+}
+
+type scheduleBean struct {
+	Zone     *time.Location `value:"schedule.zone"`
+	Cron     glue.CronSpec  `value:"schedule.cron"`
+	Daily    glue.CronSpec  `value:"schedule.daily,default=@daily"`
+	Interval glue.CronSpec  `value:"schedule.interval,default=@every 90s"`
+}
+
+func TestLocationAndCronSpec(t *testing.T) {
+
+	b := new(scheduleBean)
+	ctx, err := glue.New(
+		glue.PropertySource{Map: map[string]interface{}{
+			"schedule.zone": "Europe/Berlin",
+			"schedule.cron": "*/15 9-17 * * MON-FRI",
+		}},
+		b,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.Equal(t, "Europe/Berlin", b.Zone.String())
+	require.Equal(t, "*/15 9-17 * * MON-FRI", b.Cron.String())
+
+	// Friday 17:50 in Berlin, next run is on Monday morning
+	friday := time.Date(2023, 3, 3, 17, 50, 0, 0, b.Zone)
+	require.Equal(t, time.Date(2023, 3, 6, 9, 0, 0, 0, b.Zone), b.Cron.Next(friday))
+	require.Equal(t, time.Date(2023, 3, 3, 9, 15, 0, 0, b.Zone), b.Cron.Next(time.Date(2023, 3, 3, 9, 0, 0, 0, b.Zone)))
+
+	require.Equal(t, time.Date(2023, 3, 4, 0, 0, 0, 0, b.Zone), b.Daily.Next(friday))
+	require.Equal(t, friday.Add(90*time.Second), b.Interval.Next(friday))
+
+	for key, value := range map[string]string{
+		"schedule.zone": "Mars/Olympus",
+		"schedule.cron": "61 * * * *",
+	} {
+		_, err := glue.New(
+			glue.PropertySource{Map: map[string]interface{}{"schedule.zone": "UTC", "schedule.cron": "@hourly"}},
+			glue.PropertySource{Map: map[string]interface{}{key: value}},
+			new(scheduleBean),
+		)
+		require.Error(t, err, key)
+		require.Contains(t, err.Error(), key)
+	}
+}
+
+func TestUnregisterAndScopedResolver(t *testing.T) {
+
+	p := glue.NewProperties()
+
+	one := &onePropertyResolver{key: "a", value: "1"}
+	p.Register(one)
+	require.Equal(t, "1", p.GetString("a", ""))
+	require.True(t, p.Unregister(one))
+	require.False(t, p.Unregister(one))
+	require.Equal(t, "", p.GetString("a", ""))
+
+	// non-comparable resolvers are never matched
+	require.False(t, p.Unregister(nil))
+
+	count := len(p.PropertyResolvers())
+
+	scoped := glue.ScopedResolver("feature.", 0, onePropertyResolver{key: "feature.x", value: "on"})
+	p.Register(scoped)
+	require.Equal(t, "on", p.GetString("feature.x", ""))
+	require.True(t, p.Unregister(scoped))
+
+	outside := glue.ScopedResolver("feature.", 0, onePropertyResolver{key: "other.x", value: "on"})
+	p.Register(outside)
+	require.Equal(t, "", p.GetString("other.x", ""))
+	require.True(t, p.Unregister(outside))
+
+	temporary := glue.ScopedResolver("feature.", 20*time.Millisecond, onePropertyResolver{key: "feature.y", value: "on"})
+	p.Register(temporary)
+	require.Equal(t, "on", p.GetString("feature.y", ""))
+	require.Equal(t, count+1, len(p.PropertyResolvers()))
+
+	time.Sleep(40 * time.Millisecond)
+	require.Equal(t, "", p.GetString("feature.y", ""))
+	require.Equal(t, count, len(p.PropertyResolvers()))
+}
+
+type priorityResolver struct {
+	onePropertyResolver
+	priority int
+}
+
+func (t priorityResolver) Priority() int {
+	return t.priority
+}
+
+func TestExplainProperty(t *testing.T) {
+
+	p := glue.NewProperties()
+	p.Set("db.host", "file-host")
+	p.Register(priorityResolver{onePropertyResolver{key: "db.host", value: "env-host"}, 200})
+	p.Register(onePropertyResolver{key: "db.host", value: "fallback"})
+	p.Register(onePropertyResolver{key: "db.port", value: "5432"})
+
+	steps := p.Explain("db.host")
+	require.Equal(t, 4, len(steps))
+
+	require.Equal(t, "glue_test.priorityResolver", steps[0].Resolver)
+	require.Equal(t, 200, steps[0].Priority)
+	require.True(t, steps[0].Matched)
+	require.True(t, steps[0].Winner)
+	require.Equal(t, "env-host", steps[0].Value)
+
+	require.True(t, strings.HasPrefix(steps[1].Resolver, "Properties{"))
+	require.True(t, steps[1].Matched)
+	require.False(t, steps[1].Winner)
+	require.Equal(t, "file-host", steps[1].Value)
+
+	var unmatched int
+	for _, step := range steps[2:] {
+		if !step.Matched {
+			unmatched++
+		}
+	}
+	require.Equal(t, 1, unmatched)
+
+	p.MarkSensitive("db.host")
+	require.Equal(t, "******", p.Explain("db.host")[0].Value)
+
+	// sub properties explain keys relative to the prefix
+	var port string
+	for _, step := range p.Sub("db").Explain("port") {
+		if step.Winner {
+			port = step.Value
+		}
+	}
+	require.Equal(t, "5432", port)
+}
+
+type defaultsBean struct {
+	Timeout time.Duration `value:"client.timeout"`
+	Size    int           `value:"db.pool.size"`
+}
+
+func TestDefaultsCatalogue(t *testing.T) {
+
+	b := new(defaultsBean)
+	ctx, err := glue.New(
+		glue.Defaults(map[string]string{
+			"client.timeout": "10s",
+			"db.pool.size":   "8",
+		}),
+		glue.PropertySource{Map: map[string]interface{}{"db.pool.size": 16}},
+		b,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.Equal(t, 10*time.Second, b.Timeout)
+	require.Equal(t, 16, b.Size)
+
+	list := ctx.Bean(reflect.TypeOf(b), glue.DefaultLevel)
+	require.Equal(t, 1, len(list))
+	sources := make(map[string]string)
+	for _, binding := range list[0].Properties() {
+		sources[binding.Key] = binding.Source
+	}
+	require.Equal(t, glue.DefaultsPropertySource, sources["client.timeout"])
+	require.Equal(t, glue.MapPropertySource, sources["db.pool.size"])
+
+	steps := ctx.Properties().Explain("db.pool.size")
+	last := steps[len(steps)-1]
+	require.Equal(t, "Defaults{keys=2}", last.Resolver)
+	require.True(t, last.Matched)
+	require.False(t, last.Winner)
+}
+
+func TestPropertyInheritance(t *testing.T) {
+
+	parent, err := glue.New(
+		glue.PropertySource{Map: map[string]interface{}{
+			"app.name":  "parent",
+			"app.level": "global",
+		}},
+	)
+	require.NoError(t, err)
+	defer parent.Close()
+
+	childProperties := glue.PropertySource{Map: map[string]interface{}{
+		"app.name":   "child",
+		"app.tenant": "acme",
+	}}
+
+	shadow, err := parent.Extend(childProperties)
+	require.NoError(t, err)
+	defer shadow.Close()
+
+	require.Equal(t, "child", shadow.Properties().GetString("app.name", ""))
+	require.Equal(t, "global", shadow.Properties().GetString("app.level", ""))
+	require.Equal(t, "acme", shadow.Properties().GetString("app.tenant", ""))
+
+	merge, err := parent.Extend(glue.MergeParentProperties, childProperties)
+	require.NoError(t, err)
+	defer merge.Close()
+
+	require.Equal(t, "parent", merge.Properties().GetString("app.name", ""))
+	require.Equal(t, "global", merge.Properties().GetString("app.level", ""))
+	require.Equal(t, "acme", merge.Properties().GetString("app.tenant", ""))
+
+	isolated, err := parent.Extend(glue.IsolateParentProperties, childProperties)
+	require.NoError(t, err)
+	defer isolated.Close()
+
+	require.Equal(t, "child", isolated.Properties().GetString("app.name", ""))
+	require.Equal(t, "", isolated.Properties().GetString("app.level", ""))
+	require.Equal(t, "acme", isolated.Properties().GetString("app.tenant", ""))
+
+	grandchild, err := isolated.Extend()
+	require.NoError(t, err)
+	defer grandchild.Close()
+
+	require.Equal(t, "child", grandchild.Properties().GetString("app.name", ""))
+	require.Equal(t, "", grandchild.Properties().GetString("app.level", ""))
+}
+
+func TestPushOverlay(t *testing.T) {
+
+	p := glue.NewProperties()
+	p.Set("feature.enabled", "false")
+	p.Set("app.name", "base")
+	p.Register(priorityResolver{onePropertyResolver{key: "app.name", value: "remote"}, 1000})
+
+	pop := p.Push(map[string]string{"feature.enabled": "true", "app.name": "canary"})
+	require.True(t, p.GetBool("feature.enabled", false))
+	require.Equal(t, "canary", p.GetString("app.name", ""))
+
+	popSub := p.Sub("feature").Push(map[string]string{"enabled": "maybe"})
+	require.Equal(t, "maybe", p.GetString("feature.enabled", ""))
+	require.Equal(t, "canary", p.GetString("app.name", ""))
+
+	popSub()
+	require.Equal(t, "true", p.GetString("feature.enabled", ""))
+
+	pop()
+	pop()
+	require.False(t, p.GetBool("feature.enabled", true))
+	require.Equal(t, "remote", p.GetString("app.name", ""))
+	require.Equal(t, "base", p.Map()["app.name"])
+	require.Equal(t, 2, p.Len())
+}
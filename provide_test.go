@@ -0,0 +1,58 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type ProvidedCache interface {
+	Kind() string
+}
+
+type providedCache struct {
+	kind string
+}
+
+func (t *providedCache) Kind() string {
+	return t.kind
+}
+
+func TestProvide(t *testing.T) {
+
+	holder := &struct {
+		Cache  ProvidedCache            `inject`
+		Caches []ProvidedCache          `inject`
+		Named  map[string]ProvidedCache `inject`
+	}{}
+
+	ctx, err := glue.New(
+		glue.PropertySource{Map: map[string]interface{}{glue.ActiveProfilesProperty: "dev, test"}},
+		glue.Provide(&providedCache{kind: "local"}, glue.Name("localCache"), glue.Order(2)),
+		glue.Provide(&providedCache{kind: "memory"}, glue.Name("memoryCache"), glue.Order(1), glue.Primary()),
+		glue.Provide(&providedCache{kind: "redis"}, glue.Name("redisCache"), glue.Profile("prod")),
+		glue.Provide(&providedCache{kind: "mock"}, glue.Name("mockCache"), glue.Order(3), glue.Profile("test")),
+		holder,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.Equal(t, "memory", holder.Cache.Kind())
+
+	require.Equal(t, 3, len(holder.Caches))
+	require.Equal(t, "memory", holder.Caches[0].Kind())
+	require.Equal(t, "local", holder.Caches[1].Kind())
+	require.Equal(t, "mock", holder.Caches[2].Kind())
+
+	require.Equal(t, 3, len(holder.Named))
+	require.Equal(t, "local", holder.Named["localCache"].Kind())
+	_, ok := holder.Named["redisCache"]
+	require.False(t, ok)
+
+	require.Equal(t, 1, len(ctx.Lookup("memoryCache", glue.DefaultLevel)))
+}
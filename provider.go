@@ -0,0 +1,133 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"reflect"
+)
+
+/**
+Constructor function registered by glue.Providers, resolved after scan
+*/
+type provider struct {
+	fn interface{}
+}
+
+/**
+Registers constructor functions in the style of google/wire providers and uber/fx fx.Provide as beans,
+eases incremental migration of applications on to glue contexts: pass the same functions that are listed in wire.NewSet or fx.Provide.
+Parameters of the function are injected from the context, the result is the bean produced by singleton factory.
+
+Supported signatures are 'func(deps...) T', 'func(deps...) (T, error)', 'func(deps...) (T, func())' and 'func(deps...) (T, func(), error)',
+the cleanup function is called on close of the context. Options of fx (fx.Invoke, fx.Decorate) and wire sets are not supported.
+
+Example:
+	glue.New(
+		glue.Providers(
+			NewConfig,
+			NewDatabase,
+			NewUserRepository,
+		),
+	)
+*/
+func Providers(fns ...interface{}) interface{} {
+	list := make([]interface{}, len(fns))
+	for i, fn := range fns {
+		list[i] = &provider{fn: fn}
+	}
+	return list
+}
+
+var errorClass = reflect.TypeOf((*error)(nil)).Elem()
+var cleanupClass = reflect.TypeOf((func())(nil))
+
+/**
+Validates the constructor function and builds the holder of its parameters with 'inject' fields
+*/
+func (t *provider) resolve() (fn reflect.Value, args interface{}, err error) {
+	fn = reflect.ValueOf(t.fn)
+	if !fn.IsValid() || fn.Kind() == reflect.Func && fn.IsNil() {
+		return fn, nil, errors.New("provider must be not nil function")
+	}
+	ft := fn.Type()
+	if ft.Kind() != reflect.Func || ft.IsVariadic() {
+		return fn, nil, errors.Errorf("provider must be not variadic function, but was '%v'", ft)
+	}
+	switch ft.NumOut() {
+	case 1:
+	case 2:
+		if ft.Out(1) != errorClass && ft.Out(1) != cleanupClass {
+			return fn, nil, errors.Errorf("provider '%v' must return error or cleanup function as the second result", ft)
+		}
+	case 3:
+		if ft.Out(1) != cleanupClass || ft.Out(2) != errorClass {
+			return fn, nil, errors.Errorf("provider '%v' must return cleanup function and error as the second and third results", ft)
+		}
+	default:
+		return fn, nil, errors.Errorf("provider '%v' must return the object with optional cleanup function and error", ft)
+	}
+	fields := make([]reflect.StructField, ft.NumIn())
+	for i := range fields {
+		fields[i] = reflect.StructField{
+			Name: fmt.Sprintf("Arg%d", i),
+			Type: ft.In(i),
+			Tag:  `inject:""`,
+		}
+	}
+	return fn, reflect.New(reflect.StructOf(fields)).Interface(), nil
+}
+
+/**
+Factory bean producing the object by the constructor function with injected parameters
+*/
+type providerFactory struct {
+	fn       reflect.Value
+	args     interface{}
+	cleanups []func()
+}
+
+func (t *providerFactory) Object() (interface{}, error) {
+	ft := t.fn.Type()
+	holder := reflect.ValueOf(t.args).Elem()
+	in := make([]reflect.Value, ft.NumIn())
+	for i := range in {
+		in[i] = holder.Field(i)
+	}
+	out := t.fn.Call(in)
+	if last := out[len(out)-1]; last.Type() == errorClass && !last.IsNil() {
+		return nil, errors.Errorf("provider '%v' failed, %v", ft, last.Interface())
+	}
+	if len(out) > 1 && out[1].Type() == cleanupClass && !out[1].IsNil() {
+		t.cleanups = append(t.cleanups, out[1].Interface().(func()))
+	}
+	obj := out[0]
+	if (obj.Kind() == reflect.Ptr || obj.Kind() == reflect.Interface) && obj.IsNil() {
+		return nil, errors.Errorf("provider '%v' returned nil", ft)
+	}
+	return obj.Interface(), nil
+}
+
+func (t *providerFactory) ObjectType() reflect.Type {
+	return t.fn.Type().Out(0)
+}
+
+func (t *providerFactory) ObjectName() string {
+	return ""
+}
+
+func (t *providerFactory) Singleton() bool {
+	return true
+}
+
+func (t *providerFactory) Destroy() error {
+	for j := len(t.cleanups) - 1; j >= 0; j-- {
+		t.cleanups[j]()
+	}
+	t.cleanups = nil
+	return nil
+}
@@ -0,0 +1,94 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"errors"
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type providerConfig struct {
+	Url string
+}
+
+type providerDatabase struct {
+	config *providerConfig
+	closed bool
+}
+
+type providerRepository interface {
+	Database() *providerDatabase
+}
+
+type providerRepositoryImpl struct {
+	db *providerDatabase
+}
+
+func (t *providerRepositoryImpl) Database() *providerDatabase {
+	return t.db
+}
+
+type providerApp struct {
+	Repository providerRepository `inject`
+}
+
+func TestProviders(t *testing.T) {
+
+	var db *providerDatabase
+	app := &providerApp{}
+
+	ctx, err := glue.New(
+		glue.Providers(
+			func(db *providerDatabase) providerRepository {
+				return &providerRepositoryImpl{db: db}
+			},
+			func(config *providerConfig) (*providerDatabase, func(), error) {
+				db = &providerDatabase{config: config}
+				return db, func() { db.closed = true }, nil
+			},
+			func() *providerConfig {
+				return &providerConfig{Url: "db://local"}
+			},
+		),
+		app,
+	)
+	require.NoError(t, err)
+
+	require.NotNil(t, app.Repository)
+	require.Equal(t, db, app.Repository.Database())
+	require.Equal(t, "db://local", db.config.Url)
+	require.False(t, db.closed)
+
+	require.NoError(t, ctx.Close())
+	require.True(t, db.closed)
+}
+
+func TestProvidersError(t *testing.T) {
+
+	_, err := glue.New(
+		glue.Providers(
+			func() (*providerConfig, error) {
+				return nil, errors.New("no config")
+			},
+		),
+		&struct {
+			Config *providerConfig `inject`
+		}{},
+	)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no config")
+
+	_, err = glue.New(glue.Providers("not a function"))
+	require.Error(t, err)
+
+	_, err = glue.New(glue.Providers(nil))
+	require.Error(t, err)
+
+	_, err = glue.New(glue.Providers((func() *providerConfig)(nil)))
+	require.Error(t, err)
+}
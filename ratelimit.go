@@ -0,0 +1,191 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	stdcontext "context"
+	"fmt"
+	"github.com/pkg/errors"
+	"math"
+	"reflect"
+	"sync"
+	"time"
+)
+
+/**
+Returned by RateLimiter.Wait after the limiter is closed with the context
+*/
+var ErrRateLimiterClosed = errors.New("rate limiter is closed")
+
+/**
+Token bucket limiter with the refill goroutine managed by the context, produced by RateLimiters and injected by qualifier.
+Limiter with zero rps is unlimited.
+
+Example:
+	glue.New(
+		glue.PropertySource{Map: map[string]interface{}{"ratelimit.api.rps": 100, "ratelimit.api.burst": 20}},
+		glue.RateLimiters("api"),
+		&handler{},
+	)
+
+	type handler struct {
+		Limiter *glue.RateLimiter `inject:"bean=api"`
+	}
+
+	if err := t.Limiter.Wait(ctx); err != nil {
+		return err
+	}
+*/
+type RateLimiter struct {
+	name   string
+	rps    float64
+	tokens chan struct{}
+	done   chan struct{}
+	once   sync.Once
+}
+
+var rateLimiterClass = reflect.TypeOf((*RateLimiter)(nil))
+
+/**
+Limiter refills one token per interval, the interval can not be shorter than a nanosecond
+*/
+const maxRateLimiterRps = float64(time.Second)
+
+func newRateLimiter(name string, rps float64, burst int) *RateLimiter {
+	t := &RateLimiter{name: name, rps: rps, done: make(chan struct{})}
+	if rps <= 0 {
+		return t
+	}
+	if burst <= 0 {
+		burst = 1
+	}
+	t.tokens = make(chan struct{}, burst)
+	for i := 0; i < burst; i++ {
+		t.tokens <- struct{}{}
+	}
+	go t.refill(time.Duration(float64(time.Second) / rps))
+	return t
+}
+
+func (t *RateLimiter) refill(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			select {
+			case t.tokens <- struct{}{}:
+			default:
+			}
+		case <-t.done:
+			return
+		}
+	}
+}
+
+/**
+Returns name of the limiter
+*/
+func (t *RateLimiter) Name() string {
+	return t.name
+}
+
+/**
+Returns rate of the limiter in tokens per second, zero if unlimited
+*/
+func (t *RateLimiter) Rate() float64 {
+	return t.rps
+}
+
+/**
+Takes the token if available without blocking
+*/
+func (t *RateLimiter) Allow() bool {
+	if t.tokens == nil {
+		return true
+	}
+	select {
+	case <-t.tokens:
+		return true
+	default:
+		return false
+	}
+}
+
+/**
+Blocks until the token is available, ctx is done or the limiter is closed
+*/
+func (t *RateLimiter) Wait(ctx stdcontext.Context) error {
+	if t.tokens == nil {
+		return nil
+	}
+	select {
+	case <-t.tokens:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.done:
+		return ErrRateLimiterClosed
+	}
+}
+
+func (t *RateLimiter) close() {
+	t.once.Do(func() {
+		close(t.done)
+	})
+}
+
+/**
+Registers rate limiters by names, each limiter is configured by properties 'ratelimit.<name>.rps' and 'ratelimit.<name>.burst'
+*/
+func RateLimiters(names ...string) interface{} {
+	list := make([]interface{}, len(names))
+	for i, name := range names {
+		list[i] = &rateLimiterFactory{name: name}
+	}
+	return list
+}
+
+/**
+Factory bean producing the named rate limiter, stops the refill goroutine on close
+*/
+type rateLimiterFactory struct {
+	Properties Properties `inject`
+	name       string
+	limiter    *RateLimiter
+}
+
+func (t *rateLimiterFactory) Object() (interface{}, error) {
+	prefix := fmt.Sprintf("ratelimit.%s.", t.name)
+	rps := t.Properties.GetDouble(prefix+"rps", 0)
+	if rps < 0 {
+		return nil, errors.Errorf("rate limiter '%s' has negative rps %v", t.name, rps)
+	}
+	if math.IsNaN(rps) || rps > maxRateLimiterRps {
+		return nil, errors.Errorf("rate limiter '%s' has rps %v above the limit %v", t.name, rps, maxRateLimiterRps)
+	}
+	t.limiter = newRateLimiter(t.name, rps, t.Properties.GetInt(prefix+"burst", 1))
+	return t.limiter, nil
+}
+
+func (t *rateLimiterFactory) ObjectType() reflect.Type {
+	return rateLimiterClass
+}
+
+func (t *rateLimiterFactory) ObjectName() string {
+	return t.name
+}
+
+func (t *rateLimiterFactory) Singleton() bool {
+	return true
+}
+
+func (t *rateLimiterFactory) Destroy() error {
+	if t.limiter != nil {
+		t.limiter.close()
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"context"
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type limitedHandler struct {
+	Api    *glue.RateLimiter `inject:"bean=api"`
+	Search *glue.RateLimiter `inject:"bean=search"`
+}
+
+func TestRateLimiters(t *testing.T) {
+
+	handler := &limitedHandler{}
+	ctx, err := glue.New(
+		glue.PropertySource{Map: map[string]interface{}{
+			"ratelimit.api.rps":   1000,
+			"ratelimit.api.burst": 2,
+		}},
+		glue.RateLimiters("api", "search"),
+		handler,
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, "api", handler.Api.Name())
+	require.Equal(t, float64(1000), handler.Api.Rate())
+	require.True(t, handler.Api.Allow())
+	require.True(t, handler.Api.Allow())
+	require.False(t, handler.Api.Allow())
+
+	wait, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, handler.Api.Wait(wait))
+
+	// not configured limiter is unlimited
+	require.Equal(t, "search", handler.Search.Name())
+	for i := 0; i < 10; i++ {
+		require.True(t, handler.Search.Allow())
+	}
+
+	require.NoError(t, ctx.Close())
+	for handler.Api.Allow() {
+	}
+	require.Equal(t, glue.ErrRateLimiterClosed, handler.Api.Wait(context.Background()))
+
+	_, err = glue.New(
+		glue.PropertySource{Map: map[string]interface{}{
+			"ratelimit.api.rps": 2e9,
+		}},
+		glue.RateLimiters("api"),
+		&struct {
+			Api *glue.RateLimiter `inject:"bean=api"`
+		}{},
+	)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "above the limit")
+}
@@ -0,0 +1,167 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"github.com/pkg/errors"
+	"reflect"
+	"sync/atomic"
+)
+
+func (t *context) Refresh() error {
+
+	t.constructMu.Lock()
+	defer t.constructMu.Unlock()
+
+	/**
+	Find beans with changed properties and their dependents in dependency order
+	*/
+	changed := make(map[*bean]bool)
+	var list []*bean
+	for _, b := range t.refreshOrder() {
+		propertiesChanged, err := t.propertiesChanged(b)
+		if err != nil {
+			return err
+		}
+		dependencyChanged := false
+		for _, dep := range b.dependencies {
+			if changed[dep] {
+				dependencyChanged = true
+				break
+			}
+		}
+		failed := t.refreshFailed[b]
+		if !propertiesChanged && !dependencyChanged && !failed {
+			continue
+		}
+		if _, ok := b.obj.(Consumer); ok {
+			return errors.Errorf("can not refresh consumer '%s' running in the context", b.name)
+		}
+		if t.logger() != nil {
+			t.logger().Printf("Refresh Bean '%s' with type '%v', propertiesChanged=%v, dependencyChanged=%v, failed=%v\n", b.name, b.beanDef.classPtr, propertiesChanged, dependencyChanged, failed)
+		}
+		changed[b] = true
+		list = append(list, b)
+	}
+
+	/**
+	Destroy dependents before their dependencies, beans failed on the previous refresh are destroyed already
+	*/
+	var listErr []error
+	for j := len(list) - 1; j >= 0; j-- {
+		b := list[j]
+		if t.refreshFailed[b] {
+			continue
+		}
+		b.setLifecycle(BeanDestroying)
+		if err := destroyObject(b.obj, RefreshReason); err != nil {
+			listErr = append(listErr, errors.Errorf("destroy bean '%s' on refresh failed, %v", b.name, err))
+		}
+	}
+
+	/**
+	Inject properties and construct in dependency order, failed beans are tried again by the next refresh
+	*/
+	for _, b := range list {
+		if err := t.reconstructBean(b); err != nil {
+			if t.refreshFailed == nil {
+				t.refreshFailed = make(map[*bean]bool)
+			}
+			t.refreshFailed[b] = true
+			listErr = append(listErr, err)
+			continue
+		}
+		delete(t.refreshFailed, b)
+	}
+
+	return multipleErr(listErr)
+}
+
+/**
+Returns initialized beans and beans failed on the previous refresh of the current context not produced by factories, dependencies go first
+*/
+func (t *context) refreshOrder() []*bean {
+	owned := make(map[*bean]bool)
+	var list []*bean
+	t.eachBean(func(b Bean) bool {
+		impl, ok := b.(*bean)
+		if ok && (impl.Lifecycle() == BeanInitialized || t.refreshFailed[impl]) && impl.beenFactory == nil && impl.obj != interface{}(t) {
+			owned[impl] = true
+			list = append(list, impl)
+		}
+		return true
+	})
+	visited := make(map[*bean]bool)
+	var order []*bean
+	var visit func(b *bean)
+	visit = func(b *bean) {
+		if visited[b] || !owned[b] {
+			return
+		}
+		visited[b] = true
+		for _, dep := range b.dependencies {
+			visit(dep)
+		}
+		order = append(order, b)
+	}
+	for _, b := range list {
+		visit(b)
+	}
+	return order
+}
+
+/**
+Resolves properties of the bean in to the new value and compares it with injected fields
+*/
+func (t *context) propertiesChanged(b *bean) (bool, error) {
+	if len(b.beanDef.properties) == 0 {
+		return false, nil
+	}
+	current := b.valuePtr.Elem()
+	fresh := reflect.New(current.Type()).Elem()
+	properties := b.scopedProperties(t.properties)
+	for _, propertyDef := range b.beanDef.properties {
+		if err := propertyDef.inject(&fresh, properties); err != nil {
+			return false, errors.Errorf("property '%s' resolution in bean '%s' on refresh failed, %v", b.propertyPrefix+propertyDef.propertyName, b.name, err)
+		}
+		if !reflect.DeepEqual(fresh.Field(propertyDef.fieldNum).Interface(), current.Field(propertyDef.fieldNum).Interface()) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+/**
+Injects current properties in to the destroyed bean and calls PostConstruct
+*/
+func (t *context) reconstructBean(b *bean) error {
+	b.ctorMu.Lock()
+	defer b.ctorMu.Unlock()
+
+	b.setLifecycle(BeanConstructing)
+	atomic.StoreInt32(&b.postConstructed, 0)
+
+	if len(b.beanDef.properties) > 0 {
+		value := b.valuePtr.Elem()
+		properties := b.scopedProperties(t.properties)
+		for _, propertyDef := range b.beanDef.properties {
+			if err := propertyDef.inject(&value, properties); err != nil {
+				return errors.Errorf("property '%s' injection in bean '%s' on refresh failed, %v", b.propertyPrefix+propertyDef.propertyName, b.name, err)
+			}
+		}
+		b.bindings.Store(t.propertyBindings(b))
+	}
+
+	if init, ok := b.obj.(InitializingBean); ok {
+		if err := init.PostConstruct(); err != nil {
+			return errors.Errorf("post construct of bean '%s' on refresh failed, %v", b.name, err)
+		}
+	}
+
+	atomic.StoreInt32(&b.postConstructed, 1)
+	b.setLifecycle(BeanInitialized)
+	return nil
+}
@@ -0,0 +1,168 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"errors"
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"reflect"
+	"testing"
+)
+
+type refreshEvents struct {
+	list []string
+}
+
+type refreshPool struct {
+	Events *refreshEvents `inject`
+	Size   int            `value:"pool.size,default=4"`
+	reason glue.CloseReason
+}
+
+func (t *refreshPool) PostConstruct() error {
+	t.Events.list = append(t.Events.list, "construct pool")
+	return nil
+}
+
+func (t *refreshPool) DestroyWithReason(reason glue.CloseReason) error {
+	t.reason = reason
+	t.Events.list = append(t.Events.list, "destroy pool")
+	return nil
+}
+
+type refreshService struct {
+	Events *refreshEvents `inject`
+	Pool   *refreshPool   `inject`
+}
+
+func (t *refreshService) PostConstruct() error {
+	t.Events.list = append(t.Events.list, "construct service")
+	return nil
+}
+
+func (t *refreshService) Destroy() error {
+	t.Events.list = append(t.Events.list, "destroy service")
+	return nil
+}
+
+type refreshCache struct {
+	Events *refreshEvents `inject`
+	Ttl    string         `value:"cache.ttl,default=1m"`
+}
+
+func (t *refreshCache) PostConstruct() error {
+	t.Events.list = append(t.Events.list, "construct cache")
+	return nil
+}
+
+func TestRefresh(t *testing.T) {
+
+	events := &refreshEvents{}
+	pool := &refreshPool{}
+	cache := &refreshCache{}
+
+	ctx, err := glue.New(events, &refreshService{}, pool, cache)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	events.list = nil
+	require.NoError(t, ctx.Refresh())
+	require.Nil(t, events.list)
+
+	ctx.Properties().Set("pool.size", "16")
+	require.NoError(t, ctx.Refresh())
+
+	require.Equal(t, 16, pool.Size)
+	require.Equal(t, glue.RefreshReason, pool.reason)
+	require.Equal(t, []string{"destroy service", "destroy pool", "construct pool", "construct service"}, events.list)
+
+	events.list = nil
+	require.NoError(t, ctx.Refresh())
+	require.Nil(t, events.list)
+
+	ctx.Properties().Set("pool.size", "oops")
+	require.Error(t, ctx.Refresh())
+	require.Nil(t, events.list)
+}
+
+type refreshConn struct {
+	Events *refreshEvents `inject`
+	Url    string         `value:"conn.url,default=db"`
+}
+
+func (t *refreshConn) PostConstruct() error {
+	if t.Url == "broken" {
+		return errors.New("connection refused")
+	}
+	t.Events.list = append(t.Events.list, "construct conn")
+	return nil
+}
+
+func (t *refreshConn) Destroy() error {
+	t.Events.list = append(t.Events.list, "destroy conn")
+	return nil
+}
+
+type refreshRepo struct {
+	Events *refreshEvents `inject`
+	Conn   *refreshConn   `inject`
+}
+
+func (t *refreshRepo) PostConstruct() error {
+	t.Events.list = append(t.Events.list, "construct repo")
+	return nil
+}
+
+func (t *refreshRepo) Destroy() error {
+	t.Events.list = append(t.Events.list, "destroy repo")
+	return nil
+}
+
+func TestRefreshFailure(t *testing.T) {
+
+	events := &refreshEvents{}
+	conn := &refreshConn{}
+	repo := &refreshRepo{}
+	cache := &refreshCache{}
+
+	ctx, err := glue.New(events, conn, repo, cache)
+	require.NoError(t, err)
+
+	events.list = nil
+	ctx.Properties().Set("conn.url", "broken")
+	ctx.Properties().Set("cache.ttl", "5m")
+	err = ctx.Refresh()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "connection refused")
+
+	require.Contains(t, events.list, "construct repo")
+	require.Contains(t, events.list, "construct cache")
+	require.Equal(t, "5m", cache.Ttl)
+	require.Equal(t, glue.BeanConstructing, ctx.Bean(reflect.TypeOf(conn), glue.DefaultLevel)[0].Lifecycle())
+	require.Equal(t, glue.BeanInitialized, ctx.Bean(reflect.TypeOf(repo), glue.DefaultLevel)[0].Lifecycle())
+
+	events.list = nil
+	ctx.Properties().Set("conn.url", "db")
+	require.NoError(t, ctx.Refresh())
+	require.Equal(t, []string{"destroy repo", "construct conn", "construct repo"}, events.list)
+	require.Equal(t, glue.BeanInitialized, ctx.Bean(reflect.TypeOf(conn), glue.DefaultLevel)[0].Lifecycle())
+
+	events.list = nil
+	require.NoError(t, ctx.Close())
+	require.Contains(t, events.list, "destroy conn")
+
+	// failed bean is not destroyed on close
+	events = &refreshEvents{}
+	ctx, err = glue.New(events, &refreshConn{}, &refreshRepo{})
+	require.NoError(t, err)
+	ctx.Properties().Set("conn.url", "broken")
+	require.Error(t, ctx.Refresh())
+
+	events.list = nil
+	require.NoError(t, ctx.Close())
+	require.Equal(t, []string{"destroy repo"}, events.list)
+}
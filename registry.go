@@ -8,7 +8,11 @@ package glue
 import (
 	"github.com/pkg/errors"
 	"net/http"
+	"path"
 	"reflect"
+	"regexp"
+	"sort"
+	"strings"
 	"sync"
 )
 
@@ -73,6 +77,54 @@ func (t *registry) findByName(name string) ([]*bean, bool) {
 	return list, ok
 }
 
+/**
+Returns beans with names matching the function in order of names, each bean once
+*/
+func (t *registry) findByPattern(match func(string) bool) []*bean {
+	t.RLock()
+	defer t.RUnlock()
+	var names []string
+	for name := range t.beansByName {
+		if match(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	var list []*bean
+	visited := make(map[*bean]bool)
+	for _, name := range names {
+		for _, b := range t.beansByName[name] {
+			if !visited[b] {
+				visited[b] = true
+				list = append(list, b)
+			}
+		}
+	}
+	return list
+}
+
+const regexpPatternPrefix = "regexp:"
+
+/**
+Compiles the name pattern, glob by default ('storage.*') or regular expression with 'regexp:' prefix
+*/
+func namePattern(pattern string) (func(string) bool, error) {
+	if strings.HasPrefix(pattern, regexpPatternPrefix) {
+		re, err := regexp.Compile(pattern[len(regexpPatternPrefix):])
+		if err != nil {
+			return nil, err
+		}
+		return re.MatchString, nil
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, errors.Errorf("invalid pattern '%s', %v", pattern, err)
+	}
+	return func(name string) bool {
+		ok, _ := path.Match(pattern, name)
+		return ok
+	}, nil
+}
+
 func (t *registry) findResource(source, name string) (Resource, bool) {
 	t.RLock()
 	defer t.RUnlock()
@@ -101,6 +153,23 @@ func (t *registry) addBeanList(ifaceType reflect.Type, list []*bean) {
 	}
 }
 
+/**
+Cache the list of beans for the type if it was not cached yet, returns the cached list.
+Concurrent lookups of the same type store it once.
+*/
+func (t *registry) cacheBeanList(ifaceType reflect.Type, list []*bean) []*bean {
+	t.Lock()
+	defer t.Unlock()
+	if cached, ok := t.beansByType[ifaceType]; ok {
+		return cached
+	}
+	t.beansByType[ifaceType] = append([]*bean{}, list...)
+	for _, b := range list {
+		t.beansByName[b.name] = append(t.beansByName[b.name], b)
+	}
+	return list
+}
+
 func (t *registry) addBean(ifaceType reflect.Type, b *bean) {
 	t.Lock()
 	defer t.Unlock()
@@ -108,6 +177,79 @@ func (t *registry) addBean(ifaceType reflect.Type, b *bean) {
 	t.beansByName[b.name] = append(t.beansByName[b.name], b)
 }
 
+/**
+Replace the old bean by the new one, the new bean is kept only for types it matches
+*/
+func (t *registry) replace(old, b *bean) {
+	t.Lock()
+	defer t.Unlock()
+	for typ, list := range t.beansByType {
+		for i, item := range list {
+			if item == old {
+				c := make([]*bean, 0, len(list))
+				c = append(c, list[:i]...)
+				if b.matches(typ) {
+					c = append(c, b)
+				}
+				t.beansByType[typ] = append(c, list[i+1:]...)
+				break
+			}
+		}
+	}
+	if list, ok := t.beansByName[old.name]; ok {
+		t.beansByName[old.name] = removeBean(list, old)
+		if len(t.beansByName[old.name]) == 0 {
+			delete(t.beansByName, old.name)
+		}
+		t.beansByName[b.name] = append(t.beansByName[b.name], b)
+	}
+}
+
+/**
+Remove the bean from cached types and names
+*/
+func (t *registry) remove(b *bean) {
+	t.Lock()
+	defer t.Unlock()
+	for typ, list := range t.beansByType {
+		t.beansByType[typ] = removeBean(list, b)
+	}
+	if list, ok := t.beansByName[b.name]; ok {
+		t.beansByName[b.name] = removeBean(list, b)
+		if len(t.beansByName[b.name]) == 0 {
+			delete(t.beansByName, b.name)
+		}
+	}
+}
+
+/**
+Add the bean to cached types it matches, not cached types are resolved from core beans on lookup
+*/
+func (t *registry) promote(b *bean) {
+	t.Lock()
+	defer t.Unlock()
+	var cached bool
+	for typ, list := range t.beansByType {
+		if b.matches(typ) {
+			t.beansByType[typ] = append(list, b)
+			cached = true
+		}
+	}
+	if cached {
+		t.beansByName[b.name] = append(t.beansByName[b.name], b)
+	}
+}
+
+/**
+Drop all cached beans, resource sources stay
+*/
+func (t *registry) clear() {
+	t.Lock()
+	defer t.Unlock()
+	t.beansByType = make(map[reflect.Type][]*bean)
+	t.beansByName = make(map[string][]*bean)
+}
+
 func (t *registry) addResourceSource(other *ResourceSource) error {
 	t.Lock()
 	defer t.Unlock()
@@ -0,0 +1,73 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"reflect"
+)
+
+/**
+Option of the context that clears injected fields (pointers, interfaces, slices, maps, functions) of beans after the dispose phase
+and drops references of the context on beans, so large graphs become collectible promptly even if application code retains the context object.
+Beans must not be used after close of the context with this option, the option is inherited by contexts created by Extend.
+
+Example:
+	ctx, err := glue.New(
+		glue.ReleaseOnClose{},
+		&service{},
+	)
+*/
+type ReleaseOnClose struct {
+}
+
+func (ReleaseOnClose) applyOption(ctx *context) {
+	ctx.releaseOnClose = true
+}
+
+/**
+Clears injected fields of beans scanned in the context and references of the context on beans
+*/
+func (t *context) release() {
+	for _, list := range t.coreBeans() {
+		for _, b := range list {
+			if b.beenFactory == nil && b.obj != interface{}(t) {
+				b.releaseFields()
+			}
+			b.dependencies = nil
+			b.factoryDependencies = nil
+		}
+	}
+	t.coreMu.Lock()
+	t.core.Store(make(map[reflect.Type][]*bean))
+	t.coreMu.Unlock()
+	t.registry.clear()
+	t.disposables = nil
+	t.injections = nil
+	t.auditLog = nil
+	t.runtimeCache.Range(func(key, value interface{}) bool {
+		t.runtimeCache.Delete(key)
+		return true
+	})
+}
+
+/**
+Sets injected fields of the bean to zero values
+*/
+func (t *bean) releaseFields() {
+	if t.beanDef == nil || !t.valuePtr.IsValid() || t.valuePtr.Kind() != reflect.Ptr || t.valuePtr.IsNil() {
+		return
+	}
+	value := t.valuePtr.Elem()
+	if value.Kind() != reflect.Struct {
+		return
+	}
+	for _, def := range t.beanDef.fields {
+		field := value.Field(def.fieldNum)
+		if field.CanSet() {
+			field.Set(reflect.Zero(field.Type()))
+		}
+	}
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"reflect"
+	"testing"
+)
+
+type releaseStore struct {
+	destroyed bool
+}
+
+func (t *releaseStore) Destroy() error {
+	t.destroyed = true
+	return nil
+}
+
+type releaseService struct {
+	Store  *releaseStore            `inject`
+	Stores []*releaseStore          `inject`
+	ByName map[string]*releaseStore `inject`
+	name   string
+}
+
+func TestReleaseOnClose(t *testing.T) {
+
+	store := &releaseStore{}
+	srv := &releaseService{name: "orders"}
+	ctx, err := glue.New(glue.ReleaseOnClose{}, store, srv)
+	require.NoError(t, err)
+	require.NotNil(t, srv.Store)
+	require.Equal(t, 1, len(srv.Stores))
+
+	require.NoError(t, ctx.Close())
+	require.True(t, store.destroyed)
+	require.Nil(t, srv.Store)
+	require.Nil(t, srv.Stores)
+	require.Nil(t, srv.ByName)
+	require.Equal(t, "orders", srv.name)
+	require.Empty(t, ctx.Bean(reflect.TypeOf(srv), glue.DefaultLevel))
+
+	srv = &releaseService{}
+	ctx, err = glue.New(&releaseStore{}, srv)
+	require.NoError(t, err)
+	require.NoError(t, ctx.Close())
+	require.NotNil(t, srv.Store)
+}
@@ -0,0 +1,151 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"github.com/pkg/errors"
+	"os"
+	ossignal "os/signal"
+	"reflect"
+	"sort"
+	"syscall"
+)
+
+/**
+This interface used by beans that apply changed configuration in place, like log levels or limits,
+context notifies them by ReloadProperties after beans with changed properties got refreshed.
+Beans implementing OrderedBean are notified first by BeanOrder.
+
+Example:
+	type limiter struct {
+		Properties glue.Properties `inject`
+		rate int64
+	}
+
+	func (t *limiter) RefreshProperties(keys []string) error {
+		atomic.StoreInt64(&t.rate, int64(t.Properties.GetInt("limiter.rate", 100)))
+		return nil
+	}
+*/
+var RefreshableBeanClass = reflect.TypeOf((*RefreshableBean)(nil)).Elem()
+
+type RefreshableBean interface {
+
+	/**
+	Called with sorted keys of properties changed by reload
+	*/
+	RefreshProperties(keys []string) error
+}
+
+/**
+Option of the context that reloads properties on the signal, SIGHUP by default, the conventional way to reconfigure daemons.
+The context calls ReloadProperties in the background goroutine until it starts closing, errors are passed to OnError or logged as warnings.
+The option is not inherited by contexts created by Extend.
+
+Example:
+	ctx, err := glue.New(
+		glue.ReloadOnSignal{},
+		glue.ResourceSource{Name: "etc", AssetNames: []string{"application.yaml"}, AssetFiles: http.Dir("/etc/app")},
+		glue.PropertySource{Path: "etc:application.yaml"},
+		&server{},
+	)
+*/
+type ReloadOnSignal struct {
+
+	/**
+	Signals triggering the reload, syscall.SIGHUP by default
+	*/
+	Signals []os.Signal
+
+	/**
+	Receives errors of the reload
+	*/
+	OnError func(err error)
+}
+
+func (t ReloadOnSignal) applyOption(ctx *context) {
+	if len(t.Signals) == 0 {
+		t.Signals = []os.Signal{syscall.SIGHUP}
+	}
+	ctx.reloadOnSignal = &t
+}
+
+func (t *context) ReloadProperties() error {
+
+	t.reloadMu.Lock()
+	defer t.reloadMu.Unlock()
+
+	before := t.properties.Map()
+	if err := t.loadProperties(t.propertySources); err != nil {
+		return errors.Errorf("reload of properties failed, %v", err)
+	}
+
+	var keys []string
+	for key, value := range t.properties.Map() {
+		if prev, ok := before[key]; !ok || prev != value {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	sort.Strings(keys)
+
+	if t.logger() != nil {
+		t.logger().Printf("Reload properties, changed %v\n", keys)
+	}
+
+	var listErr []error
+	if err := t.Refresh(); err != nil {
+		listErr = append(listErr, err)
+	}
+
+	var list []*bean
+	t.eachBean(func(b Bean) bool {
+		if impl, ok := b.(*bean); ok && impl.Lifecycle() == BeanInitialized {
+			if _, ok := impl.obj.(RefreshableBean); ok {
+				list = append(list, impl)
+			}
+		}
+		return true
+	})
+	for _, b := range orderBeans(list, t.logger()) {
+		if err := b.obj.(RefreshableBean).RefreshProperties(keys); err != nil {
+			listErr = append(listErr, errors.Errorf("refresh properties of bean '%s' failed, %v", b.name, err))
+		}
+	}
+
+	return multipleErr(listErr)
+}
+
+/**
+Starts the goroutine reloading properties on signals until the context starts closing
+*/
+func (t *context) watchReloadSignals() {
+	if t.reloadOnSignal == nil {
+		return
+	}
+	opt := t.reloadOnSignal
+	ch := make(chan os.Signal, 1)
+	ossignal.Notify(ch, opt.Signals...)
+	go func() {
+		defer ossignal.Stop(ch)
+		for {
+			select {
+			case sig := <-ch:
+				if err := t.ReloadProperties(); err != nil {
+					if opt.OnError != nil {
+						opt.OnError(err)
+					} else {
+						warnf("Reload of properties on signal '%v' failed, %v\n", sig, err)
+					}
+				}
+			case <-t.shutdown.ch:
+				return
+			}
+		}
+	}()
+}
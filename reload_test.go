@@ -8,8 +8,13 @@ package glue_test
 import (
 	"github.com/codeallergy/glue"
 	"github.com/stretchr/testify/require"
+	"net/http"
+	"os"
 	"reflect"
+	"sync"
+	"syscall"
 	"testing"
+	"time"
 )
 
 var reloadableBeanClass = reflect.TypeOf((*reloadableBean)(nil))
@@ -66,3 +71,94 @@ func TestBeanReload(t *testing.T) {
 	require.True(t, tBean.ReloadableBean == reBean)
 
 }
+
+type refreshLimiter struct {
+	keys chan []string
+}
+
+func (t *refreshLimiter) RefreshProperties(keys []string) error {
+	t.keys <- keys
+	return nil
+}
+
+func TestReloadProperties(t *testing.T) {
+
+	file := &oneFile{name: "app.properties", content: "pool.size=8\n"}
+	events := &refreshEvents{}
+	pool := &refreshPool{}
+	limiter := &refreshLimiter{keys: make(chan []string, 1)}
+
+	ctx, err := glue.New(
+		glue.ResourceSource{
+			Name:       "resources",
+			AssetNames: []string{"app.properties"},
+			AssetFiles: file,
+		},
+		glue.PropertySource{Path: "resources:app.properties"},
+		events, &refreshService{}, pool, limiter,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+	require.Equal(t, 8, pool.Size)
+
+	events.list = nil
+	require.NoError(t, ctx.ReloadProperties())
+	require.Nil(t, events.list)
+	require.Equal(t, 0, len(limiter.keys))
+
+	file.content = "pool.size=32\nlimiter.rate=5\n"
+	require.NoError(t, ctx.ReloadProperties())
+	require.Equal(t, 32, pool.Size)
+	require.Equal(t, []string{"destroy service", "destroy pool", "construct pool", "construct service"}, events.list)
+	require.Equal(t, []string{"limiter.rate", "pool.size"}, <-limiter.keys)
+}
+
+type reloadFile struct {
+	sync.Mutex
+	content string
+}
+
+func (t *reloadFile) set(content string) {
+	t.Lock()
+	t.content = content
+	t.Unlock()
+}
+
+func (t *reloadFile) Open(name string) (http.File, error) {
+	t.Lock()
+	defer t.Unlock()
+	return oneFile{name: "app.properties", content: t.content}.Open(name)
+}
+
+func TestReloadOnSignal(t *testing.T) {
+
+	file := &reloadFile{content: "pool.size=8\n"}
+	limiter := &refreshLimiter{keys: make(chan []string, 1)}
+
+	ctx, err := glue.New(
+		glue.ReloadOnSignal{OnError: func(err error) { t.Error(err) }},
+		glue.ResourceSource{
+			Name:       "resources",
+			AssetNames: []string{"app.properties"},
+			AssetFiles: file,
+		},
+		glue.PropertySource{Path: "resources:app.properties"},
+		limiter,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	file.set("pool.size=64\n")
+	process, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+	if err := process.Signal(syscall.SIGHUP); err != nil {
+		t.Skipf("signals are not supported, %v", err)
+	}
+	select {
+	case keys := <-limiter.keys:
+		require.Equal(t, []string{"pool.size"}, keys)
+		require.Equal(t, "64", ctx.Properties().GetString("pool.size", ""))
+	case <-time.After(5 * time.Second):
+		t.Fatal("properties are not reloaded on SIGHUP")
+	}
+}
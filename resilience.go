@@ -0,0 +1,152 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	stdcontext "context"
+	"fmt"
+	"github.com/pkg/errors"
+	"time"
+)
+
+/**
+Returned by Resilient.Call when the circuit is open after repeated failures
+*/
+var ErrCircuitOpen = errors.New("circuit is open")
+
+/**
+Retry, timeout and circuit breaking policy of calls to the bean, read from properties by ResiliencePolicyOf
+*/
+type ResiliencePolicy struct {
+
+	/**
+	Number of attempts of the call, includes the first one
+	*/
+	Attempts int
+
+	/**
+	Delay before the second attempt, doubled for every next one
+	*/
+	Backoff time.Duration
+
+	/**
+	Upper limit of the delay between attempts, not limited if zero
+	*/
+	MaxBackoff time.Duration
+
+	/**
+	Timeout of each attempt, not limited if zero
+	*/
+	Timeout time.Duration
+
+	/**
+	Number of consecutive failed calls that opens the circuit, disabled if zero
+	*/
+	FailureThreshold int
+
+	/**
+	Time the circuit stays open before the next trial call
+	*/
+	OpenTimeout time.Duration
+}
+
+/**
+Reads the policy of the bean from properties 'resilience.<name>.*':
+attempts, backoff, max.backoff, timeout, breaker.failures and breaker.open.timeout.
+*/
+func ResiliencePolicyOf(properties Properties, name string) ResiliencePolicy {
+	prefix := fmt.Sprintf("resilience.%s.", name)
+	return ResiliencePolicy{
+		Attempts:         properties.GetInt(prefix+"attempts", 1),
+		Backoff:          properties.GetDuration(prefix+"backoff", 100*time.Millisecond),
+		MaxBackoff:       properties.GetDuration(prefix+"max.backoff", 0),
+		Timeout:          properties.GetDuration(prefix+"timeout", 0),
+		FailureThreshold: properties.GetInt(prefix+"breaker.failures", 0),
+		OpenTimeout:      properties.GetDuration(prefix+"breaker.open.timeout", time.Minute),
+	}
+}
+
+/**
+Executes calls of the decorated bean with the resilience policy, safe for concurrent use.
+Policies are not applied to beans automatically: the decorator bean is written by hand, it implements the interface of the wrapped bean,
+passes each method through Call and services inject it instead of the wrapped bean.
+
+Example:
+	type resilientPayments struct {
+		Payments   Payments        `inject:"bean=payments"`
+		Properties glue.Properties `inject`
+		resilient  *glue.Resilient
+	}
+
+	func (t *resilientPayments) PostConstruct() error {
+		t.resilient = glue.NewResilient(glue.ResiliencePolicyOf(t.Properties, "payments"))
+		return nil
+	}
+
+	func (t *resilientPayments) Charge(ctx context.Context, amount int) error {
+		return t.resilient.Call(ctx, func(ctx context.Context) error {
+			return t.Payments.Charge(ctx, amount)
+		})
+	}
+*/
+type Resilient struct {
+	policy  ResiliencePolicy
+	circuit factoryCircuit
+}
+
+func NewResilient(policy ResiliencePolicy) *Resilient {
+	return &Resilient{policy: policy}
+}
+
+/**
+Returns the policy of the executor
+*/
+func (t *Resilient) Policy() ResiliencePolicy {
+	return t.policy
+}
+
+func (t *Resilient) breaker() FactoryRetryPolicy {
+	return FactoryRetryPolicy{FailureThreshold: t.policy.FailureThreshold, OpenTimeout: t.policy.OpenTimeout}
+}
+
+/**
+Calls fn with retries, timeout of each attempt and circuit breaking, stops retries when ctx is done
+*/
+func (t *Resilient) Call(ctx stdcontext.Context, fn func(ctx stdcontext.Context) error) error {
+
+	breaker := t.breaker()
+	if !t.circuit.allow(breaker) {
+		return ErrCircuitOpen
+	}
+
+	backoff := t.policy.Backoff
+	for attempt := 1; ; attempt++ {
+		err := t.attempt(ctx, fn)
+		if err == nil || attempt >= t.policy.Attempts {
+			t.circuit.report(breaker, err)
+			return err
+		}
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			t.circuit.report(breaker, err)
+			return err
+		}
+		backoff *= 2
+		if t.policy.MaxBackoff > 0 && backoff > t.policy.MaxBackoff {
+			backoff = t.policy.MaxBackoff
+		}
+	}
+}
+
+func (t *Resilient) attempt(ctx stdcontext.Context, fn func(ctx stdcontext.Context) error) error {
+	if t.policy.Timeout <= 0 {
+		return fn(ctx)
+	}
+	ctx, cancel := stdcontext.WithTimeout(ctx, t.policy.Timeout)
+	defer cancel()
+	return fn(ctx)
+}
@@ -0,0 +1,91 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"context"
+	"errors"
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type payments interface {
+	Charge(ctx context.Context, amount int) error
+}
+
+type flakyPayments struct {
+	calls int
+	fails int
+}
+
+func (t *flakyPayments) Charge(ctx context.Context, amount int) error {
+	t.calls++
+	if t.calls <= t.fails {
+		return errors.New("gateway timeout")
+	}
+	return nil
+}
+
+type resilientPayments struct {
+	Payments   *flakyPayments  `inject`
+	Properties glue.Properties `inject`
+	resilient  *glue.Resilient
+}
+
+func (t *resilientPayments) PostConstruct() error {
+	t.resilient = glue.NewResilient(glue.ResiliencePolicyOf(t.Properties, "payments"))
+	return nil
+}
+
+func (t *resilientPayments) Charge(ctx context.Context, amount int) error {
+	return t.resilient.Call(ctx, func(ctx context.Context) error {
+		return t.Payments.Charge(ctx, amount)
+	})
+}
+
+func TestResilientDecorator(t *testing.T) {
+
+	p := glue.PropertySource{Map: map[string]interface{}{
+		"resilience.payments.attempts":             3,
+		"resilience.payments.backoff":              "1ms",
+		"resilience.payments.breaker.failures":     1,
+		"resilience.payments.breaker.open.timeout": "1h",
+	}}
+
+	flaky := &flakyPayments{fails: 2}
+	decorator := &resilientPayments{}
+
+	ctx, err := glue.New(p, flaky, decorator)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	policy := decorator.resilient.Policy()
+	require.Equal(t, 3, policy.Attempts)
+	require.Equal(t, time.Hour, policy.OpenTimeout)
+
+	var service payments = decorator
+	require.NoError(t, service.Charge(context.Background(), 10))
+	require.Equal(t, 3, flaky.calls)
+
+	flaky.calls, flaky.fails = 0, 5
+	require.Error(t, service.Charge(context.Background(), 10))
+	require.Equal(t, 3, flaky.calls)
+
+	require.True(t, errors.Is(service.Charge(context.Background(), 10), glue.ErrCircuitOpen))
+	require.Equal(t, 3, flaky.calls)
+}
+
+func TestResilientTimeout(t *testing.T) {
+
+	r := glue.NewResilient(glue.ResiliencePolicy{Attempts: 1, Timeout: 10 * time.Millisecond})
+	err := r.Call(context.Background(), func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	require.Equal(t, context.DeadlineExceeded, err)
+}
@@ -0,0 +1,135 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"github.com/pkg/errors"
+	"log"
+	"reflect"
+	"sync"
+	"time"
+)
+
+/**
+Returned by runtime injection when the circuit of the factory bean is open after repeated failures
+*/
+var ErrFactoryCircuitOpen = errors.New("factory circuit is open")
+
+/**
+Retry and circuit breaking policy of runtime calls of the FactoryBean, used by Context.Inject for non-singleton factories.
+Zero value makes a single attempt without circuit breaking.
+*/
+type FactoryRetryPolicy struct {
+
+	/**
+	Number of attempts to create the object, includes the first one
+	*/
+	Attempts int
+
+	/**
+	Delay before the second attempt, doubled for every next one
+	*/
+	Backoff time.Duration
+
+	/**
+	Upper limit of the delay between attempts, not limited if zero
+	*/
+	MaxBackoff time.Duration
+
+	/**
+	Number of consecutive failed calls that opens the circuit, disabled if zero
+	*/
+	FailureThreshold int
+
+	/**
+	Time the circuit stays open before the next trial call
+	*/
+	OpenTimeout time.Duration
+}
+
+/**
+This interface used by factory beans that need retries with backoff and circuit breaking on runtime injection,
+for example factories creating clients of remote resources.
+*/
+var RetryFactoryBeanClass = reflect.TypeOf((*RetryFactoryBean)(nil)).Elem()
+
+type RetryFactoryBean interface {
+	FactoryBean
+
+	/**
+	Returns retry policy of the factory
+	*/
+	RetryPolicy() FactoryRetryPolicy
+}
+
+/**
+State of the circuit breaker of the factory
+*/
+type factoryCircuit struct {
+	sync.Mutex
+	failures  int
+	openUntil time.Time
+}
+
+func (t *factoryCircuit) allow(policy FactoryRetryPolicy) bool {
+	t.Lock()
+	defer t.Unlock()
+	if policy.FailureThreshold <= 0 || t.failures < policy.FailureThreshold {
+		return true
+	}
+	if time.Now().Before(t.openUntil) {
+		return false
+	}
+	// half-open, let the trial call go and open again on failure
+	t.openUntil = time.Now().Add(policy.OpenTimeout)
+	return true
+}
+
+func (t *factoryCircuit) report(policy FactoryRetryPolicy, err error) {
+	t.Lock()
+	defer t.Unlock()
+	if err == nil {
+		t.failures = 0
+		return
+	}
+	t.failures++
+	if policy.FailureThreshold > 0 && t.failures >= policy.FailureThreshold {
+		t.openUntil = time.Now().Add(policy.OpenTimeout)
+	}
+}
+
+/**
+Creates the object by factory on runtime injection applying retry policy of the factory
+*/
+func (t *factory) ctorWithRetry(logger *log.Logger) (*bean, bool, error) {
+
+	retryFactory, ok := t.factoryBean.(RetryFactoryBean)
+	if !ok {
+		return t.ctor()
+	}
+	policy := retryFactory.RetryPolicy()
+
+	if !t.circuit.allow(policy) {
+		return nil, false, errors.Wrapf(ErrFactoryCircuitOpen, "factory bean '%v'", t.factoryClassPtr)
+	}
+
+	backoff := policy.Backoff
+	for attempt := 1; ; attempt++ {
+		b, created, err := t.ctor()
+		if err == nil || attempt >= policy.Attempts {
+			t.circuit.report(policy, err)
+			return b, created, err
+		}
+		if logger != nil {
+			logger.Printf("Factory bean '%v' attempt %d failed, retry in %v, %v\n", t.factoryClassPtr, attempt, backoff, err)
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+		if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	stdcontext "context"
+	"github.com/pkg/errors"
+	"reflect"
+)
+
+/**
+This interface used by beans running one-shot startup tasks like seeding of data and priming of caches.
+Context runs initialized runners of the current context one by one after it is ready and consumers are started, before New or Extend returns.
+Runners implementing OrderedBean go first by BeanOrder, ctx is canceled when the context starts closing.
+If the runner fails, the context is closed and creation returns the error.
+
+Example:
+	type seeder struct {
+		Storage *storage `inject`
+	}
+
+	func (t *seeder) Run(ctx context.Context) error {
+		return t.Storage.Seed(ctx)
+	}
+*/
+var RunnerClass = reflect.TypeOf((*Runner)(nil)).Elem()
+
+type Runner interface {
+
+	/**
+	Runs the startup task
+	*/
+	Run(ctx stdcontext.Context) error
+}
+
+/**
+Runs runners of the current context in order of beans
+*/
+func (t *context) runRunners() error {
+	var list []*bean
+	t.eachBean(func(b Bean) bool {
+		if impl, ok := b.(*bean); ok && impl.Lifecycle() == BeanInitialized {
+			if _, ok := impl.obj.(Runner); ok {
+				list = append(list, impl)
+			}
+		}
+		return true
+	})
+	if len(list) == 0 {
+		return nil
+	}
+
+	ctx, cancel := stdcontext.WithCancel(stdcontext.Background())
+	done := make(chan struct{})
+	defer close(done)
+	defer cancel()
+	go func() {
+		select {
+		case <-t.shutdown.ch:
+			cancel()
+		case <-done:
+		}
+	}()
+
+	for _, b := range orderBeans(list, t.logger()) {
+		if t.logger() != nil {
+			t.logger().Printf("Run runner '%s' with type '%v'\n", b.name, b.beanDef.classPtr)
+		}
+		if err := runRunner(ctx, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runRunner(ctx stdcontext.Context, b *bean) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("runner '%s' with type '%v' recovered with error: %v", b.name, b.beanDef.classPtr, r)
+		}
+	}()
+	if err := b.obj.(Runner).Run(ctx); err != nil {
+		return errors.Errorf("runner '%s' with type '%v' failed, %v", b.name, b.beanDef.classPtr, err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"context"
+	"errors"
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type runLog struct {
+	list      []string
+	destroyed bool
+}
+
+func (t *runLog) Destroy() error {
+	t.destroyed = true
+	return nil
+}
+
+type seedRunner struct {
+	Log   *runLog          `inject`
+	Ready glue.ReadySignal `inject`
+}
+
+func (t *seedRunner) BeanOrder() int {
+	return 2
+}
+
+func (t *seedRunner) Run(ctx context.Context) error {
+	select {
+	case <-t.Ready:
+	default:
+		return errors.New("context is not ready")
+	}
+	t.Log.list = append(t.Log.list, "seed")
+	return nil
+}
+
+type migrateRunner struct {
+	Log *runLog `inject`
+}
+
+func (t *migrateRunner) BeanOrder() int {
+	return 1
+}
+
+func (t *migrateRunner) Run(ctx context.Context) error {
+	t.Log.list = append(t.Log.list, "migrate")
+	return nil
+}
+
+type primeRunner struct {
+	Log  *runLog `inject`
+	fail bool
+}
+
+func (t *primeRunner) Run(ctx context.Context) error {
+	if t.fail {
+		return errors.New("cache is unavailable")
+	}
+	t.Log.list = append(t.Log.list, "prime")
+	return nil
+}
+
+func TestRunner(t *testing.T) {
+
+	log := &runLog{}
+	ctx, err := glue.New(log, &primeRunner{}, &seedRunner{}, &migrateRunner{})
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"migrate", "seed", "prime"}, log.list)
+	require.NoError(t, ctx.Close())
+
+	log = &runLog{}
+	_, err = glue.New(log, &primeRunner{fail: true}, &migrateRunner{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "cache is unavailable")
+	require.Equal(t, []string{"migrate"}, log.list)
+	require.True(t, log.destroyed)
+}
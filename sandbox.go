@@ -0,0 +1,82 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"github.com/pkg/errors"
+	"reflect"
+	"strings"
+)
+
+/**
+Option of the context restricting packages of types that may be registered as beans or injected in to beans of the context,
+prevents plugins loaded in to a child context from wiring in to internal types of the host. Place it first in the scan list,
+the sandbox is inherited by contexts created by Extend.
+
+Package matches the entry if it is equal to it or is a sub-package of it. Deny wins over Allow, empty Allow permits all packages.
+Types of the glue package and types without package (builtin and unnamed types) are always allowed.
+
+Example:
+	host.Extend(
+		glue.Sandbox{
+			Allow: []string{"github.com/acme/host/api", "github.com/acme/plugins"},
+			Deny:  []string{"github.com/acme/host/internal"},
+		},
+		plugin.Beans()...,
+	)
+*/
+type Sandbox struct {
+	Allow []string
+	Deny  []string
+}
+
+func (t Sandbox) applyOption(ctx *context) {
+	ctx.sandbox = &t
+}
+
+var gluePackage = reflect.TypeOf(Sandbox{}).PkgPath()
+
+func (t *Sandbox) check(class reflect.Type) error {
+	if t == nil {
+		return nil
+	}
+	pkg := classPackage(class)
+	if pkg == "" || pkg == gluePackage {
+		return nil
+	}
+	for _, deny := range t.Deny {
+		if matchPackage(pkg, deny) {
+			return errors.Errorf("package '%s' is denied by sandbox", pkg)
+		}
+	}
+	if len(t.Allow) == 0 {
+		return nil
+	}
+	for _, allow := range t.Allow {
+		if matchPackage(pkg, allow) {
+			return nil
+		}
+	}
+	return errors.Errorf("package '%s' is not allowed by sandbox", pkg)
+}
+
+func classPackage(class reflect.Type) string {
+	for {
+		switch class.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map, reflect.Chan:
+			if class.Name() == "" {
+				class = class.Elem()
+				continue
+			}
+		}
+		return class.PkgPath()
+	}
+}
+
+func matchPackage(pkg, prefix string) bool {
+	prefix = strings.TrimSuffix(prefix, "/")
+	return pkg == prefix || strings.HasPrefix(pkg, prefix+"/")
+}
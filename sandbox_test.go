@@ -0,0 +1,52 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"bytes"
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+type sandboxPlugin struct {
+	Properties glue.Properties `inject:""`
+}
+
+type sandboxIntruder struct {
+	Client *http.Client `inject:""`
+}
+
+func TestSandbox(t *testing.T) {
+
+	host, err := glue.New(&http.Client{})
+	require.NoError(t, err)
+	defer host.Close()
+
+	sandbox := glue.Sandbox{
+		Allow: []string{"github.com/codeallergy/glue_test"},
+	}
+
+	plugin, err := host.Extend(sandbox, &sandboxPlugin{})
+	require.NoError(t, err)
+	defer plugin.Close()
+
+	_, err = host.Extend(sandbox, &sandboxIntruder{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "package 'net/http' is not allowed by sandbox")
+
+	_, err = host.Extend(glue.Sandbox{Deny: []string{"bytes"}}, &bytes.Buffer{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "package 'bytes' is denied by sandbox")
+
+	child, err := plugin.Extend(&sandboxPlugin{})
+	require.NoError(t, err)
+	defer child.Close()
+
+	require.Error(t, child.Inject(&sandboxIntruder{}))
+	require.NoError(t, host.Inject(&sandboxIntruder{}))
+}
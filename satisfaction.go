@@ -0,0 +1,143 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+/**
+Beans implementing the interface in the context hierarchy returned by Context.Satisfaction
+*/
+type InterfaceSatisfaction struct {
+
+	/**
+	Interface or pointer type
+	*/
+	Interface reflect.Type
+
+	/**
+	Beans implementing the interface in the current context and then in parents, lazy ones are not constructed
+	*/
+	Beans []Bean
+
+	/**
+	Interface was requested by an injection or lookup in the context hierarchy, beans of unbound interfaces are not registered by name
+	*/
+	Bound bool
+}
+
+/**
+Status of the interface: 'ok', 'unbound' if no one requested it or 'unimplemented' if no beans implement it
+*/
+func (t InterfaceSatisfaction) Status() string {
+	switch {
+	case len(t.Beans) == 0:
+		return "unimplemented"
+	case !t.Bound:
+		return "unbound"
+	default:
+		return "ok"
+	}
+}
+
+/**
+Satisfaction matrix of interfaces in order of the request
+*/
+type SatisfactionReport []InterfaceSatisfaction
+
+/**
+Returns interfaces implemented by beans that were not requested by any injection or lookup
+*/
+func (t SatisfactionReport) Unbound() []reflect.Type {
+	return t.filter("unbound")
+}
+
+/**
+Returns interfaces without implementations
+*/
+func (t SatisfactionReport) Unimplemented() []reflect.Type {
+	return t.filter("unimplemented")
+}
+
+func (t SatisfactionReport) filter(status string) []reflect.Type {
+	var list []reflect.Type
+	for _, s := range t {
+		if s.Status() == status {
+			list = append(list, s.Interface)
+		}
+	}
+	return list
+}
+
+/**
+Returns the report with beans of each interface and the action for unbound and unimplemented ones
+*/
+func (t SatisfactionReport) String() string {
+	var out strings.Builder
+	for _, s := range t {
+		names := make([]string, len(s.Beans))
+		for i, b := range s.Beans {
+			names[i] = b.Name()
+		}
+		fmt.Fprintf(&out, "%v: %s [%s]", s.Interface, s.Status(), strings.Join(names, ", "))
+		switch s.Status() {
+		case "unbound":
+			fmt.Fprintf(&out, ", add placeholder &struct{ %s %v `inject` }{} to the scan list to bind it", placeholderField(s.Interface), s.Interface)
+		case "unimplemented":
+			fmt.Fprintf(&out, ", add a bean implementing it to the scan list")
+		}
+		out.WriteByte('\n')
+	}
+	return out.String()
+}
+
+func (t *context) Satisfaction(ifaces ...reflect.Type) SatisfactionReport {
+	return t.satisfaction(ifaces, false)
+}
+
+/**
+Builds the report over the context and its parents, or over the context only if local
+*/
+func (t *context) satisfaction(ifaces []reflect.Type, local bool) SatisfactionReport {
+	report := make(SatisfactionReport, len(ifaces))
+	for i, iface := range ifaces {
+		s := InterfaceSatisfaction{Interface: iface}
+		for ctx := t; ctx != nil; ctx = ctx.parent {
+			if _, ok := ctx.registry.findByType(iface); ok {
+				s.Bound = true
+			}
+			for _, inject := range ctx.injections {
+				if inject.injectionDef.fieldType == iface {
+					s.Bound = true
+				}
+			}
+			ctx.eachBean(func(b Bean) bool {
+				if impl, ok := b.(*bean); ok && impl.matches(iface) {
+					s.Beans = append(s.Beans, b)
+				}
+				return true
+			})
+			if local {
+				break
+			}
+		}
+		report[i] = s
+	}
+	return report
+}
+
+func placeholderField(typ reflect.Type) string {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if name := typ.Name(); name != "" {
+		return strings.ToUpper(name[:1]) + name[1:]
+	}
+	return "Bean"
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"log"
+	"reflect"
+	"testing"
+)
+
+func TestSatisfaction(t *testing.T) {
+
+	ctx, err := glue.New(
+		log.Default(),
+		&storageImpl{},
+		&configServiceImpl{},
+		&userServiceImpl{},
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	report := ctx.Satisfaction(StorageClass, UserServiceClass, AppServiceClass)
+	require.Equal(t, 3, len(report))
+
+	require.Equal(t, "ok", report[0].Status())
+	require.Equal(t, 1, len(report[0].Beans))
+	require.Equal(t, "storage", report[0].Beans[0].Name())
+
+	require.Equal(t, "unbound", report[1].Status())
+	require.Equal(t, 1, len(report[1].Beans))
+	require.Equal(t, []reflect.Type{UserServiceClass}, report.Unbound())
+
+	require.Equal(t, "unimplemented", report[2].Status())
+	require.Equal(t, []reflect.Type{AppServiceClass}, report.Unimplemented())
+
+	require.Contains(t, report.String(), "glue_test.UserService: unbound [*glue_test.userServiceImpl], add placeholder &struct{ UserService glue_test.UserService `inject` }{}")
+
+	/**
+	The report does not bind interfaces
+	*/
+	require.Equal(t, 0, len(ctx.Lookup("*glue_test.userServiceImpl", glue.DefaultLevel)))
+
+	child, err := ctx.Extend(
+		&struct{ UserService UserService `inject` }{},
+	)
+	require.NoError(t, err)
+	defer child.Close()
+
+	report = child.Satisfaction(UserServiceClass)
+	require.Equal(t, "ok", report[0].Status())
+
+	bound, err := glue.New(
+		log.Default(),
+		&storageImpl{},
+		&configServiceImpl{},
+		&userServiceImpl{},
+		&struct{ UserService UserService `inject` }{},
+	)
+	require.NoError(t, err)
+	defer bound.Close()
+
+	require.Equal(t, "ok", bound.Satisfaction(UserServiceClass)[0].Status())
+	require.Equal(t, 1, len(bound.Lookup("*glue_test.userServiceImpl", glue.DefaultLevel)))
+}
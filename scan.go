@@ -0,0 +1,323 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"github.com/pkg/errors"
+	"reflect"
+	"strings"
+)
+
+/**
+Bean registration with attributes applied on scan
+*/
+type registration struct {
+
+	/**
+	Registered instance
+	*/
+	obj interface{}
+
+	/**
+	Prefix of properties and bean name
+	*/
+	prefix string
+
+	/**
+	Construct the bean on first lookup or injection need
+	*/
+	lazy bool
+
+	/**
+	Bean name set at registration site, replaces the name from NamedBean
+	*/
+	name string
+
+	/**
+	Bean order set at registration site, replaces the order from OrderedBean
+	*/
+	ordered bool
+	order   int
+
+	/**
+	Bean is preferred among multiple candidates for the single field
+	*/
+	primary bool
+
+	/**
+	Name of the group of beans set by glue.Group
+	*/
+	group string
+}
+
+/**
+Attribute of the bean set at registration site by glue.Provide
+*/
+type ProvideOption func(r *registration, profiles *[]string)
+
+/**
+Registers the bean with attributes set at registration site instead of NamedBean and OrderedBean interfaces,
+helpful for third-party types that can not be modified.
+
+Example:
+	glue.New(
+		glue.Provide(&http.Client{}, glue.Name("defaultClient"), glue.Primary()),
+		glue.Provide(&redisCache{}, glue.Order(5), glue.Profile("prod")),
+	)
+*/
+func Provide(obj interface{}, options ...ProvideOption) interface{} {
+	r := registrationOf(obj)
+	var profiles []string
+	for _, opt := range options {
+		opt(r, &profiles)
+	}
+	if len(profiles) > 0 {
+		return &conditionalGroup{profiles: profiles, beans: []interface{}{r}}
+	}
+	return r
+}
+
+/**
+Sets the name of the bean used by Lookup, qualifier and map injection
+*/
+func Name(name string) ProvideOption {
+	return func(r *registration, profiles *[]string) {
+		r.name = name
+	}
+}
+
+/**
+Sets the order of the bean in collections
+*/
+func Order(order int) ProvideOption {
+	return func(r *registration, profiles *[]string) {
+		r.ordered = true
+		r.order = order
+	}
+}
+
+/**
+Marks the bean as preferred when multiple candidates match the single field
+*/
+func Primary() ProvideOption {
+	return func(r *registration, profiles *[]string) {
+		r.primary = true
+	}
+}
+
+/**
+Includes the bean only if one of the profiles is active, active profiles are listed in 'glue.profiles.active' property separated by comma
+*/
+func Profile(names ...string) ProvideOption {
+	return func(r *registration, profiles *[]string) {
+		*profiles = append(*profiles, names...)
+	}
+}
+
+/**
+Property with active profiles separated by comma
+*/
+const ActiveProfilesProperty = "glue.profiles.active"
+
+/**
+Returns registration of the object, merging with existing one if object is already registration
+*/
+func registrationOf(obj interface{}) *registration {
+	if r, ok := obj.(*registration); ok {
+		c := *r
+		return &c
+	}
+	return &registration{obj: obj}
+}
+
+/**
+Registers the bean that reads properties of 'value' tags under the prefix and has the prefix in the bean name.
+Gives the ability to register the same struct type multiple times with different configuration.
+
+Example:
+	glue.New(
+		glue.WithPrefix("instanceA.", &server{}),
+		glue.WithPrefix("instanceB.", &server{}),
+	)
+*/
+func WithPrefix(prefix string, obj interface{}) interface{} {
+	if prefix != "" && !strings.HasSuffix(prefix, ".") {
+		prefix += "."
+	}
+	r := registrationOf(obj)
+	r.prefix = prefix
+	return r
+}
+
+/**
+Group of beans that are investigated on creation of the context, but constructed only on first lookup or injection need
+*/
+type lazyGroup struct {
+	beans []interface{}
+}
+
+/**
+Registers beans that are constructed on first lookup, runtime injection or when other constructed bean depends on them.
+Reduces startup cost for rarely-used subsystems while keeping them in the same context.
+Lookups skip beans failed to construct and report errors as warnings.
+
+Example:
+	glue.New(
+		&service{},
+		glue.Lazy(
+			&reportGenerator{},
+			&reportStorage{},
+		),
+	)
+*/
+func Lazy(beans ...interface{}) interface{} {
+	return &lazyGroup{beans: beans}
+}
+
+/**
+Group of beans included in the context only if the boolean property is true on creation of the context
+*/
+type conditionalGroup struct {
+	key      string
+	profiles []string
+	beans    []interface{}
+}
+
+/**
+Includes beans only if the boolean property is true on creation of the context.
+Property sources and resolvers from the scan list are applied before evaluation of the condition.
+Missing property means disabled group.
+
+Example:
+	glue.New(
+		&glue.PropertySource{Path: "resources:application.properties"},
+		glue.EnabledIf("feature.search.enabled",
+			&searchIndex{},
+			&searchHandler{},
+		),
+	)
+*/
+func EnabledIf(key string, beans ...interface{}) interface{} {
+	return &conditionalGroup{key: key, beans: beans}
+}
+
+func (t *conditionalGroup) enabled(properties Properties) (bool, error) {
+	if len(t.profiles) > 0 {
+		return profileActive(properties, t.profiles), nil
+	}
+	return propertyEnabled(properties, t.key)
+}
+
+/**
+Checks if one of profiles is listed in active profiles property
+*/
+func profileActive(properties Properties, profiles []string) bool {
+	value, _ := properties.Get(ActiveProfilesProperty)
+	for _, active := range trimSplit(value, ",") {
+		for _, profile := range profiles {
+			if active == profile {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+/**
+Returns value of the boolean property, missing property means false
+*/
+func propertyEnabled(properties Properties, key string) (bool, error) {
+	value, ok := properties.Get(key)
+	if !ok {
+		return false, nil
+	}
+	enabled, err := parseBool(value)
+	if err != nil {
+		return false, errors.Errorf("invalid boolean property '%s', %v", key, redactError(properties, key, err))
+	}
+	return enabled, nil
+}
+
+/**
+Returns conditional group if the object is the group or registration of the group
+*/
+func conditionalOf(obj interface{}) (*conditionalGroup, bool) {
+	if r, ok := obj.(*registration); ok {
+		obj = r.obj
+	}
+	group, ok := obj.(*conditionalGroup)
+	return group, ok
+}
+
+/**
+Conditional group deferred on scan with the position and registration attributes
+*/
+type conditionalScan struct {
+	pos      string
+	group    *conditionalGroup
+	template interface{}
+}
+
+/**
+Returns scan callback that applies attributes of the registration of the group to each bean of the group
+*/
+func (t *conditionalScan) callback(cb func(pos string, obj interface{}) error) func(pos string, obj interface{}) error {
+	template, ok := t.template.(*registration)
+	if !ok {
+		return cb
+	}
+	return func(pos string, obj interface{}) error {
+		r := registrationOf(obj)
+		r.lazy = r.lazy || template.lazy
+		if r.prefix == "" {
+			r.prefix = template.prefix
+		}
+		if r.group == "" {
+			r.group = template.group
+		}
+		return cb(pos, r)
+	}
+}
+
+/**
+Plain value registered by glue.Value
+*/
+type namedValue struct {
+	name  string
+	value interface{}
+}
+
+/**
+Registers plain value (struct value, string, number) as the bean injectable by the type and by the qualifier.
+Only pointers and functions are accepted as beans otherwise.
+
+Example:
+	glue.New(
+		glue.Value("apiKey", "secret"),
+		glue.Value("limits", Limits{Rps: 100}),
+	)
+
+	type client struct {
+		ApiKey string `inject:"bean=apiKey"`
+		Limits Limits `inject`
+	}
+*/
+func Value(name string, v interface{}) interface{} {
+	return &namedValue{name: name, value: v}
+}
+
+func (t *namedValue) bean() *bean {
+	return &bean{
+		name:      t.name,
+		qualifier: t.name,
+		obj:       t.value,
+		valuePtr:  reflect.ValueOf(t.value),
+		beanDef: &beanDef{
+			classPtr: reflect.TypeOf(t.value),
+		},
+		lifecycle: BeanInitialized,
+	}
+}
@@ -0,0 +1,78 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"reflect"
+	"sort"
+)
+
+/**
+Scanners in the scan list are scanned in the order of the list, so beans of bundles from different packages come in the order of declaration.
+Scanners implementing OrderedScanner or wrapped by ScannerOrder are scanned in ascending order of ScanOrder instead,
+they swap only among themselves, positions of other items of the list are kept.
+The order of scanning defines the order of unordered collections and the precedence of property sources.
+*/
+var OrderedScannerClass = reflect.TypeOf((*OrderedScanner)(nil)).Elem()
+
+type OrderedScanner interface {
+	Scanner
+
+	/**
+	Returns order of scanning among ordered scanners of the same scan list
+	*/
+	ScanOrder() int
+}
+
+/**
+Declares order of scanning of the scanner that does not implement OrderedScanner.
+
+Example:
+	glue.New(
+		glue.ScannerOrder(2, metrics.Scanner),
+		glue.ScannerOrder(1, storage.Scanner),
+	)
+*/
+func ScannerOrder(order int, scanner Scanner) Scanner {
+	return &orderedScanner{Scanner: scanner, order: order}
+}
+
+type orderedScanner struct {
+	Scanner
+	order int
+}
+
+func (t *orderedScanner) ScanOrder() int {
+	return t.order
+}
+
+/**
+Returns the scan list with ordered scanners sorted among their own positions, the list is not copied if there is nothing to sort
+*/
+func sortScanners(scan []interface{}) []interface{} {
+	var slots []int
+	for i, item := range scan {
+		if _, ok := item.(OrderedScanner); ok {
+			slots = append(slots, i)
+		}
+	}
+	if len(slots) < 2 {
+		return scan
+	}
+	scanners := make([]OrderedScanner, len(slots))
+	for i, slot := range slots {
+		scanners[i] = scan[slot].(OrderedScanner)
+	}
+	sort.SliceStable(scanners, func(i, j int) bool {
+		return scanners[i].ScanOrder() < scanners[j].ScanOrder()
+	})
+	sorted := make([]interface{}, len(scan))
+	copy(sorted, scan)
+	for i, slot := range slots {
+		sorted[slot] = scanners[i]
+	}
+	return sorted
+}
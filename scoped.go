@@ -0,0 +1,87 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	stdcontext "context"
+	"fmt"
+	"github.com/pkg/errors"
+)
+
+/**
+Scoped value is a typed key of the request-scoped data (trace ID, tenant, user) carried by context.Context,
+it keeps request state out of fields of singleton beans that are shared by concurrent requests.
+Scoped value could be declared as a package variable or placed in the scan list and injected by the bean name equal to its name.
+There is no request scope of beans: the context does not create or destroy anything per request, the application sets values in to context.Context.
+
+Example:
+	var Tenant = glue.NewScopedValue[string]("tenant")
+
+	func (t *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+		ctx := Tenant.With(r.Context(), r.Header.Get("X-Tenant"))
+		t.Orders.List(ctx)
+	}
+
+	func (t *orderService) List(ctx context.Context) {
+		tenant, ok := Tenant.Get(ctx)
+		...
+	}
+*/
+type ScopedValue[T any] struct {
+	name string
+}
+
+/**
+Creates scoped value with the name used as the bean name and in errors
+*/
+func NewScopedValue[T any](name string) *ScopedValue[T] {
+	return &ScopedValue[T]{name: name}
+}
+
+func (t *ScopedValue[T]) BeanName() string {
+	return t.name
+}
+
+/**
+Returns the child of ctx carrying the value
+*/
+func (t *ScopedValue[T]) With(ctx stdcontext.Context, value T) stdcontext.Context {
+	return stdcontext.WithValue(ctx, t, value)
+}
+
+/**
+Returns the value carried by ctx
+*/
+func (t *ScopedValue[T]) Get(ctx stdcontext.Context) (T, bool) {
+	value, ok := ctx.Value(t).(T)
+	return value, ok
+}
+
+/**
+Returns the value carried by ctx or the default value
+*/
+func (t *ScopedValue[T]) GetOr(ctx stdcontext.Context, defaultValue T) T {
+	if value, ok := t.Get(ctx); ok {
+		return value
+	}
+	return defaultValue
+}
+
+/**
+Returns the value carried by ctx or error if it is absent
+*/
+func (t *ScopedValue[T]) Require(ctx stdcontext.Context) (T, error) {
+	if value, ok := t.Get(ctx); ok {
+		return value, nil
+	}
+	var empty T
+	return empty, errors.Errorf("scoped value '%s' is not set in context", t.name)
+}
+
+func (t *ScopedValue[T]) String() string {
+	var empty T
+	return fmt.Sprintf("ScopedValue[%T]{%s}", empty, t.name)
+}
@@ -0,0 +1,50 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"context"
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type tenantService struct {
+	Tenant  *glue.ScopedValue[string] `inject:"bean=tenant"`
+	TraceID *glue.ScopedValue[string] `inject:"bean=traceId"`
+}
+
+func (t *tenantService) Describe(ctx context.Context) string {
+	return t.Tenant.GetOr(ctx, "default") + "/" + t.TraceID.GetOr(ctx, "-")
+}
+
+func TestScopedValue(t *testing.T) {
+
+	srv := &tenantService{}
+	ctx, err := glue.New(
+		glue.NewScopedValue[string]("tenant"),
+		glue.NewScopedValue[string]("traceId"),
+		srv,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	req := context.Background()
+	require.Equal(t, "default/-", srv.Describe(req))
+
+	req = srv.Tenant.With(req, "acme")
+	require.Equal(t, "acme/-", srv.Describe(req))
+
+	req = srv.TraceID.With(req, "abc123")
+	require.Equal(t, "acme/abc123", srv.Describe(req))
+
+	tenant, err := srv.Tenant.Require(req)
+	require.NoError(t, err)
+	require.Equal(t, "acme", tenant)
+
+	_, err = glue.NewScopedValue[int]("user").Require(req)
+	require.Error(t, err)
+}
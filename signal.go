@@ -0,0 +1,98 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"reflect"
+	"sync"
+)
+
+/**
+Channel closed when the context starts closing, before consumers and beans are stopped.
+Every context has own built-in bean, convenient for third-party components taking stop channels.
+
+Example:
+	type worker struct {
+		Shutdown glue.ShutdownSignal `inject`
+	}
+
+	func (t *worker) PostConstruct() error {
+		go thirdparty.Run(t.Shutdown)
+		return nil
+	}
+*/
+type ShutdownSignal <-chan struct{}
+
+var ShutdownSignalClass = reflect.TypeOf((ShutdownSignal)(nil))
+
+/**
+Channel closed when the context is created, all beans are constructed and consumers are started.
+Every context has own built-in bean, it is never closed if construction of beans fails. Runners are started after it.
+
+Example:
+	type probe struct {
+		Ready glue.ReadySignal `inject`
+	}
+
+	func (t *probe) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-t.Ready:
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}
+*/
+type ReadySignal <-chan struct{}
+
+var ReadySignalClass = reflect.TypeOf((ReadySignal)(nil))
+
+/**
+Channel closed once, exposed by the bean holding the typed channel
+*/
+type signal struct {
+	ch   chan struct{}
+	once sync.Once
+	bean *bean
+}
+
+func newSignal(classPtr reflect.Type) *signal {
+	ch := make(chan struct{})
+	value := reflect.ValueOf(ch).Convert(classPtr)
+	return &signal{
+		ch: ch,
+		bean: &bean{
+			name:     classPtr.String(),
+			obj:      value.Interface(),
+			valuePtr: value,
+			beanDef: &beanDef{
+				classPtr: classPtr,
+			},
+			lifecycle: BeanInitialized,
+		},
+	}
+}
+
+func (t *signal) fire() {
+	t.once.Do(func() {
+		close(t.ch)
+	})
+}
+
+/**
+Returns beans of the exact type in the context.
+Built-in signal beans are resolved by the context itself and are not part of core beans.
+*/
+func (t *context) objectBeans(classPtr reflect.Type) ([]*bean, bool) {
+	switch classPtr {
+	case ShutdownSignalClass:
+		return []*bean{t.shutdown.bean}, true
+	case ReadySignalClass:
+		return []*bean{t.ready.bean}, true
+	}
+	list, ok := t.coreBeans()[classPtr]
+	return list, ok
+}
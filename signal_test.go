@@ -0,0 +1,69 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type signalWorker struct {
+	Shutdown glue.ShutdownSignal `inject`
+	Ready    glue.ReadySignal    `inject`
+	ready    bool
+}
+
+func (t *signalWorker) PostConstruct() error {
+	select {
+	case <-t.Ready:
+		t.ready = true
+	default:
+	}
+	return nil
+}
+
+func TestSignals(t *testing.T) {
+
+	worker := &signalWorker{}
+	ctx, err := glue.New(worker)
+	require.NoError(t, err)
+
+	require.NotNil(t, worker.Shutdown)
+	require.False(t, worker.ready)
+
+	select {
+	case <-worker.Ready:
+	default:
+		require.Fail(t, "ready signal must be closed after creation")
+	}
+
+	child, err := ctx.Extend()
+	require.NoError(t, err)
+
+	runtime := &struct {
+		Shutdown glue.ShutdownSignal `inject`
+	}{}
+	require.NoError(t, child.Inject(runtime))
+
+	require.NoError(t, child.Close())
+	_, open := <-runtime.Shutdown
+	require.False(t, open)
+
+	select {
+	case <-worker.Shutdown:
+		require.Fail(t, "shutdown signal of the parent must be open")
+	default:
+	}
+
+	signals := glue.ListOf[glue.ShutdownSignal](ctx)
+	require.Equal(t, 1, len(signals))
+	require.True(t, signals[0] == worker.Shutdown)
+
+	require.NoError(t, ctx.Close())
+	_, open = <-worker.Shutdown
+	require.False(t, open)
+}
@@ -0,0 +1,284 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"encoding/json"
+	"github.com/pkg/errors"
+	"io"
+	"reflect"
+	"sort"
+)
+
+/**
+Version of the snapshot format, snapshots of other versions are rejected
+*/
+const SnapshotVersion = 3
+
+/**
+Snapshot of scanned bean definitions, objects are not included
+*/
+type snapshot struct {
+	Version int              `json:"version"`
+	Classes []*classSnapshot `json:"classes"`
+
+	/**
+	Index of classes by class name, filled on load
+	*/
+	index map[string]*classSnapshot
+}
+
+type classSnapshot struct {
+	Class      string              `json:"class"`
+	NumField   int                 `json:"numField"`
+	Fields     []*fieldSnapshot    `json:"fields,omitempty"`
+	Properties []*propertySnapshot `json:"properties,omitempty"`
+}
+
+type fieldSnapshot struct {
+	Num         int      `json:"num"`
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Tag         string   `json:"tag"`
+	Slice       bool     `json:"slice,omitempty"`
+	Table       bool     `json:"table,omitempty"`
+	Lazy        bool     `json:"lazy,omitempty"`
+	Optional    bool     `json:"optional,omitempty"`
+	Ordered     bool     `json:"ordered,omitempty"`
+	Topo        bool     `json:"topo,omitempty"`
+	Weak        bool     `json:"weak,omitempty"`
+	Qualifier   string   `json:"qualifier,omitempty"`
+	When        string   `json:"when,omitempty"`
+	Also        []string `json:"also,omitempty"`
+	ExcludeSelf bool     `json:"excludeSelf,omitempty"`
+	OnDuplicate string   `json:"onDuplicate,omitempty"`
+	KeyCase     string   `json:"keyCase,omitempty"`
+	KeyFunc     string   `json:"keyFunc,omitempty"`
+	Level       int      `json:"level"`
+}
+
+type propertySnapshot struct {
+	Num       int                 `json:"num"`
+	Name      string              `json:"name"`
+	Type      string              `json:"type"`
+	Tag       string              `json:"tag"`
+	Property  string              `json:"property"`
+	Default   string              `json:"default,omitempty"`
+	Layout    string              `json:"layout,omitempty"`
+	Sensitive bool                `json:"sensitive,omitempty"`
+	Nested    []*propertySnapshot `json:"nested,omitempty"`
+}
+
+/**
+Creates context using bean definitions from the snapshot written by Context.Snapshot.
+Objects are constructed fresh, only metadata of injection fields and properties is taken from the snapshot.
+Classes missing in the snapshot or with fields, types or tags changed after it was written are investigated as usual.
+
+Example:
+	file, err := os.Open("context.snapshot")
+	ctx, err := glue.NewFromSnapshot(file, beans...)
+*/
+func NewFromSnapshot(r io.Reader, scan ...interface{}) (Context, error) {
+	s := new(snapshot)
+	if err := json.NewDecoder(r).Decode(s); err != nil {
+		return nil, errors.Errorf("decode snapshot, %v", err)
+	}
+	if s.Version != SnapshotVersion {
+		return nil, errors.Errorf("unsupported snapshot version %d, expected %d", s.Version, SnapshotVersion)
+	}
+	s.index = make(map[string]*classSnapshot)
+	for _, c := range s.Classes {
+		s.index[c.Class] = c
+	}
+	return createContext(nil, s, scan)
+}
+
+func (t *context) Snapshot(w io.Writer) error {
+	s := &snapshot{Version: SnapshotVersion}
+	visited := make(map[reflect.Type]bool)
+	for classPtr, list := range t.coreBeans() {
+		for _, b := range list {
+			if b.beenFactory != nil || b.beanDef.classPtr.Kind() != reflect.Ptr || b.beanDef.classPtr.Elem().Kind() != reflect.Struct {
+				continue
+			}
+			if visited[classPtr] {
+				continue
+			}
+			visited[classPtr] = true
+			s.Classes = append(s.Classes, newClassSnapshot(b.beanDef))
+		}
+	}
+	sort.Slice(s.Classes, func(i, j int) bool {
+		return s.Classes[i].Class < s.Classes[j].Class
+	})
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(s)
+}
+
+func newClassSnapshot(bd *beanDef) *classSnapshot {
+	c := &classSnapshot{
+		Class:    bd.classPtr.String(),
+		NumField: bd.classPtr.Elem().NumField(),
+	}
+	for _, def := range bd.fields {
+		c.Fields = append(c.Fields, &fieldSnapshot{
+			Num:         def.fieldNum,
+			Name:        def.fieldName,
+			Type:        def.class.Field(def.fieldNum).Type.String(),
+			Tag:         string(def.class.Field(def.fieldNum).Tag),
+			Slice:       def.slice,
+			Table:       def.table,
+			Lazy:        def.lazy,
+			Optional:    def.optional,
+			Ordered:     def.ordered,
+			Topo:        def.topo,
+			Weak:        def.weakType != nil,
+			Qualifier:   def.qualifier,
+			When:        def.when,
+			Also:        interfaceNames(def.also),
+			ExcludeSelf: def.excludeSelf,
+			OnDuplicate: def.onDuplicate,
+			KeyCase:     def.keyCase,
+			KeyFunc:     def.keyFunc,
+			Level:       def.level,
+		})
+	}
+	c.Properties = newPropertySnapshots(bd.properties)
+	return c
+}
+
+func newPropertySnapshots(list []*propInjectionDef) []*propertySnapshot {
+	var out []*propertySnapshot
+	for _, def := range list {
+		out = append(out, &propertySnapshot{
+			Num:       def.fieldNum,
+			Name:      def.fieldName,
+			Type:      def.fieldType.String(),
+			Tag:       string(def.class.Field(def.fieldNum).Tag),
+			Property:  def.propertyName,
+			Default:   def.defaultValue,
+			Layout:    def.layout,
+			Sensitive: def.sensitive,
+			Nested:    newPropertySnapshots(def.nested),
+		})
+	}
+	return out
+}
+
+/**
+Restores bean definition of the class from the snapshot, returns nil if snapshot does not have the class or it was changed
+*/
+func (t *snapshot) restore(classPtr reflect.Type) *beanDef {
+	if t == nil || classPtr.Kind() != reflect.Ptr || classPtr.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	c, ok := t.index[classPtr.String()]
+	if !ok {
+		return nil
+	}
+	class := classPtr.Elem()
+	if class.NumField() != c.NumField {
+		return nil
+	}
+	bd := &beanDef{classPtr: classPtr}
+	for _, f := range c.Fields {
+		field, ok := snapshotField(class, f.Num, f.Name, f.Type, f.Tag)
+		if !ok {
+			return nil
+		}
+		def := &injectionDef{
+			class:       class,
+			fieldNum:    f.Num,
+			fieldName:   f.Name,
+			fieldType:   field.Type,
+			slice:       f.Slice,
+			table:       f.Table,
+			lazy:        f.Lazy,
+			optional:    f.Optional,
+			ordered:     f.Ordered,
+			topo:        f.Topo,
+			qualifier:   f.Qualifier,
+			when:        f.When,
+			excludeSelf: f.ExcludeSelf,
+			onDuplicate: f.OnDuplicate,
+			level:       f.Level,
+		}
+		if def.resolveMapKey(f.KeyCase, f.KeyFunc) != nil {
+			return nil
+		}
+		for _, name := range f.Also {
+			iface, ok := lookupInterface(name)
+			if !ok {
+				return nil
+			}
+			def.also = append(def.also, iface)
+		}
+		if f.Weak {
+			if !field.Type.Implements(weakReferenceClass) {
+				return nil
+			}
+			def.weakType = newWeakReference(field.Type).weakType()
+		} else if f.Slice || f.Table {
+			def.fieldType = field.Type.Elem()
+		}
+		bd.fields = append(bd.fields, def)
+	}
+	properties, ok := restoreProperties(class, c.Properties)
+	if !ok {
+		return nil
+	}
+	bd.properties = properties
+	return bd
+}
+
+func restoreProperties(class reflect.Type, list []*propertySnapshot) ([]*propInjectionDef, bool) {
+	var out []*propInjectionDef
+	for _, p := range list {
+		field, ok := snapshotField(class, p.Num, p.Name, p.Type, p.Tag)
+		if !ok {
+			return nil, false
+		}
+		def := &propInjectionDef{
+			class:        class,
+			fieldNum:     p.Num,
+			fieldName:    p.Name,
+			fieldType:    field.Type,
+			propertyName: p.Property,
+			defaultValue: p.Default,
+			layout:       p.Layout,
+			sensitive:    p.Sensitive,
+		}
+		if nestedClass, ok := nestedPropertyClass(field.Type); ok {
+			nested, ok := restoreProperties(nestedClass, p.Nested)
+			if !ok {
+				return nil, false
+			}
+			def.nested = append([]*propInjectionDef{}, nested...)
+		}
+		out = append(out, def)
+	}
+	return out, true
+}
+
+/**
+Returns the field of the class if it has the same name, type and tag as in the snapshot, edited tags make the snapshot stale
+*/
+func snapshotField(class reflect.Type, num int, name, typ, tag string) (reflect.StructField, bool) {
+	if num < 0 || num >= class.NumField() {
+		return reflect.StructField{}, false
+	}
+	field := class.Field(num)
+	return field, field.Name == name && field.Type.String() == typ && string(field.Tag) == tag
+}
+
+func interfaceNames(list []reflect.Type) []string {
+	var names []string
+	for _, typ := range list {
+		names = append(names, typ.String())
+	}
+	return names
+}
@@ -0,0 +1,134 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+type snapshotStorage struct {
+	Path string `value:"storage.path,default=/tmp"`
+}
+
+type snapshotService struct {
+	Storage  *snapshotStorage `inject`
+	Services []FirstService   `inject:"optional"`
+	Timeout  int              `value:"service.timeout,default=30"`
+}
+
+func TestSnapshot(t *testing.T) {
+
+	ctx, err := glue.New(
+		&snapshotStorage{},
+		&snapshotService{},
+	)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, ctx.Snapshot(&buf))
+	ctx.Close()
+
+	require.Contains(t, buf.String(), "*glue_test.snapshotService")
+	require.Contains(t, buf.String(), "service.timeout")
+
+	service := &snapshotService{}
+	ctx, err = glue.NewFromSnapshot(bytes.NewReader(buf.Bytes()),
+		&glue.PropertySource{Map: map[string]interface{}{
+			"storage.path": "/var/lib",
+		}},
+		&snapshotStorage{},
+		&firstServiceImpl{testing: t},
+		service,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.NotNil(t, service.Storage)
+	require.Equal(t, "/var/lib", service.Storage.Path)
+	require.Equal(t, 30, service.Timeout)
+	require.Equal(t, 1, len(service.Services))
+}
+
+func TestSnapshotStale(t *testing.T) {
+
+	ctx, err := glue.New(
+		&snapshotStorage{},
+		&snapshotService{},
+	)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, ctx.Snapshot(&buf))
+	ctx.Close()
+
+	// class changed after the snapshot was written
+	stale := strings.ReplaceAll(buf.String(), `"Storage"`, `"Store"`)
+
+	service := &snapshotService{}
+	ctx, err = glue.NewFromSnapshot(strings.NewReader(stale),
+		&snapshotStorage{},
+		service,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+	require.NotNil(t, service.Storage)
+
+	// tag edited after the snapshot was written
+	stale = strings.ReplaceAll(buf.String(), `"default": "30"`, `"default": "60"`)
+	stale = strings.ReplaceAll(stale, `default=30`, `default=60`)
+
+	service = &snapshotService{}
+	tagged, err := glue.NewFromSnapshot(strings.NewReader(stale),
+		&snapshotStorage{},
+		service,
+	)
+	require.NoError(t, err)
+	defer tagged.Close()
+	require.Equal(t, 30, service.Timeout)
+
+	var unsupported map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &unsupported))
+	unsupported["version"] = glue.SnapshotVersion + 1
+	data, err := json.Marshal(unsupported)
+	require.NoError(t, err)
+
+	_, err = glue.NewFromSnapshot(bytes.NewReader(data))
+	require.Error(t, err)
+}
+
+func TestSnapshotTopo(t *testing.T) {
+
+	scan := func(pipeline *stagePipeline) []interface{} {
+		return []interface{}{
+			&auditStage{},
+			&storeStage{},
+			&enrichedStore{},
+			&enrichStage{},
+			&parseStage{},
+			pipeline,
+		}
+	}
+
+	ctx, err := glue.New(scan(&stagePipeline{})...)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, ctx.Snapshot(&buf))
+	ctx.Close()
+	require.Contains(t, buf.String(), `"topo": true`)
+
+	pipeline := &stagePipeline{}
+	ctx, err = glue.NewFromSnapshot(bytes.NewReader(buf.Bytes()), scan(pipeline)...)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.Equal(t, []string{"audit", "parse", "enrich", "store"}, pipeline.names())
+}
@@ -0,0 +1,160 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"bufio"
+	"io"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+/**
+This interface used by long-running stateful beans (offsets, caches) that survive planned restarts through Context.Checkpoint and Context.Restore.
+*/
+var StatefulBeanClass = reflect.TypeOf((*StatefulBean)(nil)).Elem()
+
+type StatefulBean interface {
+
+	/**
+	Writes the state of the bean
+	*/
+	SaveState(w io.Writer) error
+
+	/**
+	Reads the state of the bean saved by SaveState
+	*/
+	LoadState(r io.Reader) error
+}
+
+/**
+Extension of the state file of the bean in the checkpoint directory
+*/
+const stateFileExt = ".state"
+
+/**
+Returns initialized stateful beans of the current context with file names in the checkpoint directory in order of names
+*/
+func (t *context) statefulBeans() (map[string]*bean, []string, error) {
+	files := make(map[string]*bean)
+	var names []string
+	var err error
+	t.eachBean(func(b Bean) bool {
+		impl, ok := b.(*bean)
+		if !ok || impl.Lifecycle() != BeanInitialized {
+			return true
+		}
+		if _, ok := impl.obj.(StatefulBean); !ok {
+			return true
+		}
+		name := stateFileName(impl.name)
+		if other, ok := files[name]; ok {
+			err = errors.Errorf("stateful beans '%s' and '%s' have the same state file '%s', implement NamedBean to distinguish them", other.name, impl.name, name)
+			return false
+		}
+		files[name] = impl
+		names = append(names, name)
+		return true
+	})
+	sort.Strings(names)
+	return files, names, err
+}
+
+func stateFileName(beanName string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, beanName)
+	return name + stateFileExt
+}
+
+func (t *context) Checkpoint(dir string) error {
+	files, names, err := t.statefulBeans()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return errors.Errorf("create checkpoint directory '%s', %v", dir, err)
+	}
+	var errs []error
+	for _, name := range names {
+		b := files[name]
+		if t.logger() != nil {
+			t.logger().Printf("Checkpoint bean '%s' with type '%v' to '%s'\n", b.name, b.beanDef.classPtr, name)
+		}
+		if err := saveState(filepath.Join(dir, name), b.obj.(StatefulBean)); err != nil {
+			errs = append(errs, errors.Errorf("checkpoint bean '%s' with type '%v', %v", b.name, b.beanDef.classPtr, err))
+		}
+	}
+	return multipleErr(errs)
+}
+
+/**
+Writes the state to the temporary file and renames it, so the previous checkpoint stays intact on failure
+*/
+func saveState(path string, stateful StatefulBean) (err error) {
+	f, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+	w := bufio.NewWriter(f)
+	if err = stateful.SaveState(w); err != nil {
+		return err
+	}
+	if err = w.Flush(); err != nil {
+		return err
+	}
+	if err = f.Sync(); err != nil {
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+func (t *context) Restore(dir string) error {
+	files, names, err := t.statefulBeans()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, name := range names {
+		b := files[name]
+		f, err := os.Open(filepath.Join(dir, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, errors.Errorf("restore bean '%s' with type '%v', %v", b.name, b.beanDef.classPtr, err))
+			continue
+		}
+		if t.logger() != nil {
+			t.logger().Printf("Restore bean '%s' with type '%v' from '%s'\n", b.name, b.beanDef.classPtr, name)
+		}
+		err = b.obj.(StatefulBean).LoadState(bufio.NewReader(f))
+		f.Close()
+		if err != nil {
+			errs = append(errs, errors.Errorf("restore bean '%s' with type '%v', %v", b.name, b.beanDef.classPtr, err))
+		}
+	}
+	return multipleErr(errs)
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type offsetTracker struct {
+	Offset int
+	fail   bool
+}
+
+func (t *offsetTracker) SaveState(w io.Writer) error {
+	if t.fail {
+		return errors.New("disk is full")
+	}
+	_, err := fmt.Fprintf(w, "%d", t.Offset)
+	return err
+}
+
+func (t *offsetTracker) LoadState(r io.Reader) error {
+	_, err := fmt.Fscanf(r, "%d", &t.Offset)
+	return err
+}
+
+type cacheTracker struct {
+	Entries string
+}
+
+func (t *cacheTracker) BeanName() string {
+	return "cache"
+}
+
+func (t *cacheTracker) SaveState(w io.Writer) error {
+	_, err := io.WriteString(w, t.Entries)
+	return err
+}
+
+func (t *cacheTracker) LoadState(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	t.Entries = string(data)
+	return err
+}
+
+func TestCheckpointRestore(t *testing.T) {
+
+	dir := filepath.Join(t.TempDir(), "state")
+
+	tracker := &offsetTracker{Offset: 42}
+	ctx, err := glue.New(tracker)
+	require.NoError(t, err)
+
+	require.NoError(t, ctx.Checkpoint(dir))
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(files))
+
+	tracker.Offset = 43
+	tracker.fail = true
+	require.Error(t, ctx.Checkpoint(dir))
+	require.NoError(t, ctx.Close())
+
+	files, err = os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(files))
+
+	restored := &offsetTracker{}
+	cache := &cacheTracker{}
+	ctx, err = glue.New(restored, cache)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.NoError(t, ctx.Restore(dir))
+	require.Equal(t, 42, restored.Offset)
+	require.Equal(t, "", cache.Entries)
+}
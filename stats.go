@@ -0,0 +1,275 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"reflect"
+	"sort"
+)
+
+/**
+Statistics of beans of the context returned by Context.Stats
+*/
+type ContextStats struct {
+
+	/**
+	Number of beans in the context including objects produced by factories
+	*/
+	Beans int `json:"beans"`
+
+	/**
+	Number of initialized beans, the rest are lazy or produced on demand
+	*/
+	Initialized int `json:"initialized"`
+
+	/**
+	Approximate retained memory of beans sorted by size, the largest first, empty without MemoryAnalyzer option
+	*/
+	Memory []BeanMemory `json:"memory,omitempty"`
+}
+
+/**
+Approximate memory retained by the bean, memory shared with beans listed before is not counted again,
+memory of other beans referenced by the bean is not included
+*/
+type BeanMemory struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+
+	/**
+	Estimated size in bytes
+	*/
+	Size int64 `json:"size"`
+
+	/**
+	Walk reached limits of the analyzer, the real size is greater
+	*/
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+/**
+Option of the context that enables estimation of retained memory per bean in Context.Stats, to find which singleton is ballooning process RSS.
+Estimation walks objects by reflection with the visited set, so the call is expensive, zero limits mean defaults.
+
+Example:
+	ctx, err := glue.New(
+		glue.MemoryAnalyzer{},
+		&cache{},
+	)
+
+	for _, m := range ctx.Stats().Memory {
+		fmt.Printf("%s %d\n", m.Name, m.Size)
+	}
+*/
+type MemoryAnalyzer struct {
+
+	/**
+	Maximum depth of references walked from the bean, 64 by default
+	*/
+	MaxDepth int
+
+	/**
+	Maximum number of objects walked per bean, 100000 by default
+	*/
+	MaxObjects int
+}
+
+func (t MemoryAnalyzer) applyOption(ctx *context) {
+	if t.MaxDepth <= 0 {
+		t.MaxDepth = 64
+	}
+	if t.MaxObjects <= 0 {
+		t.MaxObjects = 100000
+	}
+	ctx.memoryAnalyzer = &t
+}
+
+func (t *context) Stats() ContextStats {
+	var stats ContextStats
+	var list []*bean
+	t.eachBean(func(b Bean) bool {
+		impl, ok := b.(*bean)
+		if !ok || impl.obj == interface{}(t) {
+			return true
+		}
+		stats.Beans++
+		if impl.Lifecycle() == BeanInitialized {
+			stats.Initialized++
+		}
+		list = append(list, impl)
+		return true
+	})
+	if t.memoryAnalyzer != nil {
+		stats.Memory = t.estimateMemory(list)
+	}
+	return stats
+}
+
+/**
+Estimates retained memory of beans with the visited set shared between them
+*/
+func (t *context) estimateMemory(list []*bean) []BeanMemory {
+	walker := &memoryWalker{
+		limits:  t.memoryAnalyzer,
+		visited: make(map[memoryRef]bool),
+		beans:   make(map[uintptr]bool),
+	}
+	for ctx := t; ctx != nil; ctx = ctx.parent {
+		ctx.eachBean(func(b Bean) bool {
+			if v := reflect.ValueOf(b.Object()); v.Kind() == reflect.Ptr && !v.IsNil() {
+				walker.beans[v.Pointer()] = true
+			}
+			return true
+		})
+	}
+	var result []BeanMemory
+	for _, b := range list {
+		v := reflect.ValueOf(b.obj)
+		if v.Kind() != reflect.Ptr || v.IsNil() {
+			continue
+		}
+		walker.objects = 0
+		walker.truncated = false
+		size := int64(v.Type().Elem().Size()) + walker.walk(v.Elem(), 1)
+		result = append(result, BeanMemory{
+			Name:      b.name,
+			Type:      b.beanDef.classPtr.String(),
+			Size:      size,
+			Truncated: walker.truncated,
+		})
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Size > result[j].Size
+	})
+	return result
+}
+
+type memoryRef struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+type memoryWalker struct {
+	limits    *MemoryAnalyzer
+	visited   map[memoryRef]bool
+	beans     map[uintptr]bool
+	objects   int
+	truncated bool
+}
+
+/**
+Marks the referenced memory as visited, returns false if it was counted already or belongs to other bean
+*/
+func (t *memoryWalker) enter(ptr uintptr, typ reflect.Type) bool {
+	if ptr == 0 || t.beans[ptr] {
+		return false
+	}
+	ref := memoryRef{ptr, typ}
+	if t.visited[ref] {
+		return false
+	}
+	t.visited[ref] = true
+	return true
+}
+
+/**
+Returns size of memory referenced by the value, excluding the inline size of the value itself
+*/
+func (t *memoryWalker) walk(v reflect.Value, depth int) int64 {
+	if depth > t.limits.MaxDepth || t.objects >= t.limits.MaxObjects {
+		t.truncated = true
+		return 0
+	}
+	t.objects++
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() || !t.enter(v.Pointer(), v.Type()) {
+			return 0
+		}
+		return int64(v.Type().Elem().Size()) + t.walk(v.Elem(), depth+1)
+	case reflect.Interface:
+		if v.IsNil() {
+			return 0
+		}
+		elem := v.Elem()
+		if elem.Kind() == reflect.Ptr {
+			return t.walk(elem, depth)
+		}
+		return int64(elem.Type().Size()) + t.walk(elem, depth+1)
+	case reflect.String:
+		return int64(v.Len())
+	case reflect.Slice:
+		if v.IsNil() || !t.enter(v.Pointer(), v.Type()) {
+			return 0
+		}
+		size := int64(v.Cap()) * int64(v.Type().Elem().Size())
+		if hasReferences(v.Type().Elem()) {
+			for i := 0; i < v.Len(); i++ {
+				size += t.walk(v.Index(i), depth+1)
+			}
+		}
+		return size
+	case reflect.Array:
+		var size int64
+		if hasReferences(v.Type().Elem()) {
+			for i := 0; i < v.Len(); i++ {
+				size += t.walk(v.Index(i), depth+1)
+			}
+		}
+		return size
+	case reflect.Map:
+		if v.IsNil() || !t.enter(v.Pointer(), v.Type()) {
+			return 0
+		}
+		size := int64(v.Len()) * int64(v.Type().Key().Size()+v.Type().Elem().Size())
+		walkKeys, walkValues := hasReferences(v.Type().Key()), hasReferences(v.Type().Elem())
+		if walkKeys || walkValues {
+			iter := v.MapRange()
+			for iter.Next() {
+				if walkKeys {
+					size += t.walk(iter.Key(), depth+1)
+				}
+				if walkValues {
+					size += t.walk(iter.Value(), depth+1)
+				}
+			}
+		}
+		return size
+	case reflect.Chan:
+		if v.IsNil() || !t.enter(v.Pointer(), v.Type()) {
+			return 0
+		}
+		return int64(v.Cap()) * int64(v.Type().Elem().Size())
+	case reflect.Struct:
+		var size int64
+		for i := 0; i < v.NumField(); i++ {
+			if hasReferences(v.Type().Field(i).Type) {
+				size += t.walk(v.Field(i), depth)
+			}
+		}
+		return size
+	}
+	return 0
+}
+
+/**
+Checks if values of the type could reference memory outside of their inline size
+*/
+func hasReferences(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.String, reflect.Slice, reflect.Map, reflect.Chan:
+		return true
+	case reflect.Array:
+		return typ.Len() > 0 && hasReferences(typ.Elem())
+	case reflect.Struct:
+		for i := 0; i < typ.NumField(); i++ {
+			if hasReferences(typ.Field(i).Type) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type blobCache struct {
+	entries map[string][]byte
+}
+
+type blobIndex struct {
+	Cache *blobCache `inject`
+	keys  []string
+}
+
+type lazyReport struct {
+}
+
+func TestContextStats(t *testing.T) {
+
+	cache := &blobCache{entries: map[string][]byte{
+		"a": make([]byte, 1<<20),
+		"b": make([]byte, 1<<10),
+	}}
+	index := &blobIndex{keys: []string{"a", "b"}}
+
+	ctx, err := glue.New(
+		glue.MemoryAnalyzer{},
+		cache,
+		index,
+		glue.Lazy(&lazyReport{}),
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	stats := ctx.Stats()
+	require.True(t, stats.Beans >= 3)
+	require.Equal(t, stats.Beans-1, stats.Initialized)
+
+	var cacheSize, indexSize int64
+	for _, m := range stats.Memory {
+		switch m.Type {
+		case "*glue_test.blobCache":
+			cacheSize = m.Size
+			require.False(t, m.Truncated)
+		case "*glue_test.blobIndex":
+			indexSize = m.Size
+		}
+	}
+	require.Equal(t, "*glue_test.blobCache", stats.Memory[0].Type)
+	require.True(t, cacheSize > 1<<20+1<<10)
+	require.True(t, indexSize < 1<<10)
+
+	plain, err := glue.New(cache)
+	require.NoError(t, err)
+	defer plain.Close()
+	require.Empty(t, plain.Stats().Memory)
+
+	limited, err := glue.New(glue.MemoryAnalyzer{MaxObjects: 2}, cache)
+	require.NoError(t, err)
+	defer limited.Close()
+	for _, m := range limited.Stats().Memory {
+		if m.Type == "*glue_test.blobCache" {
+			require.True(t, m.Truncated)
+		}
+	}
+}
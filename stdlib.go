@@ -0,0 +1,275 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sync"
+	"time"
+)
+
+/**
+Ready-made beans of the standard library: *http.Client configured from properties, Clock and rand.Source.
+
+Example:
+	glue.New(
+		glue.StandardBeans(),
+		&service{},
+	)
+
+	type service struct {
+		Client *http.Client `inject`
+		Clock  glue.Clock   `inject`
+		Rand   rand.Source  `inject`
+	}
+*/
+func StandardBeans() interface{} {
+	return []interface{}{
+		&HttpClientFactory{},
+		&SystemClock{},
+		&RandSourceFactory{},
+	}
+}
+
+/**
+Factory of *http.Client tuned by 'http.client.*' properties
+*/
+type HttpClientFactory struct {
+	Timeout             time.Duration `value:"http.client.timeout,default=30s"`
+	DialTimeout         time.Duration `value:"http.client.dial.timeout,default=30s"`
+	TLSHandshakeTimeout time.Duration `value:"http.client.tls.handshake.timeout,default=10s"`
+	IdleConnTimeout     time.Duration `value:"http.client.idle.timeout,default=90s"`
+	MaxIdleConns        int           `value:"http.client.max.idle.conns,default=100"`
+	MaxIdleConnsPerHost int           `value:"http.client.max.idle.conns.per.host,default=2"`
+
+	/**
+	Url of the proxy, proxy is taken from environment variables if empty
+	*/
+	Proxy string `value:"http.client.proxy,default="`
+
+	/**
+	PEM file with additional root certificates
+	*/
+	CAFile             string `value:"http.client.tls.ca.file,default="`
+	InsecureSkipVerify bool   `value:"http.client.tls.insecure,default=false"`
+}
+
+var httpClientClass = reflect.TypeOf((*http.Client)(nil))
+
+func (t *HttpClientFactory) Object() (interface{}, error) {
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{Timeout: t.DialTimeout, KeepAlive: 30 * time.Second}).DialContext
+	transport.TLSHandshakeTimeout = t.TLSHandshakeTimeout
+	transport.IdleConnTimeout = t.IdleConnTimeout
+	transport.MaxIdleConns = t.MaxIdleConns
+	transport.MaxIdleConnsPerHost = t.MaxIdleConnsPerHost
+
+	if t.Proxy != "" {
+		proxy, err := url.Parse(t.Proxy)
+		if err != nil {
+			return nil, errors.Errorf("invalid http client proxy '%s', %v", t.Proxy, err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	if t.CAFile != "" || t.InsecureSkipVerify {
+		tlsConfig := &tls.Config{InsecureSkipVerify: t.InsecureSkipVerify}
+		if t.CAFile != "" {
+			pem, err := ioutil.ReadFile(t.CAFile)
+			if err != nil {
+				return nil, errors.Errorf("read http client CA file '%s', %v", t.CAFile, err)
+			}
+			pool, err := x509.SystemCertPool()
+			if err != nil || pool == nil {
+				pool = x509.NewCertPool()
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, errors.Errorf("no certificates found in http client CA file '%s'", t.CAFile)
+			}
+			tlsConfig.RootCAs = pool
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	return &http.Client{Transport: transport, Timeout: t.Timeout}, nil
+}
+
+func (t *HttpClientFactory) ObjectType() reflect.Type {
+	return httpClientClass
+}
+
+func (t *HttpClientFactory) ObjectName() string {
+	return ""
+}
+
+func (t *HttpClientFactory) Singleton() bool {
+	return true
+}
+
+/**
+Source of the current time, inject Clock instead of calling time.Now to make the code testable with ManualClock
+*/
+var ClockClass = reflect.TypeOf((*Clock)(nil)).Elem()
+
+type Clock interface {
+
+	/**
+	Returns current time
+	*/
+	Now() time.Time
+
+	/**
+	Returns time elapsed since t
+	*/
+	Since(t time.Time) time.Duration
+
+	/**
+	Returns channel receiving the time after d elapsed
+	*/
+	After(d time.Duration) <-chan time.Time
+
+	/**
+	Blocks for d
+	*/
+	Sleep(d time.Duration)
+}
+
+/**
+Clock backed by the time package
+*/
+type SystemClock struct {
+}
+
+func (t *SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (t *SystemClock) Since(tm time.Time) time.Duration {
+	return time.Since(tm)
+}
+
+func (t *SystemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func (t *SystemClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+/**
+Clock for tests, the time moves only by Add and Set calls
+*/
+type ManualClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []clockWaiter
+}
+
+type clockWaiter struct {
+	deadline time.Time
+	ch       chan time.Time
+}
+
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{now: now}
+}
+
+func (t *ManualClock) Now() time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.now
+}
+
+func (t *ManualClock) Since(tm time.Time) time.Duration {
+	return t.Now().Sub(tm)
+}
+
+func (t *ManualClock) After(d time.Duration) <-chan time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	deadline := t.now.Add(d)
+	if d <= 0 {
+		ch <- t.now
+	} else {
+		t.waiters = append(t.waiters, clockWaiter{deadline: deadline, ch: ch})
+	}
+	return ch
+}
+
+func (t *ManualClock) Sleep(d time.Duration) {
+	<-t.After(d)
+}
+
+/**
+Moves the time forward by d and fires expired After channels
+*/
+func (t *ManualClock) Add(d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.set(t.now.Add(d))
+}
+
+/**
+Sets the time and fires expired After channels
+*/
+func (t *ManualClock) Set(now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.set(now)
+}
+
+func (t *ManualClock) set(now time.Time) {
+	t.now = now
+	var pending []clockWaiter
+	for _, w := range t.waiters {
+		if now.Before(w.deadline) {
+			pending = append(pending, w)
+		} else {
+			w.ch <- now
+		}
+	}
+	t.waiters = pending
+}
+
+/**
+Factory of rand.Source, every injection gets own source because rand.Source is not safe for concurrent use.
+Source is seeded by 'rand.seed' property, or by the current time if the property is zero.
+*/
+type RandSourceFactory struct {
+	Seed int64 `value:"rand.seed,default=0"`
+}
+
+var randSourceClass = reflect.TypeOf((*rand.Source)(nil)).Elem()
+
+func (t *RandSourceFactory) Object() (interface{}, error) {
+	seed := t.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return rand.NewSource(seed), nil
+}
+
+func (t *RandSourceFactory) ObjectType() reflect.Type {
+	return randSourceClass
+}
+
+func (t *RandSourceFactory) ObjectName() string {
+	return ""
+}
+
+func (t *RandSourceFactory) Singleton() bool {
+	return false
+}
@@ -0,0 +1,116 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+type stdlibService struct {
+	Client *http.Client `inject`
+	Clock  glue.Clock   `inject`
+	Rand   rand.Source  `inject`
+}
+
+func TestStandardBeans(t *testing.T) {
+
+	p := glue.NewProperties()
+	p.Set("http.client.timeout", "5s")
+	p.Set("http.client.proxy", "http://proxy.local:3128")
+	p.Set("rand.seed", "42")
+
+	service := &stdlibService{}
+	ctx, err := glue.New(
+		p,
+		glue.StandardBeans(),
+		service,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.Equal(t, 5*time.Second, service.Client.Timeout)
+	transport := service.Client.Transport.(*http.Transport)
+	req, _ := http.NewRequest("GET", "http://example.com", nil)
+	proxy, err := transport.Proxy(req)
+	require.NoError(t, err)
+	require.Equal(t, "proxy.local:3128", proxy.Host)
+
+	require.Equal(t, rand.NewSource(42).Int63(), service.Rand.Int63())
+	require.IsType(t, &glue.SystemClock{}, service.Clock)
+}
+
+func TestHttpClientDialTimeout(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	p := glue.NewProperties()
+	p.Set("http.client.dial.timeout", "1ns")
+
+	service := &stdlibService{}
+	ctx, err := glue.New(
+		p,
+		glue.StandardBeans(),
+		service,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	_, err = service.Client.Get(server.URL)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "timeout")
+
+	service = &stdlibService{}
+	ctx, err = glue.New(
+		glue.StandardBeans(),
+		service,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	resp, err := service.Client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
+
+func TestManualClock(t *testing.T) {
+
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := glue.NewManualClock(start)
+
+	ch := clock.After(time.Minute)
+	clock.Add(30 * time.Second)
+	select {
+	case <-ch:
+		require.Fail(t, "fired too early")
+	default:
+	}
+
+	clock.Add(30 * time.Second)
+	require.Equal(t, start.Add(time.Minute), <-ch)
+	require.Equal(t, time.Minute, clock.Since(start))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			clock.Add(time.Second)
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, time.Minute+100*time.Second, clock.Since(start))
+}
@@ -30,7 +30,7 @@ type orderedBeanStub struct {
 }
 
 func (t *orderedBeanStub) BeanOrder() int {
-	return 0
+	return OrderDefault
 }
 
 /**
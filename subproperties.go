@@ -0,0 +1,241 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+)
+
+/**
+Live view of properties scoped to the prefix, all keys are relative to the prefix.
+Resolvers, interceptors and error handler are shared with the underlying properties.
+*/
+type subProperties struct {
+	parent Properties
+	prefix string
+}
+
+func newSubProperties(parent Properties, prefix string) Properties {
+	if prefix != "" && !strings.HasSuffix(prefix, ".") {
+		prefix += "."
+	}
+	return &subProperties{parent: parent, prefix: prefix}
+}
+
+func (t *subProperties) String() string {
+	return fmt.Sprintf("Properties{prefix=%s,parent=%v}", t.prefix, t.parent)
+}
+
+func (t *subProperties) key(key string) string {
+	return t.prefix + key
+}
+
+func (t *subProperties) Priority() int {
+	return t.parent.Priority()
+}
+
+func (t *subProperties) GetProperty(key string) (string, bool) {
+	return t.parent.GetProperty(t.key(key))
+}
+
+func (t *subProperties) Register(resolver PropertyResolver) {
+	t.parent.Register(resolver)
+}
+
+func (t *subProperties) Unregister(resolver PropertyResolver) bool {
+	return t.parent.Unregister(resolver)
+}
+
+func (t *subProperties) Explain(key string) []ResolutionStep {
+	return t.parent.Explain(t.key(key))
+}
+
+func (t *subProperties) PropertyResolvers() []PropertyResolver {
+	return t.parent.PropertyResolvers()
+}
+
+func (t *subProperties) Intercept(interceptor PropertyInterceptor) {
+	t.parent.Intercept(interceptor)
+}
+
+func (t *subProperties) PropertyInterceptors() []PropertyInterceptor {
+	return t.parent.PropertyInterceptors()
+}
+
+func (t *subProperties) LoadMap(source map[string]interface{}) {
+	holder := NewProperties()
+	holder.LoadMap(source)
+	t.copyFrom(holder)
+}
+
+func (t *subProperties) Load(reader io.Reader) error {
+	content, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	return t.Parse(string(content))
+}
+
+func (t *subProperties) Save(writer io.Writer) (n int, err error) {
+	return writer.Write([]byte(t.Dump()))
+}
+
+func (t *subProperties) Parse(content string) error {
+	holder := NewProperties()
+	if err := holder.Parse(content); err != nil {
+		return err
+	}
+	t.copyFrom(holder)
+	return nil
+}
+
+func (t *subProperties) copyFrom(holder Properties) {
+	for key, value := range holder.Map() {
+		t.Set(key, value)
+		if comments := holder.GetComments(key); len(comments) > 0 {
+			t.SetComments(key, comments)
+		}
+	}
+}
+
+func (t *subProperties) Dump() string {
+	holder := NewProperties()
+	for key, value := range t.Map() {
+		holder.Set(key, value)
+		holder.SetComments(key, t.GetComments(key))
+		if t.IsSensitive(key) {
+			holder.MarkSensitive(key)
+		}
+	}
+	return holder.Dump()
+}
+
+func (t *subProperties) Extend(parent Properties) {
+	t.parent.Extend(parent)
+}
+
+func (t *subProperties) Sub(prefix string) Properties {
+	return newSubProperties(t.parent, t.key(prefix))
+}
+
+func (t *subProperties) Push(overlay map[string]string) (pop func()) {
+	prefixed := make(map[string]string, len(overlay))
+	for key, value := range overlay {
+		prefixed[t.key(key)] = value
+	}
+	return t.parent.Push(prefixed)
+}
+
+func (t *subProperties) Len() int {
+	return len(t.Keys())
+}
+
+func (t *subProperties) Keys() []string {
+	var keys []string
+	for _, key := range t.parent.Keys() {
+		if strings.HasPrefix(key, t.prefix) {
+			keys = append(keys, key[len(t.prefix):])
+		}
+	}
+	return keys
+}
+
+func (t *subProperties) Map() map[string]string {
+	m := make(map[string]string)
+	for key, value := range t.parent.Map() {
+		if strings.HasPrefix(key, t.prefix) {
+			m[key[len(t.prefix):]] = value
+		}
+	}
+	return m
+}
+
+func (t *subProperties) Contains(key string) bool {
+	return t.parent.Contains(t.key(key))
+}
+
+func (t *subProperties) Get(key string) (string, bool) {
+	return t.parent.Get(t.key(key))
+}
+
+func (t *subProperties) GetString(key, def string) string {
+	return t.parent.GetString(t.key(key), def)
+}
+
+func (t *subProperties) GetBool(key string, def bool) bool {
+	return t.parent.GetBool(t.key(key), def)
+}
+
+func (t *subProperties) GetInt(key string, def int) int {
+	return t.parent.GetInt(t.key(key), def)
+}
+
+func (t *subProperties) GetFloat(key string, def float32) float32 {
+	return t.parent.GetFloat(t.key(key), def)
+}
+
+func (t *subProperties) GetDouble(key string, def float64) float64 {
+	return t.parent.GetDouble(t.key(key), def)
+}
+
+func (t *subProperties) GetDuration(key string, def time.Duration) time.Duration {
+	return t.parent.GetDuration(t.key(key), def)
+}
+
+func (t *subProperties) GetFileMode(key string, def os.FileMode) os.FileMode {
+	return t.parent.GetFileMode(t.key(key), def)
+}
+
+func (t *subProperties) MarkSensitive(keys ...string) {
+	for _, key := range keys {
+		t.parent.MarkSensitive(t.key(key))
+	}
+}
+
+func (t *subProperties) IsSensitive(key string) bool {
+	return t.parent.IsSensitive(t.key(key))
+}
+
+func (t *subProperties) GetErrorHandler() func(string, error) {
+	return t.parent.GetErrorHandler()
+}
+
+func (t *subProperties) SetErrorHandler(onError func(string, error)) {
+	t.parent.SetErrorHandler(onError)
+}
+
+func (t *subProperties) Set(key string, value string) {
+	t.parent.Set(t.key(key), value)
+}
+
+func (t *subProperties) Remove(key string) bool {
+	return t.parent.Remove(t.key(key))
+}
+
+func (t *subProperties) Clear() {
+	for _, key := range t.Keys() {
+		t.Remove(key)
+	}
+}
+
+func (t *subProperties) GetComments(key string) []string {
+	return t.parent.GetComments(t.key(key))
+}
+
+func (t *subProperties) SetComments(key string, comments []string) {
+	t.parent.SetComments(t.key(key), comments)
+}
+
+func (t *subProperties) ClearComments() {
+	for _, key := range t.Keys() {
+		t.SetComments(key, nil)
+	}
+}
@@ -0,0 +1,270 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"github.com/pkg/errors"
+	"reflect"
+	"sync/atomic"
+	"unsafe"
+)
+
+func (t *context) Swap(iface reflect.Type, newImpl interface{}) error {
+
+	classPtr := reflect.TypeOf(newImpl)
+	if classPtr == nil || classPtr.Kind() != reflect.Ptr || classPtr.Elem().Kind() != reflect.Struct {
+		return errors.Errorf("new implementation of '%v' must be a pointer to struct, but was '%v'", iface, classPtr)
+	}
+	if !classPtr.AssignableTo(iface) {
+		return errors.Errorf("new implementation '%v' is not assignable to '%v'", classPtr, iface)
+	}
+
+	/**
+	Lock contexts extending this one before it, the same order as Promote and construction on demand take locks
+	*/
+	extensions := t.extensionTree()
+	for i := len(extensions) - 1; i >= 0; i-- {
+		extensions[i].constructMu.Lock()
+		defer extensions[i].constructMu.Unlock()
+	}
+	t.constructMu.Lock()
+	defer t.constructMu.Unlock()
+
+	list := t.findMatches(iface)
+	switch len(list) {
+	case 0:
+		return errors.Errorf("can not find bean '%v' to swap in context", iface)
+	case 1:
+	default:
+		return errors.Errorf("can not swap '%v' having multiple candidates %+v", iface, list)
+	}
+	old := list[0]
+	if old.beenFactory != nil {
+		return errors.Errorf("can not swap bean '%s' produced by factory '%v'", old.name, old.beenFactory.factoryClassPtr)
+	}
+	if atomic.LoadInt32(&old.runtimeInjected) != 0 {
+		return errors.Errorf("can not swap bean '%s' injected by Inject method in to objects not tracked by the context", old.name)
+	}
+	all := append([]*context{t}, extensions...)
+	if classPtr != old.beanDef.classPtr {
+		for _, ctx := range all {
+			for _, inject := range ctx.injections {
+				if inject.holds(old, classPtr) {
+					return errors.Errorf("can not swap bean '%s' by other type '%v', field '%s' in class '%v' holding it could not be updated atomically",
+						old.name, classPtr, inject.injectionDef.fieldName, inject.injectionDef.class)
+				}
+			}
+		}
+	}
+
+	/**
+	Construct the new implementation before any change
+	*/
+	b, err := investigate(newImpl, classPtr)
+	if err != nil {
+		return err
+	}
+	if b.qualifier == "" {
+		b.name = t.beanName(classPtr)
+	}
+	plan, err := t.planInjection(newImpl, t.logger())
+	if err != nil {
+		return err
+	}
+	plan.bean = b
+	if err := plan.apply(t, newImpl, t.properties, t.logger()); err != nil {
+		return err
+	}
+	b.setLifecycle(BeanConstructing)
+	if init, ok := newImpl.(InitializingBean); ok {
+		if err := init.PostConstruct(); err != nil {
+			return errors.Errorf("post construct of '%v' on swap failed, %v", classPtr, err)
+		}
+	}
+	atomic.StoreInt32(&b.postConstructed, 1)
+	b.setLifecycle(BeanInitialized)
+
+	if t.logger() != nil {
+		t.logger().Printf("Swap bean '%s' with type '%v' by '%s' with type '%v'\n", old.name, old.beanDef.classPtr, b.name, classPtr)
+	}
+
+	/**
+	Repoint fields and dependencies of beans of the context and of contexts extending it, all of them are locked
+	*/
+	for _, ctx := range all {
+		for _, inject := range ctx.injections {
+			if err := inject.swap(old, b); err != nil {
+				return err
+			}
+		}
+		for _, list := range ctx.coreBeans() {
+			for _, other := range list {
+				other.dependencies = replaceBean(other.dependencies, old, b)
+			}
+		}
+	}
+
+	/**
+	Replace the bean in context structures
+	*/
+	t.coreMu.Lock()
+	defer t.coreMu.Unlock()
+	current := t.coreBeans()
+	core := make(map[reflect.Type][]*bean, len(current))
+	for typ, list := range current {
+		core[typ] = replaceBean(list, old, b)
+	}
+	for typ, list := range core {
+		if typ == old.beanDef.classPtr {
+			core[typ] = removeBean(list, b)
+		}
+	}
+	registerBean(core, classPtr, b)
+	t.core.Store(core)
+	old.moveConsumers(b)
+	t.registry.replace(old, b)
+	t.disposablesMu.Lock()
+	t.disposables = replaceBean(t.disposables, old, b)
+	t.disposablesMu.Unlock()
+
+	/**
+	Dispose the old bean
+	*/
+	return t.destroyBean(old, SwapReason)
+}
+
+/**
+Returns contexts extending this one and not closed yet, parents go before their extensions
+*/
+func (t *context) extensionTree() []*context {
+	t.extensionsMu.Lock()
+	list := append([]*context(nil), t.extensions...)
+	t.extensionsMu.Unlock()
+	for _, ctx := range list {
+		list = append(list, ctx.extensionTree()...)
+	}
+	return list
+}
+
+func (t *context) attachExtension(ctx *context) {
+	t.extensionsMu.Lock()
+	t.extensions = append(t.extensions, ctx)
+	t.extensionsMu.Unlock()
+}
+
+func (t *context) detachExtension(ctx *context) {
+	t.extensionsMu.Lock()
+	var list []*context
+	for _, item := range t.extensions {
+		if item != ctx {
+			list = append(list, item)
+		}
+	}
+	t.extensions = list
+	t.extensionsMu.Unlock()
+}
+
+/**
+Checks if the field of the injection holds the bean in place, so it could not be repointed atomically to other type.
+Maps are replaced as a whole, they only need to accept the other type.
+*/
+func (t *injection) holds(old *bean, classPtr reflect.Type) bool {
+	field := t.value.Field(t.injectionDef.fieldNum)
+	switch {
+	case t.injectionDef.slice:
+		for i := 0; i < field.Len(); i++ {
+			if item := field.Index(i); !item.IsNil() && item.Interface() == old.obj {
+				return true
+			}
+		}
+	case t.injectionDef.table:
+		if field.IsNil() || classPtr.AssignableTo(field.Type().Elem()) {
+			return false
+		}
+		iter := field.MapRange()
+		for iter.Next() {
+			if iter.Value().Interface() == old.obj {
+				return true
+			}
+		}
+	default:
+		return !field.IsNil() && field.Interface() == old.obj
+	}
+	return false
+}
+
+/**
+Repoint the field of the injection from the old bean to the new one
+*/
+func (t *injection) swap(old, b *bean) error {
+	field := t.value.Field(t.injectionDef.fieldNum)
+	switch {
+	case t.injectionDef.slice:
+		for i := 0; i < field.Len(); i++ {
+			swapValue(field.Index(i), old, b)
+		}
+	case t.injectionDef.table:
+		if field.IsNil() {
+			return nil
+		}
+		var changed bool
+		m := reflect.MakeMapWithSize(field.Type(), field.Len())
+		iter := field.MapRange()
+		for iter.Next() {
+			value := iter.Value()
+			if value.Interface() == old.obj {
+				value = b.valuePtr
+				changed = true
+			}
+			m.SetMapIndex(iter.Key(), value)
+		}
+		if changed {
+			atomicSet(field, m)
+		}
+	default:
+		swapValue(field, old, b)
+	}
+	return nil
+}
+
+/**
+Set the new bean to the value if it holds the old bean.
+Pointers are stored atomically, interfaces hold the same concrete type checked by Swap, so only the data word is stored.
+*/
+func swapValue(field reflect.Value, old, b *bean) {
+	if field.IsNil() || field.Interface() != old.obj {
+		return
+	}
+	switch field.Kind() {
+	case reflect.Ptr:
+		atomicSet(field, b.valuePtr)
+	case reflect.Interface:
+		data := (*unsafe.Pointer)(unsafe.Add(field.Addr().UnsafePointer(), unsafe.Sizeof(uintptr(0))))
+		atomic.StorePointer(data, b.valuePtr.UnsafePointer())
+	}
+}
+
+func replaceBean(list []*bean, old, b *bean) []*bean {
+	for i, item := range list {
+		if item == old {
+			c := make([]*bean, len(list))
+			copy(c, list)
+			c[i] = b
+			return c
+		}
+	}
+	return list
+}
+
+func removeBean(list []*bean, b *bean) []*bean {
+	var out []*bean
+	for _, item := range list {
+		if item != b {
+			out = append(out, item)
+		}
+	}
+	return out
+}
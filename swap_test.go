@@ -0,0 +1,146 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"reflect"
+	"testing"
+)
+
+var StrategyClass = reflect.TypeOf((*Strategy)(nil)).Elem()
+
+type Strategy interface {
+	Algorithm() string
+}
+
+type strategyImpl struct {
+	name      string
+	Storage   *reportStorage `inject`
+	destroyed bool
+}
+
+func (t *strategyImpl) Algorithm() string {
+	return t.name
+}
+
+func (t *strategyImpl) BeanName() string {
+	return "strategy"
+}
+
+func (t *strategyImpl) Destroy() error {
+	t.destroyed = true
+	return nil
+}
+
+type fastStrategy struct {
+	Storage *reportStorage `inject`
+}
+
+func (t *fastStrategy) Algorithm() string {
+	return "fast"
+}
+
+type strategyHolder struct {
+	Strategy   Strategy            `inject`
+	Strategies []Strategy          `inject`
+	Named      map[string]Strategy `inject`
+}
+
+func TestSwap(t *testing.T) {
+
+	old := &strategyImpl{name: "slow"}
+	holder := &strategyHolder{}
+
+	ctx, err := glue.New(
+		&reportStorage{},
+		old,
+		holder,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.Equal(t, "slow", holder.Strategy.Algorithm())
+	require.Equal(t, 1, len(ctx.Bean(StrategyClass, glue.DefaultLevel)))
+
+	// same concrete type
+	next := &strategyImpl{name: "medium"}
+	require.NoError(t, ctx.Swap(StrategyClass, next))
+	require.True(t, old.destroyed)
+	require.NotNil(t, next.Storage)
+	require.Equal(t, "medium", holder.Strategy.Algorithm())
+	require.Equal(t, "medium", holder.Strategies[0].Algorithm())
+	require.Equal(t, "medium", holder.Named["strategy"].Algorithm())
+
+	// other concrete type held by interface fields
+	err = ctx.Swap(StrategyClass, &fastStrategy{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "could not be updated atomically")
+	require.False(t, next.destroyed)
+	require.Equal(t, "medium", holder.Strategy.Algorithm())
+
+	list := ctx.Bean(StrategyClass, glue.DefaultLevel)
+	require.Equal(t, 1, len(list))
+	require.Equal(t, "medium", list[0].Object().(Strategy).Algorithm())
+
+	require.Error(t, ctx.Swap(StrategyClass, &reportStorage{}))
+
+	// runtime injections are not tracked
+	require.NoError(t, ctx.Inject(&strategyHolder{}))
+	require.Error(t, ctx.Swap(StrategyClass, &strategyImpl{name: "fast"}))
+}
+
+func TestSwapOtherType(t *testing.T) {
+
+	holder := &struct {
+		Named map[string]Strategy `inject`
+	}{}
+
+	ctx, err := glue.New(
+		&reportStorage{},
+		&strategyImpl{name: "slow"},
+		holder,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.NoError(t, ctx.Swap(StrategyClass, &fastStrategy{}))
+	require.Equal(t, 1, len(holder.Named))
+	for _, s := range holder.Named {
+		require.Equal(t, "fast", s.Algorithm())
+	}
+}
+
+func TestSwapChild(t *testing.T) {
+
+	holder := &strategyHolder{}
+
+	ctx, err := glue.New(
+		&reportStorage{},
+		&strategyImpl{name: "slow"},
+		glue.Child("child", holder),
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	_, err = ctx.Children()[0].Object()
+	require.NoError(t, err)
+	require.Equal(t, "slow", holder.Strategy.Algorithm())
+
+	require.NoError(t, ctx.Swap(StrategyClass, &strategyImpl{name: "fast"}))
+	require.Equal(t, "fast", holder.Strategy.Algorithm())
+	require.Equal(t, "fast", holder.Strategies[0].Algorithm())
+
+	extended := &strategyHolder{}
+	ext, err := ctx.Extend(extended)
+	require.NoError(t, err)
+	defer ext.Close()
+
+	require.NoError(t, ctx.Swap(StrategyClass, &strategyImpl{name: "faster"}))
+	require.Equal(t, "faster", holder.Strategy.Algorithm())
+	require.Equal(t, "faster", extended.Strategy.Algorithm())
+}
@@ -0,0 +1,107 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"sort"
+	"strings"
+)
+
+/**
+Embed this marker in beans that must not be used by concurrent goroutines.
+Context fails on creation if the bean is reachable by injection from multiple consumers, because consumers run concurrently.
+
+Example:
+	type parser struct {
+		glue.NotThreadSafe
+		buf []byte
+	}
+*/
+type NotThreadSafe struct {
+}
+
+func (NotThreadSafe) notThreadSafe() {}
+
+type notThreadSafeBean interface {
+	notThreadSafe()
+}
+
+/**
+Embed this marker in beans that are safe for concurrent use and guard their dependencies, for example by the mutex.
+Not thread-safe beans injected in to them are not considered shared by consumers that use them.
+
+Example:
+	type syncParser struct {
+		glue.SingletonSafe
+		mu     sync.Mutex
+		Parser *parser `inject:""`
+	}
+*/
+type SingletonSafe struct {
+}
+
+func (SingletonSafe) singletonSafe() {}
+
+type singletonSafeBean interface {
+	singletonSafe()
+}
+
+/**
+Finds not thread-safe beans reachable by injection from more than one consumer of the context,
+the walk does not go through singleton-safe beans
+*/
+func (t *context) checkThreadSafety() error {
+	var consumers []*bean
+	t.eachBean(func(b Bean) bool {
+		if impl, ok := b.(*bean); ok {
+			if _, ok := impl.obj.(Consumer); ok {
+				consumers = append(consumers, impl)
+			}
+		}
+		return true
+	})
+	if len(consumers) < 2 {
+		return nil
+	}
+	shared := make(map[*bean][]string)
+	var order []*bean
+	for _, c := range consumers {
+		visited := make(map[*bean]bool)
+		var walk func(b *bean)
+		walk = func(b *bean) {
+			for _, dep := range b.dependencies {
+				if visited[dep] || dep == c {
+					continue
+				}
+				visited[dep] = true
+				if _, ok := dep.obj.(singletonSafeBean); ok {
+					continue
+				}
+				if _, ok := dep.obj.(notThreadSafeBean); ok {
+					if _, ok := shared[dep]; !ok {
+						order = append(order, dep)
+					}
+					shared[dep] = append(shared[dep], c.name)
+				}
+				walk(dep)
+			}
+		}
+		walk(c)
+	}
+	var list []string
+	for _, b := range order {
+		if names := shared[b]; len(names) > 1 {
+			sort.Strings(names)
+			list = append(list, fmt.Sprintf("bean '%s' with type '%v' is not thread-safe, but shared by consumers %s", b.name, b.beanDef.classPtr, strings.Join(names, ", ")))
+		}
+	}
+	if len(list) > 0 {
+		return errors.New(strings.Join(list, "\n"))
+	}
+	return nil
+}
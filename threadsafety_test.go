@@ -0,0 +1,125 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"context"
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+)
+
+type unsafeParser struct {
+	glue.NotThreadSafe
+	buf []byte
+}
+
+type syncParser struct {
+	glue.SingletonSafe
+	mu     sync.Mutex
+	Parser *unsafeParser `inject:""`
+}
+
+type parserHolder struct {
+	Parser *unsafeParser `inject:""`
+}
+
+type ordersConsumer struct {
+	Holder *parserHolder `inject:""`
+}
+
+func (t *ordersConsumer) Consume(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+type paymentsConsumer struct {
+	Parser *unsafeParser `inject:""`
+}
+
+func (t *paymentsConsumer) Consume(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+type guardedConsumer struct {
+	Parser *syncParser `inject:""`
+}
+
+func (t *guardedConsumer) Consume(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func TestThreadSafety(t *testing.T) {
+
+	_, err := glue.New(&unsafeParser{}, &parserHolder{}, &ordersConsumer{}, &paymentsConsumer{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is not thread-safe, but shared by consumers *glue_test.ordersConsumer, *glue_test.paymentsConsumer")
+
+	ctx, err := glue.New(&unsafeParser{}, &paymentsConsumer{}, &parserHolder{})
+	require.NoError(t, err)
+	ctx.Close()
+
+	ctx, err = glue.New(&unsafeParser{}, &syncParser{}, &guardedConsumer{}, &paymentsConsumer{})
+	require.NoError(t, err)
+	ctx.Close()
+}
+
+type concurrentStorage interface {
+	Load(key string) string
+}
+
+type concurrentStorageImpl struct {
+}
+
+func (t *concurrentStorageImpl) Load(key string) string {
+	return key
+}
+
+type concurrentService struct {
+	Storage concurrentStorage `inject:""`
+	Parser  *unsafeParser      `inject:""`
+	Name    string             `value:"service.name,default=none"`
+}
+
+func TestConcurrentCreation(t *testing.T) {
+
+	parent, err := glue.New(&concurrentStorageImpl{})
+	require.NoError(t, err)
+	defer parent.Close()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 16)
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			service := &concurrentService{}
+			var ctx glue.Context
+			var err error
+			if i%2 == 0 {
+				ctx, err = parent.Extend(service, &unsafeParser{})
+			} else {
+				ctx, err = glue.New(&concurrentStorageImpl{}, service, &unsafeParser{}, glue.PropertySource{Map: map[string]interface{}{"service.name": "standalone"}})
+			}
+			if err == nil {
+				if service.Storage == nil || service.Parser == nil {
+					err = context.Canceled
+				}
+				ctx.Close()
+			}
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"github.com/pkg/errors"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+/**
+Orders slices injected with 'topo' attribute after wiring, when dependencies of all beans are known
+*/
+func orderTopoInjections(maps ...map[reflect.Type][]*injection) error {
+	for _, m := range maps {
+		for _, injects := range m {
+			for _, inject := range injects {
+				if len(inject.topoList) < 2 {
+					continue
+				}
+				list, err := topoOrder(inject.topoList)
+				if err != nil {
+					return errors.Errorf("field '%s' in class '%v' can not be ordered by dependencies, %v", inject.injectionDef.fieldName, inject.injectionDef.class, err)
+				}
+				field := inject.value.Field(inject.injectionDef.fieldNum)
+				newSlice := reflect.MakeSlice(field.Type(), 0, len(list))
+				for _, b := range list {
+					newSlice = reflect.Append(newSlice, b.valuePtr)
+				}
+				field.Set(newSlice)
+				inject.topoList = nil
+			}
+		}
+	}
+	return nil
+}
+
+/**
+Sorts beans so that the bean goes after all beans of the list it depends on directly or through other beans,
+independent beans keep the order of registration
+*/
+func topoOrder(list []*bean) ([]*bean, error) {
+	list = append([]*bean(nil), list...)
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].seq < list[j].seq
+	})
+	n := len(list)
+	index := make(map[*bean]int, n)
+	for i, b := range list {
+		index[b] = i
+	}
+
+	// after[i] are positions of beans that depend on list[i]
+	after := make([][]int, n)
+	incoming := make([]int, n)
+	for j, b := range list {
+		visited := map[*bean]bool{b: true}
+		var walk func(b *bean)
+		walk = func(b *bean) {
+			for _, dep := range b.dependencies {
+				if visited[dep] {
+					continue
+				}
+				visited[dep] = true
+				if i, ok := index[dep]; ok {
+					after[i] = append(after[i], j)
+					incoming[j]++
+				}
+				walk(dep)
+			}
+		}
+		walk(b)
+	}
+
+	result := make([]*bean, 0, n)
+	done := make([]bool, n)
+	for len(result) < n {
+		next := -1
+		for i := 0; i < n; i++ {
+			if !done[i] && incoming[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next == -1 {
+			var cycle []string
+			for i := 0; i < n; i++ {
+				if !done[i] {
+					cycle = append(cycle, list[i].name)
+				}
+			}
+			return nil, errors.Errorf("dependency cycle between beans %s", strings.Join(cycle, ", "))
+		}
+		done[next] = true
+		result = append(result, list[next])
+		for _, j := range after[next] {
+			incoming[j]--
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,81 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type PipelineStage interface {
+	StageName() string
+}
+
+type auditStage struct {
+}
+
+func (t *auditStage) StageName() string { return "audit" }
+
+type parseStage struct {
+}
+
+func (t *parseStage) StageName() string { return "parse" }
+
+type enrichStage struct {
+	Parse *parseStage `inject:""`
+}
+
+func (t *enrichStage) StageName() string { return "enrich" }
+
+type enrichedStore struct {
+	Enrich *enrichStage `inject:""`
+}
+
+type storeStage struct {
+	Store *enrichedStore `inject:""`
+}
+
+func (t *storeStage) StageName() string { return "store" }
+
+type stagePipeline struct {
+	Stages []PipelineStage `inject:"topo"`
+}
+
+func (t *stagePipeline) names() []string {
+	var list []string
+	for _, s := range t.Stages {
+		list = append(list, s.StageName())
+	}
+	return list
+}
+
+func TestTopologicalSlice(t *testing.T) {
+
+	pipeline := &stagePipeline{}
+	ctx, err := glue.New(
+		&auditStage{},
+		&storeStage{},
+		&enrichedStore{},
+		&enrichStage{},
+		&parseStage{},
+		pipeline,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.Equal(t, []string{"audit", "parse", "enrich", "store"}, pipeline.names())
+
+	runtime := &stagePipeline{}
+	require.NoError(t, ctx.Inject(runtime))
+	require.Equal(t, []string{"audit", "parse", "enrich", "store"}, runtime.names())
+
+	_, err = glue.New(&parseStage{}, &struct {
+		Stage PipelineStage `inject:"topo"`
+	}{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "'topo' attribute is allowed only for slice field")
+}
@@ -0,0 +1,42 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import "fmt"
+
+/**
+Keys of attributes returned by SpanAttributes
+*/
+const (
+	SpanBeanName    = "glue.bean.name"
+	SpanBeanType    = "glue.bean.type"
+	SpanContextRole = "glue.context.role"
+)
+
+/**
+Returns attributes of spans and log records describing the callee bean and the role of its context,
+so latency of calls in applications with multiple child contexts is attributed to the right subsystem, ctx is the context of the bean.
+Spans are not annotated automatically, the tracing decorator of the bean written by hand sets them on spans it starts.
+
+Example:
+	list := ctx.Bean(reflect.TypeOf((*Payments)(nil)).Elem(), glue.DefaultLevel)
+	attrs := glue.SpanAttributes(ctx, list[0])
+
+	span := tracer.Start(ctx, "charge")
+	for k, v := range attrs {
+		span.SetAttributes(attribute.String(k, v))
+	}
+*/
+func SpanAttributes(ctx Context, b Bean) map[string]string {
+	attrs := map[string]string{
+		SpanBeanName: b.Name(),
+		SpanBeanType: fmt.Sprint(b.Class()),
+	}
+	if role := ctx.Role(); role != "" {
+		attrs[SpanContextRole] = role
+	}
+	return attrs
+}
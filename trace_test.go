@@ -0,0 +1,46 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"reflect"
+	"testing"
+)
+
+type tracedPayments struct {
+}
+
+func TestSpanAttributes(t *testing.T) {
+
+	parent, err := glue.New(
+		glue.Child("billing", &tracedPayments{}),
+	)
+	require.NoError(t, err)
+	defer parent.Close()
+
+	require.Equal(t, "", parent.Role())
+
+	child, err := parent.Children()[0].Object()
+	require.NoError(t, err)
+	require.Equal(t, "billing", child.Role())
+
+	list := child.Bean(reflect.TypeOf((*tracedPayments)(nil)), glue.DefaultLevel)
+	require.Equal(t, 1, len(list))
+
+	require.Equal(t, map[string]string{
+		glue.SpanBeanName:    list[0].Name(),
+		glue.SpanBeanType:    "*glue_test.tracedPayments",
+		glue.SpanContextRole: "billing",
+	}, glue.SpanAttributes(child, list[0]))
+
+	// extended context keeps the role of the child
+	extended, err := child.Extend()
+	require.NoError(t, err)
+	defer extended.Close()
+	require.Equal(t, "billing", extended.Role())
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	stdcontext "context"
+	"github.com/pkg/errors"
+	"reflect"
+)
+
+/**
+Transaction started by TxManager
+*/
+type Tx interface {
+
+	/**
+	Commits the transaction
+	*/
+	Commit() error
+
+	/**
+	Rolls back the transaction
+	*/
+	Rollback() error
+}
+
+/**
+This interface used by beans that start transactions of the unit of work, for example wrappers of sql.DB or outbox stores.
+*/
+var TxManagerClass = reflect.TypeOf((*TxManager)(nil)).Elem()
+
+type TxManager interface {
+
+	/**
+	Begins a new transaction
+	*/
+	Begin(ctx stdcontext.Context) (Tx, error)
+}
+
+type txKey struct {
+	tm TxManager
+}
+
+/**
+Returns the transaction of the TxManager bound to ctx by Transactional
+*/
+func TxFromContext(ctx stdcontext.Context, tm TxManager) (Tx, bool) {
+	tx, ok := ctx.Value(txKey{tm}).(Tx)
+	return tx, ok
+}
+
+/**
+Runs fn inside the transaction of TxManager, commits it if fn returns nil and rolls back on error or panic.
+The transaction is bound to ctx passed to fn, nested calls with the same TxManager join the outer transaction.
+Service methods call it explicitly with the injected TxManager bean, methods selected by matchers are not wrapped in transactions automatically.
+
+Example:
+	type orderService struct {
+		TxManager glue.TxManager `inject`
+	}
+
+	func (t *orderService) Place(ctx context.Context, order *Order) error {
+		return glue.Transactional(ctx, t.TxManager, func(ctx context.Context) error {
+			...
+		})
+	}
+*/
+func Transactional(ctx stdcontext.Context, tm TxManager, fn func(ctx stdcontext.Context) error) (err error) {
+
+	if tm == nil {
+		return errors.New("transaction manager is nil")
+	}
+
+	if _, ok := TxFromContext(ctx, tm); ok {
+		return fn(ctx)
+	}
+
+	tx, err := tm.Begin(ctx)
+	if err != nil {
+		return errors.Errorf("begin transaction, %v", err)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	if err = fn(stdcontext.WithValue(ctx, txKey{tm}, tx)); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return errors.Errorf("%v, rollback failed, %v", err, rollbackErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"context"
+	"errors"
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type recordingTx struct {
+	tm *recordingTxManager
+}
+
+func (t *recordingTx) Commit() error {
+	t.tm.events = append(t.tm.events, "commit")
+	return nil
+}
+
+func (t *recordingTx) Rollback() error {
+	t.tm.events = append(t.tm.events, "rollback")
+	return nil
+}
+
+type recordingTxManager struct {
+	events []string
+}
+
+func (t *recordingTxManager) Begin(ctx context.Context) (glue.Tx, error) {
+	t.events = append(t.events, "begin")
+	return &recordingTx{tm: t}, nil
+}
+
+type txOrderService struct {
+	TxManager glue.TxManager `inject`
+}
+
+func (t *txOrderService) Place(ctx context.Context, fail bool) error {
+	return glue.Transactional(ctx, t.TxManager, func(ctx context.Context) error {
+		return glue.Transactional(ctx, t.TxManager, func(ctx context.Context) error {
+			if _, ok := glue.TxFromContext(ctx, t.TxManager); !ok {
+				return errors.New("no transaction")
+			}
+			if fail {
+				return errors.New("out of stock")
+			}
+			return nil
+		})
+	})
+}
+
+func TestTransactional(t *testing.T) {
+
+	tm := &recordingTxManager{}
+	service := &txOrderService{}
+
+	ctx, err := glue.New(tm, service)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.NoError(t, service.Place(context.Background(), false))
+	require.Equal(t, []string{"begin", "commit"}, tm.events)
+
+	tm.events = nil
+	require.EqualError(t, service.Place(context.Background(), true), "out of stock")
+	require.Equal(t, []string{"begin", "rollback"}, tm.events)
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"github.com/pkg/errors"
+	"reflect"
+)
+
+/**
+Checks wiring of the application without starting it: scans beans, resolves candidates of injections and properties of beans
+and detects cycle dependencies, but does not call PostConstruct, Object of factories, consumers and runners.
+Errors are the same as New would return, so tests of huge applications could verify wiring quickly
+without starting servers or opening connections.
+
+Validation wires the scanned objects the same way as New does, so bean fields of them are injected, property fields stay untouched.
+Pass fresh instances if the objects are used after validation. The context of validation is closed before return.
+
+Example:
+	func TestWiring(t *testing.T) {
+		require.NoError(t, glue.Validate(app.Beans()...))
+	}
+*/
+func Validate(scan ...interface{}) error {
+	ctx, err := createContext(nil, nil, append([]interface{}{dryRun{}}, scan...))
+	if ctx != nil {
+		if closeErr := ctx.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+/**
+Stops creation of the context before construction of beans, used by Validate
+*/
+type dryRun struct{}
+
+func (t dryRun) applyOption(ctx *context) {
+	ctx.dryRun = true
+}
+
+/**
+Walks the construction graph of beans in the order of construction without constructing them
+*/
+func (t *context) validateWiring(lists ...[]*bean) error {
+	done := make(map[*bean]bool)
+	var visit func(b *bean, stack []*bean) error
+	visit = func(b *bean, stack []*bean) error {
+		if done[b] || b.Lifecycle() == BeanInitialized {
+			return nil
+		}
+		for i, s := range stack {
+			if s == b {
+				return errors.Errorf("detected cycle dependency %s", getStackInfo(append(stack[i:], b), "->"))
+			}
+		}
+		for _, dep := range beanDependencies(b) {
+			if err := visit(dep, append(stack, b)); err != nil {
+				return err
+			}
+		}
+		if err := t.validateProperties(b); err != nil {
+			return err
+		}
+		done[b] = true
+		return nil
+	}
+	for _, list := range lists {
+		for _, b := range list {
+			if err := visit(b, nil); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+/**
+Resolves properties of the bean in to the new value, so fields of the bean stay untouched
+*/
+func (t *context) validateProperties(b *bean) error {
+	if b.obj == nil || len(b.beanDef.properties) == 0 {
+		return nil
+	}
+	value := reflect.New(b.valuePtr.Elem().Type()).Elem()
+	properties := b.scopedProperties(t.properties)
+	for _, propertyDef := range b.beanDef.properties {
+		if err := propertyDef.inject(&value, properties); err != nil {
+			return errors.Errorf("property '%s' injection in bean '%s' failed, %v", b.propertyPrefix+propertyDef.propertyName, b.name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"reflect"
+	"testing"
+)
+
+type validateCalls struct {
+	list []string
+}
+
+type validateConn struct {
+	calls *validateCalls
+}
+
+var validateConnClass = reflect.TypeOf((*validateConn)(nil))
+
+type validateConnFactory struct {
+	Calls *validateCalls `inject`
+	URL   string         `value:"db.url"`
+}
+
+func (t *validateConnFactory) Object() (interface{}, error) {
+	t.Calls.list = append(t.Calls.list, "open connection")
+	return &validateConn{calls: t.Calls}, nil
+}
+
+func (t *validateConnFactory) ObjectType() reflect.Type {
+	return validateConnClass
+}
+
+func (t *validateConnFactory) ObjectName() string {
+	return ""
+}
+
+func (t *validateConnFactory) Singleton() bool {
+	return true
+}
+
+type validateServer struct {
+	Calls *validateCalls `inject`
+	Conn  *validateConn  `inject`
+	Port  int            `value:"server.port,default=8080"`
+}
+
+func (t *validateServer) PostConstruct() error {
+	t.Calls.list = append(t.Calls.list, "listen")
+	return nil
+}
+
+type validateA struct {
+	B *validateB `inject`
+}
+
+type validateB struct {
+	A *validateA `inject`
+}
+
+func TestValidate(t *testing.T) {
+
+	calls := &validateCalls{}
+	server := &validateServer{}
+	props := glue.PropertySource{Map: map[string]interface{}{"db.url": "postgres://localhost"}}
+
+	require.NoError(t, glue.Validate(props, calls, &validateConnFactory{}, server))
+	require.Nil(t, calls.list)
+	require.Equal(t, 0, server.Port)
+	require.True(t, server.Calls == calls, "bean fields of scanned objects are injected")
+
+	err := glue.Validate(props, calls, server)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can not find candidates")
+
+	err = glue.Validate(glue.PropertySource{Map: map[string]interface{}{"server.port": "http"}}, props, calls, &validateConnFactory{}, server)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "property 'server.port'")
+
+	_, newErr := glue.New(&validateA{}, &validateB{})
+	err = glue.Validate(&validateA{}, &validateB{})
+	require.Error(t, err)
+	require.Error(t, newErr)
+	require.Contains(t, err.Error(), "detected cycle dependency")
+	require.Contains(t, newErr.Error(), "detected cycle dependency")
+	require.Nil(t, calls.list)
+}
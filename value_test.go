@@ -0,0 +1,55 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type valueLimits struct {
+	Rps int
+}
+
+type valueClient struct {
+	ApiKey  string      `inject:"bean=apiKey"`
+	Region  string      `inject:"bean=region"`
+	Limits  valueLimits `inject`
+	Retries int         `inject:"optional"`
+}
+
+func TestValue(t *testing.T) {
+
+	client := &valueClient{}
+
+	ctx, err := glue.New(
+		glue.Value("apiKey", "secret"),
+		glue.Value("region", "eu"),
+		glue.Value("limits", valueLimits{Rps: 100}),
+		client,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.Equal(t, "secret", client.ApiKey)
+	require.Equal(t, "eu", client.Region)
+	require.Equal(t, 100, client.Limits.Rps)
+	require.Equal(t, 0, client.Retries)
+
+	runtime := &struct {
+		ApiKey string `inject:"bean=apiKey"`
+	}{}
+	require.NoError(t, ctx.Inject(runtime))
+	require.Equal(t, "secret", runtime.ApiKey)
+
+	list := ctx.Lookup("apiKey", glue.DefaultLevel)
+	require.Equal(t, 1, len(list))
+	require.Equal(t, "secret", list[0].Object())
+
+	_, err = glue.New(glue.Value("empty", nil))
+	require.Error(t, err)
+}
@@ -21,3 +21,58 @@ func Verbose(log *log.Logger) (prev *log.Logger) {
 	return
 }
 
+
+/**
+Sets verbose logger of the context being created, passed to Extend by WithVerbose
+*/
+type verboseOption struct {
+	log *log.Logger
+}
+
+func (t verboseOption) applyOption(ctx *context) {
+	ctx.verboseLog = t.log
+}
+
+/**
+Returns verbose logger of the context or the global one if not set
+*/
+func (t *context) logger() *log.Logger {
+	if l, ok := t.eventLog.Load().(*log.Logger); ok && l != nil {
+		return l
+	}
+	if t.verboseLog != nil {
+		return t.verboseLog
+	}
+	return verbose
+}
+
+/**
+View of the context with own verbose logger for runtime injections and extensions
+*/
+type verboseContext struct {
+	*context
+	log *log.Logger
+}
+
+func (t *context) WithVerbose(log *log.Logger) Context {
+	if log == nil {
+		return t
+	}
+	return &verboseContext{context: t, log: log}
+}
+
+func (t *verboseContext) WithVerbose(log *log.Logger) Context {
+	return t.context.WithVerbose(log)
+}
+
+func (t *verboseContext) Extend(scan ...interface{}) (Context, error) {
+	return createContext(t.context, nil, append([]interface{}{verboseOption{t.log}}, scan...))
+}
+
+func (t *verboseContext) Inject(obj interface{}) error {
+	return t.inject(obj, t.log)
+}
+
+func (t *verboseContext) InjectAll(objs ...interface{}) error {
+	return t.injectAll(objs, t.log)
+}
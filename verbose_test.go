@@ -6,9 +6,11 @@
 package glue_test
 
 import (
+	"bytes"
 	"github.com/codeallergy/glue"
 	"github.com/stretchr/testify/require"
 	"log"
+	"strings"
 	"testing"
 )
 
@@ -21,3 +23,70 @@ func TestVerbose(t *testing.T) {
 	require.NotNil(t, prev)
 }
 
+
+type tracedStorage struct {
+}
+
+type tracedHandler struct {
+	Storage  *tracedStorage  `inject`
+	Optional *tracedHandler  `inject:"optional"`
+	Name     string          `value:"handler.name,default=orders"`
+}
+
+type tracedWorker struct {
+}
+
+func (t *tracedWorker) Stop() error {
+	return nil
+}
+
+func TestWithVerbose(t *testing.T) {
+
+	prev := glue.Verbose(nil)
+	defer glue.Verbose(prev)
+
+	ctx, err := glue.New(&tracedStorage{})
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	var buf bytes.Buffer
+	traced := ctx.WithVerbose(log.New(&buf, "", 0))
+
+	handler := &tracedHandler{}
+	err = traced.Inject(handler)
+	require.NoError(t, err)
+	require.NotNil(t, handler.Storage)
+	require.Equal(t, "orders", handler.Name)
+
+	out := buf.String()
+	require.True(t, strings.Contains(out, "Field 'Storage'"), out)
+	require.True(t, strings.Contains(out, "Inject field 'Storage'"), out)
+	require.True(t, strings.Contains(out, "Skip optional field 'Optional'"), out)
+	require.True(t, strings.Contains(out, "Inject property 'handler.name'"), out)
+
+	buf.Reset()
+	err = ctx.Inject(&tracedHandler{})
+	require.NoError(t, err)
+	require.Equal(t, 0, buf.Len())
+
+	err = traced.InjectAll(&tracedHandler{}, &tracedHandler{})
+	require.NoError(t, err)
+	require.Equal(t, 2, strings.Count(buf.String(), "Inject field 'Storage'"))
+
+	buf.Reset()
+	child, err := traced.Extend(&tracedHandler{})
+	require.NoError(t, err)
+	defer child.Close()
+	require.True(t, strings.Contains(buf.String(), "tracedHandler"), buf.String())
+
+	buf.Reset()
+	err = child.Inject(&tracedHandler{})
+	require.NoError(t, err)
+	require.True(t, strings.Contains(buf.String(), "Inject field 'Storage'"), buf.String())
+
+	buf.Reset()
+	worker, err := traced.Extend(&tracedWorker{})
+	require.NoError(t, err)
+	require.NoError(t, worker.Close())
+	require.True(t, strings.Contains(buf.String(), "Stop bean"), buf.String())
+}
@@ -0,0 +1,179 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue
+
+import (
+	"github.com/pkg/errors"
+	"reflect"
+)
+
+/**
+Weak reference is an injectable handle that resolves the bean on demand and does not pin it in the dependency graph.
+Injecting the handle does not create dependency, so the bean is not constructed because of it.
+
+Objects produced by FactoryBean and referenced only by weak handles could be released by Context.ReleaseWeak call,
+the next Get call would produce them again. Combine it with glue.Lazy to avoid construction on startup.
+
+Example:
+	type service struct {
+		Model *glue.WeakRef[*bigModel] `inject`
+	}
+
+	model, err := t.Model.Get()
+*/
+type WeakRef[T any] struct {
+	ctx *context
+	def *injectionDef
+}
+
+/**
+Internal interface implemented by all weak references to bind them on injection
+*/
+type weakReference interface {
+	weakType() reflect.Type
+	bindWeak(ctx *context, def *injectionDef)
+}
+
+var weakReferenceClass = reflect.TypeOf((*weakReference)(nil)).Elem()
+
+func (t *WeakRef[T]) weakType() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func (t *WeakRef[T]) bindWeak(ctx *context, def *injectionDef) {
+	t.ctx = ctx
+	t.def = def
+}
+
+/**
+Resolves the bean, constructs it if needed
+*/
+func (t *WeakRef[T]) Get() (T, error) {
+	var empty T
+	if t.ctx == nil {
+		return empty, errors.Errorf("weak reference on '%v' is not injected", t.weakType())
+	}
+	b, value, err := t.ctx.resolveWeak(t.def)
+	if err != nil {
+		return empty, err
+	}
+	if obj, ok := value.(T); ok {
+		return obj, nil
+	}
+	return empty, errors.Errorf("weak reference on '%v' resolved to incompatible bean '%s'", t.weakType(), b.name)
+}
+
+/**
+Returns true if the weak reference is bound to the context
+*/
+func (t *WeakRef[T]) Bound() bool {
+	return t.ctx != nil
+}
+
+func newWeakReference(fieldType reflect.Type) weakReference {
+	return reflect.New(fieldType.Elem()).Interface().(weakReference)
+}
+
+/**
+Creates weak reference handle for the field and registers weak target type
+*/
+func (t *context) bindWeak(value reflect.Value, def *injectionDef) error {
+	field := value.Field(def.fieldNum)
+	if !field.CanSet() {
+		return errors.Errorf("field '%s' in class '%v' is not public", def.fieldName, def.class)
+	}
+	if !def.optional {
+		deep := t.getBean(def.weakType)
+		if len(deep) == 0 || len(def.selectBeans(deep, nil, t.logger())) == 0 {
+			return errors.Errorf("can not find candidates for weak reference field '%s' in class '%v'", def.fieldName, def.class)
+		}
+	}
+	ref := newWeakReference(def.fieldType)
+	ref.bindWeak(t, def)
+	field.Set(reflect.ValueOf(ref))
+	t.weakTypes.Store(def.weakType, true)
+	return nil
+}
+
+/**
+Resolves the bean of the weak reference and returns its object read under the lock of the owning context,
+so concurrent ReleaseWeak could not drop it in between
+*/
+func (t *context) resolveWeak(def *injectionDef) (*bean, interface{}, error) {
+	deep := t.getBean(def.weakType)
+	if len(deep) == 0 {
+		return nil, nil, errors.Errorf("can not find candidates for weak reference on '%v'", def.weakType)
+	}
+	list := t.lookupBeans(def.weakType, def.selectBeans(deep, nil, t.logger()))
+	switch len(list) {
+	case 0:
+		return nil, nil, errors.Errorf("can not find candidates for weak reference on '%v' on level %d", def.weakType, def.level)
+	case 1:
+	default:
+		return nil, nil, errors.Errorf("weak reference on '%v' has multiple candidates %+v", def.weakType, list)
+	}
+	b := list[0]
+	owner := t
+	for _, entry := range deep {
+		for _, candidate := range entry.list {
+			if candidate == b {
+				owner = t.contextAt(entry.level)
+			}
+		}
+	}
+	owner.constructMu.Lock()
+	defer owner.constructMu.Unlock()
+	if err := owner.constructBean(b, nil); err != nil {
+		return nil, nil, err
+	}
+	return b, b.obj, nil
+}
+
+func (t *context) ReleaseWeak() int {
+	t.constructMu.Lock()
+	defer t.constructMu.Unlock()
+	released := 0
+	t.weakTypes.Range(func(key, value interface{}) bool {
+		for _, b := range t.coreBeans()[key.(reflect.Type)] {
+			if !b.weakReleasable() {
+				continue
+			}
+			if err := t.destroyBean(b, ReleaseWeakReason); err != nil {
+				warnf("Destroy of weak bean '%s' with type '%v' on release failed, %v\n", b.name, b.beanDef.classPtr, err)
+			}
+			if b.releaseWeak() {
+				if t.logger() != nil {
+					t.logger().Printf("Release weak bean '%s' with type '%v'\n", b.name, b.beanDef.classPtr)
+				}
+				released++
+			}
+		}
+		return true
+	})
+	return released
+}
+
+/**
+Checks that the bean is initialized object produced by singleton factory bean and was never injected by strong reference
+*/
+func (t *bean) weakReleasable() bool {
+	return t.beenFactory != nil && !t.injected && t.Lifecycle() == BeanInitialized && t.beenFactory.factoryBean.Singleton()
+}
+
+/**
+Drops the object of the bean destroyed by ReleaseWeak
+*/
+func (t *bean) releaseWeak() bool {
+	switch t.Lifecycle() {
+	case BeanDestroyed, BeanDestroying:
+	default:
+		return false
+	}
+	t.obj = nil
+	t.valuePtr = reflect.Value{}
+	t.setLifecycle(BeanAllocated)
+	return true
+}
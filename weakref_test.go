@@ -0,0 +1,161 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package glue_test
+
+import (
+	"github.com/codeallergy/glue"
+	"github.com/stretchr/testify/require"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+var bigModelClass = reflect.TypeOf((*bigModel)(nil))
+
+type bigModel struct {
+	weights []byte
+	reasons []glue.CloseReason
+}
+
+func (t *bigModel) DestroyWithReason(reason glue.CloseReason) error {
+	t.reasons = append(t.reasons, reason)
+	return nil
+}
+
+type bigModelFactory struct {
+	produced int
+}
+
+func (t *bigModelFactory) Object() (interface{}, error) {
+	t.produced++
+	return &bigModel{weights: make([]byte, 1024)}, nil
+}
+
+func (t *bigModelFactory) ObjectType() reflect.Type {
+	return bigModelClass
+}
+
+func (t *bigModelFactory) ObjectName() string {
+	return ""
+}
+
+func (t *bigModelFactory) Singleton() bool {
+	return true
+}
+
+type weakModelHolder struct {
+	Model *glue.WeakRef[*bigModel] `inject`
+}
+
+type strongModelHolder struct {
+	Model *bigModel `inject`
+}
+
+func TestWeakRef(t *testing.T) {
+
+	factory := &bigModelFactory{}
+	holder := &weakModelHolder{}
+
+	ctx, err := glue.New(
+		glue.Lazy(factory),
+		holder,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	require.True(t, holder.Model.Bound())
+	require.Equal(t, 0, factory.produced)
+
+	model, err := holder.Model.Get()
+	require.NoError(t, err)
+	require.NotNil(t, model)
+	require.Equal(t, 1, factory.produced)
+
+	again, err := holder.Model.Get()
+	require.NoError(t, err)
+	require.True(t, model == again)
+	require.Equal(t, 1, factory.produced)
+
+	require.Equal(t, 1, ctx.ReleaseWeak())
+	require.Equal(t, 0, ctx.ReleaseWeak())
+	require.Equal(t, []glue.CloseReason{glue.ReleaseWeakReason}, model.reasons)
+
+	model, err = holder.Model.Get()
+	require.NoError(t, err)
+	require.NotNil(t, model)
+	require.Equal(t, 2, factory.produced)
+}
+
+func TestWeakRefConcurrentRelease(t *testing.T) {
+
+	holder := &weakModelHolder{}
+
+	ctx, err := glue.New(
+		glue.Lazy(&bigModelFactory{}),
+		holder,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			model, err := holder.Model.Get()
+			require.NoError(t, err)
+			require.NotNil(t, model)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			ctx.ReleaseWeak()
+		}
+	}()
+	wg.Wait()
+}
+
+func TestWeakRefPinned(t *testing.T) {
+
+	factory := &bigModelFactory{}
+	holder := &weakModelHolder{}
+	strong := &strongModelHolder{}
+
+	ctx, err := glue.New(
+		factory,
+		holder,
+		strong,
+	)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	model, err := holder.Model.Get()
+	require.NoError(t, err)
+	require.True(t, model == strong.Model)
+
+	require.Equal(t, 0, ctx.ReleaseWeak())
+	require.Equal(t, 1, factory.produced)
+}
+
+func TestWeakRefMissing(t *testing.T) {
+
+	_, err := glue.New(
+		&weakModelHolder{},
+	)
+	require.Error(t, err)
+
+	holder := &struct {
+		Model *glue.WeakRef[*bigModel] `inject:"optional"`
+	}{}
+
+	ctx, err := glue.New(holder)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	_, err = holder.Model.Get()
+	require.Error(t, err)
+}